@@ -2,10 +2,42 @@ package sabre
 
 import (
 	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
 )
 
 var nilValue = Nil{}
 
+var (
+	// stringEscapes maps characters to the escape sequences used for them
+	// when printing strings (reverse of escapeMap).
+	stringEscapes = map[rune]rune{
+		'"':  '"',
+		'\\': '\\',
+		'\n': 'n',
+		'\t': 't',
+		'\a': 'a',
+		'\r': 'r',
+		'\b': 'b',
+		'\f': 'f',
+		'\v': 'v',
+	}
+
+	// charNames maps characters to the names used for them in character
+	// literals (reverse of charLiterals).
+	charNames = map[rune]string{
+		'\t': "tab",
+		' ':  "space",
+		'\n': "newline",
+		'\r': "return",
+		'\b': "backspace",
+		'\f': "formfeed",
+	}
+)
+
 // Nil represents a nil value.
 type Nil struct{}
 
@@ -29,7 +61,28 @@ type Float64 float64
 // Eval returns the underlying value.
 func (f64 Float64) Eval(_ Scope) (Value, error) { return f64, nil }
 
-func (f64 Float64) String() string { return fmt.Sprintf("%f", f64) }
+// String returns the shortest representation of the number which reads back
+// as the same Float64 (i.e., always contains a decimal point or exponent).
+// Infinities and NaN are represented as ##Inf, ##-Inf and ##NaN.
+func (f64 Float64) String() string {
+	f := float64(f64)
+	switch {
+	case math.IsNaN(f):
+		return "##NaN"
+
+	case math.IsInf(f, 1):
+		return "##Inf"
+
+	case math.IsInf(f, -1):
+		return "##-Inf"
+	}
+
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".e") {
+		s += ".0"
+	}
+	return s
+}
 
 // Int64 represents integer values represented using decimal, octal, radix
 // and hexadecimal formats.
@@ -48,7 +101,31 @@ type String string
 // Eval returns the underlying value.
 func (se String) Eval(_ Scope) (Value, error) { return se, nil }
 
-func (se String) String() string { return fmt.Sprintf("\"%s\"", string(se)) }
+// String returns the double-quoted string with special characters escaped
+// so that it reads back as the same String. Control characters without a
+// single character escape are written as unicode escapes (e.g., \u001B).
+func (se String) String() string {
+	var sb strings.Builder
+	sb.WriteByte('"')
+	for _, r := range string(se) {
+		if esc, found := stringEscapes[r]; found {
+			sb.WriteByte('\\')
+			sb.WriteRune(esc)
+			continue
+		}
+
+		if unicode.IsControl(r) {
+			fmt.Fprintf(&sb, "\\u%04X", r)
+			continue
+		}
+		sb.WriteRune(r)
+	}
+	sb.WriteByte('"')
+	return sb.String()
+}
+
+// Size returns the number of characters (runes) in the string.
+func (se String) Size() int { return utf8.RuneCountInString(string(se)) }
 
 // Character represents a character literal.  For example, \a, \b, \1, \∂ etc
 // are valid character literals. In addition, special literals like \newline,
@@ -58,7 +135,20 @@ type Character rune
 // Eval returns the underlying value.
 func (char Character) Eval(_ Scope) (Value, error) { return char, nil }
 
-func (char Character) String() string { return fmt.Sprintf("\\%c", rune(char)) }
+// String returns the character literal. Special characters are printed using
+// their names (e.g., \newline) and other non-printable characters using the
+// unicode notation (e.g., \u0000).
+func (char Character) String() string {
+	if name, found := charNames[rune(char)]; found {
+		return "\\" + name
+	}
+
+	if !unicode.IsPrint(rune(char)) {
+		return fmt.Sprintf("\\u%04X", rune(char))
+	}
+
+	return fmt.Sprintf("\\%c", rune(char))
+}
 
 // Keyword represents a keyword literal.
 type Keyword string
@@ -68,11 +158,29 @@ func (kw Keyword) Eval(_ Scope) (Value, error) { return kw, nil }
 
 func (kw Keyword) String() string { return fmt.Sprintf(":%s", string(kw)) }
 
+// Invoke of a keyword looks up the keyword in the map or record. (:key m)
+// returns the value of the key or nil and (:key m default) returns default
+// if the key is not present or if m is not a map or record.
+func (kw Keyword) Invoke(scope Scope, args ...Value) (Value, error) {
+	vals, err := evalValueList(scope, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(vals) != 1 && len(vals) != 2 {
+		return nil, fmt.Errorf("call requires 1 or 2 argument(s), got %d", len(vals))
+	}
+
+	m, _ := vals[0].(getter)
+	return lookup(m, append([]Value{kw}, vals[1:]...)), nil
+}
+
 // Symbol represents a name given to a value in memory.
 type Symbol struct {
 	Position
 
 	Value string
+	meta  *HashMap
 }
 
 // Eval returns the underlying value.
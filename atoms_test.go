@@ -1,6 +1,7 @@
 package sabre_test
 
 import (
+	"math"
 	"reflect"
 	"strings"
 	"testing"
@@ -105,11 +106,35 @@ func TestFloat64_String(t *testing.T) {
 	executeStringTestCase(t, []stringTestCase{
 		{
 			value: sabre.Float64(10.3),
-			want:  "10.300000",
+			want:  "10.3",
 		},
 		{
 			value: sabre.Float64(-10.3),
-			want:  "-10.300000",
+			want:  "-10.3",
+		},
+		{
+			value: sabre.Float64(10),
+			want:  "10.0",
+		},
+		{
+			value: sabre.Float64(1e-9),
+			want:  "1e-09",
+		},
+		{
+			value: sabre.Float64(1.5e300),
+			want:  "1.5e+300",
+		},
+		{
+			value: sabre.Float64(math.Inf(1)),
+			want:  "##Inf",
+		},
+		{
+			value: sabre.Float64(math.Inf(-1)),
+			want:  "##-Inf",
+		},
+		{
+			value: sabre.Float64(math.NaN()),
+			want:  "##NaN",
 		},
 	})
 }
@@ -151,6 +176,18 @@ func TestCharacter_String(t *testing.T) {
 			value: sabre.Character('a'),
 			want:  "\\a",
 		},
+		{
+			value: sabre.Character('\n'),
+			want:  "\\newline",
+		},
+		{
+			value: sabre.Character(' '),
+			want:  "\\space",
+		},
+		{
+			value: sabre.Character(0),
+			want:  "\\u0000",
+		},
 	})
 }
 
@@ -162,7 +199,11 @@ func TestString_String(t *testing.T) {
 		},
 		{
 			value: sabre.String("hello\tworld"),
-			want: `"hello	world"`,
+			want:  `"hello\tworld"`,
+		},
+		{
+			value: sabre.String("say \"hi\"\n\\"),
+			want:  `"say \"hi\"\n\\"`,
 		},
 	})
 }
@@ -0,0 +1,33 @@
+package sabre
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// BigInt represents arbitrary precision integers. BigInt literals are
+// written with 'N' suffix (e.g., 123N, 0xFFN).
+type BigInt struct {
+	Int *big.Int
+}
+
+// Eval returns the underlying value.
+func (bi BigInt) Eval(_ Scope) (Value, error) { return bi, nil }
+
+// String returns the decimal representation of the number with 'N' suffix
+// so that it reads back as BigInt.
+func (bi BigInt) String() string { return bi.Int.String() + "N" }
+
+// parseBigInt parses integer literal with 'N' suffix. Decimal, octal and
+// hexadecimal notations are supported.
+func parseBigInt(numStr string) (BigInt, error) {
+	repr := strings.TrimSuffix(numStr, "N")
+
+	i, ok := new(big.Int).SetString(repr, 0)
+	if !ok {
+		return BigInt{}, fmt.Errorf("illegal number format '%s'", numStr)
+	}
+
+	return BigInt{Int: i}, nil
+}
@@ -0,0 +1,823 @@
+package sabre
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+)
+
+// compileBytecode compiles the form into the body of a function prototype
+// which takes no arguments.
+func compileBytecode(scope Scope, form Value) (*funcProto, error) {
+	bc := &bcCompiler{
+		scope: scope,
+		proto: &funcProto{name: "<main>"},
+	}
+
+	if err := bc.compile(form, true); err != nil {
+		return nil, err
+	}
+	bc.emit(opReturn, 0)
+
+	return bc.proto, nil
+}
+
+type opcode uint8
+
+const (
+	opConst opcode = iota
+	opLoadLocal
+	opStoreLocal
+	opLoadUpval
+	opLoadGlobal
+	opDef
+	opPop
+	opJump
+	opJumpIfFalse
+	opMacroCheck
+	opCall
+	opTailCall
+	opReturn
+	opClosure
+	opList
+	opVector
+	opSet
+	opQuotedSet
+	opMap
+	opMeta
+	opThrow
+	opGo
+	opFuture
+	opDelay
+	opEval
+)
+
+var opNames = [...]string{
+	opConst:       "CONST",
+	opLoadLocal:   "LOAD_LOCAL",
+	opStoreLocal:  "STORE_LOCAL",
+	opLoadUpval:   "LOAD_UPVAL",
+	opLoadGlobal:  "LOAD_GLOBAL",
+	opDef:         "DEF",
+	opPop:         "POP",
+	opJump:        "JUMP",
+	opJumpIfFalse: "JUMP_IF_FALSE",
+	opMacroCheck:  "MACRO_CHECK",
+	opCall:        "CALL",
+	opTailCall:    "TAIL_CALL",
+	opReturn:      "RETURN",
+	opClosure:     "CLOSURE",
+	opList:        "LIST",
+	opVector:      "VECTOR",
+	opSet:         "SET",
+	opQuotedSet:   "QUOTED_SET",
+	opMap:         "MAP",
+	opMeta:        "META",
+	opThrow:       "THROW",
+	opGo:          "GO",
+	opFuture:      "FUTURE",
+	opDelay:       "DELAY",
+	opEval:        "EVAL",
+}
+
+func (op opcode) String() string { return opNames[op] }
+
+const maxOperand = 1<<24 - 1
+
+// instr is a single instruction. Lower 8 bits hold the opcode and the upper
+// 24 bits hold the operand.
+type instr uint32
+
+func (in instr) op() opcode { return opcode(in & 0xff) }
+
+func (in instr) arg() int { return int(in >> 8) }
+
+// funcProto is the compiled form of a single function method.
+type funcProto struct {
+	name     string
+	args     []string
+	variadic bool
+	nlocals  int
+	code     []instr
+	consts   []Value
+	fns      []*fnDef
+	sites    []*site
+	upvals   []upvalDesc
+	locals   []string
+}
+
+// fnDef is the compiled form of a fn* form with one prototype per arity.
+type fnDef struct {
+	name    string
+	methods []*funcProto
+}
+
+// upvalDesc describes where the value of an upvalue is captured from when
+// a closure is created.
+type upvalDesc struct {
+	name      string
+	fromLocal bool
+	index     int
+}
+
+// site holds information about an invocation or a form evaluated directly
+// (EVAL) required during execution.
+type site struct {
+	form   Value
+	args   []Value
+	end    int
+	locals []localVar
+	pos    Position
+}
+
+// wrap returns the error annotated with the position of the site.
+func (s *site) wrap(err error) error {
+	return EvalError{
+		Position: s.pos,
+		Cause:    err,
+		Form:     s.form,
+	}
+}
+
+type bcCompiler struct {
+	scope  Scope
+	parent *bcCompiler
+	proto  *funcProto
+	vars   []localVar
+
+	// pos is the position of the innermost form being compiled that has
+	// one. Sites for forms without a position (e.g., generated by the
+	// reader or a macro) report this instead.
+	pos Position
+}
+
+func (bc *bcCompiler) compile(form Value, tail bool) error {
+	var err error
+
+	if pos := getPosition(form); pos != (Position{}) {
+		outer := bc.pos
+		bc.pos = pos
+		defer func() { bc.pos = outer }()
+	}
+
+	switch v := form.(type) {
+	case Nil, Bool, Int64, BigInt, Float64, String, Character, Keyword, Regex, Time, Duration, UUID:
+		err = bc.emitConst(v)
+
+	case Symbol:
+		err = bc.compileSymbol(v)
+
+	case Module:
+		err = bc.compileBody(v, tail)
+
+	case Vector:
+		err = bc.compileCollection(opVector, v.Values, v.meta)
+
+	case Set:
+		err = bc.compileCollection(opSet, v.Values, v.meta)
+
+	case *HashMap:
+		err = bc.compileMap(v)
+
+	case *List:
+		err = bc.compileInvocation(v, tail)
+
+	default:
+		err = bc.emitEval(form)
+	}
+
+	if err != nil {
+		if _, ok := err.(EvalError); ok {
+			return err
+		}
+
+		return EvalError{
+			Cause:    err,
+			Position: getPosition(form),
+			Form:     form,
+		}
+	}
+
+	return nil
+}
+
+func (bc *bcCompiler) compileSymbol(sym Symbol) error {
+	if slot, found := bc.resolveLocal(sym.Value); found {
+		bc.emit(opLoadLocal, slot)
+		return nil
+	}
+
+	if idx, found := bc.resolveUpval(sym.Value); found {
+		bc.emit(opLoadUpval, idx)
+		return nil
+	}
+
+	k, err := bc.addConst(sym)
+	if err != nil {
+		return err
+	}
+	bc.emit(opLoadGlobal, k)
+	return nil
+}
+
+func (bc *bcCompiler) compileBody(forms []Value, tail bool) error {
+	if len(forms) == 0 {
+		return bc.emitConst(Nil{})
+	}
+
+	for i, form := range forms {
+		if i > 0 {
+			bc.emit(opPop, 0)
+		}
+
+		if err := bc.compile(form, tail && i == len(forms)-1); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (bc *bcCompiler) compileItems(op opcode, forms []Value) error {
+	for _, form := range forms {
+		if err := bc.compile(form, false); err != nil {
+			return err
+		}
+	}
+
+	return bc.emitN(op, len(forms))
+}
+
+// compileSite compiles the forms followed by an instruction which consumes
+// their values and may fail. The instruction refers to a site so that errors
+// can be reported with the position of 'form'.
+func (bc *bcCompiler) compileSite(op opcode, form Value, forms []Value) error {
+	for _, f := range forms {
+		if err := bc.compile(f, false); err != nil {
+			return err
+		}
+	}
+
+	return bc.emitSite(op, form, forms)
+}
+
+// compileCollection compiles a collection literal. Metadata of the literal
+// (if any) is attached to the resulting collection.
+func (bc *bcCompiler) compileCollection(op opcode, forms []Value, meta *HashMap) error {
+	if err := bc.compileItems(op, forms); err != nil {
+		return err
+	}
+
+	return bc.emitMeta(meta)
+}
+
+// compileMap compiles a map literal. Unlike other collections, creating the
+// map fails if the keys evaluate to equal values.
+func (bc *bcCompiler) compileMap(hm *HashMap) error {
+	if err := bc.compileSite(opMap, hm, hm.kvs()); err != nil {
+		return err
+	}
+
+	return bc.emitMeta(hm.meta)
+}
+
+func (bc *bcCompiler) emitMeta(meta *HashMap) error {
+	if meta == nil {
+		return nil
+	}
+
+	k, err := bc.addConst(meta)
+	if err != nil {
+		return err
+	}
+	bc.emit(opMeta, k)
+	return nil
+}
+
+func (bc *bcCompiler) compileInvocation(lf *List, tail bool) error {
+	if lf.Size() == 0 {
+		bc.emit(opList, 0)
+		return nil
+	}
+
+	if sym, isSymbol := lf.Values[0].(Symbol); isSymbol && getSpecial(sym) != nil {
+		return bc.compileSpecial(lf, sym.Value, tail)
+	}
+
+	if err := bc.compile(lf.Values[0], false); err != nil {
+		return err
+	}
+
+	s, err := bc.addSite(&site{form: lf, args: lf.Values[1:]})
+	if err != nil {
+		return err
+	}
+	bc.emit(opMacroCheck, s)
+
+	for _, arg := range lf.Values[1:] {
+		if err := bc.compile(arg, false); err != nil {
+			return err
+		}
+	}
+
+	if tail {
+		bc.emit(opTailCall, s)
+	} else {
+		bc.emit(opCall, s)
+	}
+	bc.proto.sites[s].end = len(bc.proto.code)
+
+	return nil
+}
+
+func (bc *bcCompiler) compileSpecial(lf *List, name string, tail bool) error {
+	args := lf.Values[1:]
+
+	switch name {
+	case "quote":
+		if err := verifyArgCount([]int{1}, args); err != nil {
+			return err
+		}
+		return bc.emitConst(args[0])
+
+	case "syntax-quote":
+		if err := verifyArgCount([]int{1}, args); err != nil {
+			return err
+		}
+		return bc.compileSyntaxQuote(args[0])
+
+	case "fn*", "λ":
+		return bc.compileLambda(args)
+
+	case "let*":
+		return bc.compileLet(args, tail)
+
+	case "def":
+		return bc.compileDef(args)
+
+	case "if":
+		return bc.compileIf(args, tail)
+
+	case "do":
+		return bc.compileBody(args, tail)
+
+	case "throw":
+		return bc.compileSite(opThrow, lf, args)
+
+	case "go", "future", "delay":
+		if err := bc.compileThunk(args); err != nil {
+			return err
+		}
+
+		ops := map[string]opcode{"go": opGo, "future": opFuture, "delay": opDelay}
+		bc.emit(ops[name], 0)
+		return nil
+
+	default:
+		analyzed, err := lf.analyze(bc.scope)
+		if err != nil {
+			return err
+		}
+		return bc.emitEval(analyzed)
+	}
+}
+
+// compileLambda compiles (fn* name? [arg*] expr*) or (fn* name? ([arg*]
+// expr*)+) forms.
+func (bc *bcCompiler) compileLambda(args []Value) error {
+	if len(args) < 1 {
+		return fmt.Errorf("insufficient args (%d) for 'fn'", len(args))
+	}
+
+	def := &fnDef{}
+	nextIndex := 0
+
+	name, isName := args[nextIndex].(Symbol)
+	if isName {
+		def.name = name.String()
+		nextIndex++
+	}
+
+	if _, isList := args[nextIndex].(*List); isList {
+		for _, arg := range args[nextIndex:] {
+			spec, isList := arg.(*List)
+			if !isList {
+				return fmt.Errorf("expected arg to be list, not %s",
+					reflect.TypeOf(arg))
+			}
+
+			proto, err := bc.compileMethod(def.name, spec.Values)
+			if err != nil {
+				return err
+			}
+			def.methods = append(def.methods, proto)
+		}
+	} else {
+		proto, err := bc.compileMethod(def.name, args[nextIndex:])
+		if err != nil {
+			return err
+		}
+		def.methods = append(def.methods, proto)
+	}
+
+	return bc.emitClosure(def)
+}
+
+func (bc *bcCompiler) compileMethod(name string, spec []Value) (*funcProto, error) {
+	if len(spec) < 1 {
+		return nil, fmt.Errorf("insufficient args (%d) for 'fn'", len(spec))
+	}
+
+	var fn Fn
+	if err := fn.parseArgSpec(spec[0]); err != nil {
+		return nil, err
+	}
+
+	child := bc.child(name)
+	child.proto.args = fn.Args
+	child.proto.variadic = fn.Variadic
+	for _, arg := range fn.Args {
+		child.declare(arg)
+	}
+
+	if err := child.compileBody(spec[1:], true); err != nil {
+		return nil, err
+	}
+	child.emit(opReturn, 0)
+
+	return child.proto, nil
+}
+
+// compileThunk compiles the forms as the body of a function that takes no
+// arguments. This is used by go, future and delay.
+func (bc *bcCompiler) compileThunk(forms []Value) error {
+	child := bc.child("")
+	if err := child.compileBody(forms, true); err != nil {
+		return err
+	}
+	child.emit(opReturn, 0)
+
+	return bc.emitClosure(&fnDef{methods: []*funcProto{child.proto}})
+}
+
+// compileLet compiles (let* [binding*] expr*) form.
+func (bc *bcCompiler) compileLet(args []Value, tail bool) error {
+	if len(args) < 1 {
+		return fmt.Errorf("call requires at-least bindings argument")
+	}
+
+	vec, isVector := args[0].(Vector)
+	if !isVector {
+		return fmt.Errorf(
+			"first argument to let must be bindings vector, not %v",
+			reflect.TypeOf(args[0]),
+		)
+	}
+
+	if len(vec.Values)%2 != 0 {
+		return fmt.Errorf("bindings must contain event forms")
+	}
+
+	visible := len(bc.vars)
+	defer func() { bc.vars = bc.vars[:visible] }()
+
+	for i := 0; i < len(vec.Values); i += 2 {
+		sym, isSymbol := vec.Values[i].(Symbol)
+		if !isSymbol {
+			return fmt.Errorf(
+				"item at %d must be symbol, not %s",
+				i, vec.Values[i],
+			)
+		}
+
+		if err := bc.compile(vec.Values[i+1], false); err != nil {
+			return err
+		}
+		bc.emit(opStoreLocal, bc.declare(sym.Value))
+	}
+
+	return bc.compileBody(args[1:], tail)
+}
+
+func (bc *bcCompiler) compileDef(args []Value) error {
+	if err := verifyArgCount([]int{2}, args); err != nil {
+		return err
+	}
+
+	sym, isSymbol := args[0].(Symbol)
+	if !isSymbol {
+		return fmt.Errorf("first argument must be symbol, not '%v'",
+			reflect.TypeOf(args[0]))
+	}
+
+	if err := bc.compile(args[1], false); err != nil {
+		return err
+	}
+
+	k, err := bc.addConst(sym)
+	if err != nil {
+		return err
+	}
+	bc.emit(opDef, k)
+	return nil
+}
+
+func (bc *bcCompiler) compileIf(args []Value, tail bool) error {
+	if err := verifyArgCount([]int{2, 3}, args); err != nil {
+		return err
+	}
+
+	if err := bc.compile(args[0], false); err != nil {
+		return err
+	}
+	jumpToElse := bc.emit(opJumpIfFalse, 0)
+
+	if err := bc.compile(args[1], tail); err != nil {
+		return err
+	}
+	jumpToEnd := bc.emit(opJump, 0)
+
+	bc.patch(jumpToElse, len(bc.proto.code))
+	if len(args) == 3 {
+		if err := bc.compile(args[2], tail); err != nil {
+			return err
+		}
+	} else if err := bc.emitConst(Nil{}); err != nil {
+		return err
+	}
+	bc.patch(jumpToEnd, len(bc.proto.code))
+
+	return nil
+}
+
+// compileSyntaxQuote compiles the syntax-quoted form. Only the unquoted
+// forms within are evaluated.
+func (bc *bcCompiler) compileSyntaxQuote(form Value) error {
+	var vals []Value
+	var op opcode
+
+	switch v := form.(type) {
+	case *List:
+		if isUnquote(v.Values) {
+			if err := verifyArgCount([]int{1}, v.Values[1:]); err != nil {
+				return err
+			}
+			return bc.compile(v.Values[1], false)
+		}
+		vals, op = v.Values, opList
+
+	case Set:
+		vals, op = v.Values, opQuotedSet
+
+	case Vector:
+		vals, op = v.Values, opVector
+
+	case *HashMap:
+		vals, op = v.kvs(), opMap
+
+	default:
+		return bc.emitConst(form)
+	}
+
+	for _, v := range vals {
+		if err := bc.compileSyntaxQuote(v); err != nil {
+			return err
+		}
+	}
+
+	if op == opMap {
+		return bc.emitSite(op, form, vals)
+	}
+
+	return bc.emitN(op, len(vals))
+}
+
+func (bc *bcCompiler) child(name string) *bcCompiler {
+	child := &bcCompiler{
+		scope:  bc.scope,
+		parent: bc,
+		proto:  &funcProto{name: name},
+		pos:    bc.pos,
+	}
+
+	// all the local bindings visible at the point of definition are
+	// captured so that they remain accessible to Go functions and forms
+	// evaluated directly (See vmScope).
+	seen := map[string]bool{}
+	for i := len(bc.vars) - 1; i >= 0; i-- {
+		v := bc.vars[i]
+		if !seen[v.name] {
+			seen[v.name] = true
+			child.proto.upvals = append(child.proto.upvals, upvalDesc{
+				name:      v.name,
+				fromLocal: true,
+				index:     v.slot,
+			})
+		}
+	}
+
+	for i, up := range bc.proto.upvals {
+		if !seen[up.name] {
+			seen[up.name] = true
+			child.proto.upvals = append(child.proto.upvals, upvalDesc{
+				name:  up.name,
+				index: i,
+			})
+		}
+	}
+
+	return child
+}
+
+func (bc *bcCompiler) declare(name string) int {
+	slot := bc.proto.nlocals
+	bc.proto.nlocals++
+	bc.proto.locals = append(bc.proto.locals, name)
+	bc.vars = append(bc.vars, localVar{name: name, slot: slot})
+	return slot
+}
+
+func (bc *bcCompiler) resolveLocal(name string) (int, bool) {
+	for i := len(bc.vars) - 1; i >= 0; i-- {
+		if bc.vars[i].name == name {
+			return bc.vars[i].slot, true
+		}
+	}
+
+	return 0, false
+}
+
+func (bc *bcCompiler) resolveUpval(name string) (int, bool) {
+	for i, up := range bc.proto.upvals {
+		if up.name == name {
+			return i, true
+		}
+	}
+
+	return 0, false
+}
+
+func (bc *bcCompiler) emit(op opcode, arg int) int {
+	bc.proto.code = append(bc.proto.code, instr(op)|instr(arg)<<8)
+	return len(bc.proto.code) - 1
+}
+
+func (bc *bcCompiler) emitN(op opcode, n int) error {
+	if n > maxOperand {
+		return fmt.Errorf("too many items (%d)", n)
+	}
+
+	bc.emit(op, n)
+	return nil
+}
+
+func (bc *bcCompiler) patch(idx, arg int) {
+	bc.proto.code[idx] = instr(bc.proto.code[idx].op()) | instr(arg)<<8
+}
+
+func (bc *bcCompiler) emitConst(v Value) error {
+	k, err := bc.addConst(v)
+	if err != nil {
+		return err
+	}
+
+	bc.emit(opConst, k)
+	return nil
+}
+
+func (bc *bcCompiler) emitClosure(def *fnDef) error {
+	if len(bc.proto.fns) >= maxOperand {
+		return fmt.Errorf("too many functions")
+	}
+
+	bc.proto.fns = append(bc.proto.fns, def)
+	bc.emit(opClosure, len(bc.proto.fns)-1)
+	return nil
+}
+
+func (bc *bcCompiler) emitEval(form Value) error {
+	return bc.emitSite(opEval, form, nil)
+}
+
+func (bc *bcCompiler) emitSite(op opcode, form Value, args []Value) error {
+	s, err := bc.addSite(&site{form: form, args: args})
+	if err != nil {
+		return err
+	}
+
+	bc.emit(op, s)
+	return nil
+}
+
+func (bc *bcCompiler) addConst(v Value) (int, error) {
+	comparable := reflect.TypeOf(v).Comparable()
+	for i, c := range bc.proto.consts {
+		if comparable && reflect.TypeOf(c) == reflect.TypeOf(v) && c == v {
+			return i, nil
+		}
+	}
+
+	if len(bc.proto.consts) >= maxOperand {
+		return 0, fmt.Errorf("too many constants")
+	}
+
+	bc.proto.consts = append(bc.proto.consts, v)
+	return len(bc.proto.consts) - 1, nil
+}
+
+func (bc *bcCompiler) addSite(s *site) (int, error) {
+	if len(bc.proto.sites) >= maxOperand {
+		return 0, fmt.Errorf("too many invocations")
+	}
+
+	s.locals = append([]localVar(nil), bc.vars...)
+	if s.pos = getPosition(s.form); s.pos == (Position{}) {
+		s.pos = bc.pos
+	}
+	bc.proto.sites = append(bc.proto.sites, s)
+	return len(bc.proto.sites) - 1, nil
+}
+
+// disassemble writes human readable listing of the instructions of the
+// prototype and all the functions defined within.
+func disassemble(w io.Writer, proto *funcProto) error {
+	name := proto.name
+	if name == "" {
+		name = "<anonymous>"
+	}
+
+	var args []string
+	if proto.variadic {
+		argc := len(proto.args)
+		args = append(append(args, proto.args[:argc-1]...), "&", proto.args[argc-1])
+	} else {
+		args = proto.args
+	}
+
+	_, err := fmt.Fprintf(w, "fn %s [%s] locals=%d upvals=%d consts=%d\n",
+		name, strings.Join(args, " "), proto.nlocals, len(proto.upvals), len(proto.consts))
+	if err != nil {
+		return err
+	}
+
+	for ip, in := range proto.code {
+		line := fmt.Sprintf("  %04d  %-14s", ip, in.op())
+
+		switch in.op() {
+		case opReturn, opPop, opGo, opFuture, opDelay:
+
+		default:
+			line += fmt.Sprintf("%4d", in.arg())
+			if comment := proto.describe(in); comment != "" {
+				line += "  ; " + comment
+			}
+		}
+
+		if _, err := fmt.Fprintln(w, strings.TrimRight(line, " ")); err != nil {
+			return err
+		}
+	}
+
+	for _, def := range proto.fns {
+		for _, method := range def.methods {
+			if _, err := fmt.Fprintln(w); err != nil {
+				return err
+			}
+
+			if err := disassemble(w, method); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func (proto *funcProto) describe(in instr) string {
+	arg := in.arg()
+
+	switch in.op() {
+	case opConst, opLoadGlobal, opDef, opMeta:
+		return proto.consts[arg].String()
+
+	case opLoadLocal, opStoreLocal:
+		return proto.locals[arg]
+
+	case opLoadUpval:
+		return proto.upvals[arg].name
+
+	case opCall, opTailCall, opMacroCheck, opEval, opMap, opThrow:
+		return proto.sites[arg].form.String()
+
+	case opClosure:
+		name := proto.fns[arg].name
+		if name == "" {
+			name = "<anonymous>"
+		}
+		return name
+
+	default:
+		return ""
+	}
+}
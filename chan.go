@@ -0,0 +1,201 @@
+package sabre
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var valueType = reflect.TypeOf((*Value)(nil)).Elem()
+
+// NewChan returns a new channel of Values with given buffer size. Buffer
+// size of 0 creates an unbuffered channel.
+func NewChan(size int) *Chan {
+	return &Chan{rv: reflect.MakeChan(reflect.ChanOf(reflect.BothDir, valueType), size)}
+}
+
+// Chan represents a Go channel. Values put into the channel are converted
+// to the element type of the channel and values taken from the channel are
+// converted back using ValueOf. Any Go channel can be wrapped using ValueOf.
+type Chan struct {
+	rv reflect.Value
+
+	// err is set before the channel is closed if the producer failed.
+	err error
+}
+
+// Eval returns the channel itself.
+func (ch *Chan) Eval(_ Scope) (Value, error) { return ch, nil }
+
+func (ch *Chan) String() string {
+	return fmt.Sprintf("Chan{%s}", ch.rv.Type())
+}
+
+// Put sends the value into the channel and blocks until the value is
+// accepted or the context is cancelled. Returns false if the channel is
+// closed.
+func (ch *Chan) Put(ctx context.Context, v Value) (sent bool, err error) {
+	if ch.rv.Type().ChanDir()&reflect.SendDir == 0 {
+		return false, errors.New("cannot put into receive-only channel")
+	}
+
+	rv, err := convertValue(v, ch.rv.Type().Elem())
+	if err != nil {
+		return false, err
+	}
+
+	defer func() {
+		if recover() != nil {
+			sent, err = false, nil
+		}
+	}()
+
+	chosen, _, _ := reflect.Select([]reflect.SelectCase{
+		{Dir: reflect.SelectSend, Chan: ch.rv, Send: rv},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+	})
+	if chosen == 1 {
+		return false, ctx.Err()
+	}
+
+	return true, nil
+}
+
+// Take receives a value from the channel and blocks until a value is
+// available or the context is cancelled. Returns nil once the channel is
+// closed and drained, or the error of the producer if it failed.
+func (ch *Chan) Take(ctx context.Context) (Value, error) {
+	if ch.rv.Type().ChanDir()&reflect.RecvDir == 0 {
+		return nil, errors.New("cannot take from send-only channel")
+	}
+
+	chosen, rv, ok := reflect.Select([]reflect.SelectCase{
+		{Dir: reflect.SelectRecv, Chan: ch.rv},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+	})
+	if chosen == 1 {
+		return nil, ctx.Err()
+	}
+
+	return ch.received(rv, ok)
+}
+
+// Close closes the channel. Closing an already closed channel is an error.
+func (ch *Chan) Close() (err error) {
+	if ch.rv.Type().ChanDir()&reflect.SendDir == 0 {
+		return errors.New("cannot close receive-only channel")
+	}
+
+	defer func() {
+		if recover() != nil {
+			err = errors.New("close of closed channel")
+		}
+	}()
+
+	ch.rv.Close()
+	return nil
+}
+
+func (ch *Chan) closeWithErr(err error) {
+	ch.err = err
+	ch.rv.Close()
+}
+
+func (ch *Chan) received(rv reflect.Value, ok bool) (Value, error) {
+	if !ok {
+		if ch.err != nil {
+			return nil, ch.err
+		}
+		return Nil{}, nil
+	}
+
+	return ValueOf(rv.Interface()), nil
+}
+
+// Alts performs a Go select over multiple channel operations and returns
+// after exactly one of them completes. Each operation must be a channel to
+// take from or a vector [channel value] to put into. Returns the value taken
+// (or whether the value was sent for puts) along with the channel of the
+// completed operation. If 'def' is not nil and no operation is ready, def is
+// returned immediately with a nil channel. Returns the context error if the
+// context is cancelled before any operation completes.
+func Alts(ctx context.Context, ops []Value, def Value) (_ Value, _ *Chan, err error) {
+	defer func() {
+		if recover() != nil {
+			err = errors.New("put on closed channel")
+		}
+	}()
+
+	if len(ops) == 0 && def == nil {
+		return nil, nil, errors.New("alts requires at-least one operation")
+	}
+
+	var chans []*Chan
+	var cases []reflect.SelectCase
+
+	for _, op := range ops {
+		switch v := op.(type) {
+		case *Chan:
+			if v.rv.Type().ChanDir()&reflect.RecvDir == 0 {
+				return nil, nil, errors.New("cannot take from send-only channel")
+			}
+
+			cases = append(cases, reflect.SelectCase{
+				Dir:  reflect.SelectRecv,
+				Chan: v.rv,
+			})
+			chans = append(chans, v)
+
+		case Vector:
+			ch, isChan := v.First().(*Chan)
+			if len(v.Values) != 2 || !isChan {
+				return nil, nil, errors.New("put operation must be a vector [channel value]")
+			}
+
+			if ch.rv.Type().ChanDir()&reflect.SendDir == 0 {
+				return nil, nil, errors.New("cannot put into receive-only channel")
+			}
+
+			rv, err := convertValue(v.Values[1], ch.rv.Type().Elem())
+			if err != nil {
+				return nil, nil, err
+			}
+
+			cases = append(cases, reflect.SelectCase{
+				Dir:  reflect.SelectSend,
+				Chan: ch.rv,
+				Send: rv,
+			})
+			chans = append(chans, ch)
+
+		default:
+			return nil, nil, fmt.Errorf("invalid channel operation of type '%s'",
+				reflect.TypeOf(op))
+		}
+	}
+
+	cases = append(cases, reflect.SelectCase{
+		Dir:  reflect.SelectRecv,
+		Chan: reflect.ValueOf(ctx.Done()),
+	})
+
+	if def != nil {
+		cases = append(cases, reflect.SelectCase{Dir: reflect.SelectDefault})
+	}
+
+	chosen, rv, ok := reflect.Select(cases)
+	if chosen == len(chans) {
+		return nil, nil, ctx.Err()
+	} else if chosen > len(chans) {
+		return def, nil, nil
+	}
+
+	ch := chans[chosen]
+	if cases[chosen].Dir == reflect.SelectSend {
+		return Bool(true), ch, nil
+	}
+
+	v, err := ch.received(rv, ok)
+	return v, ch, err
+}
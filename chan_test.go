@@ -0,0 +1,165 @@
+package sabre_test
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/spy16/sabre"
+)
+
+func TestChan(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("PutTake", func(t *testing.T) {
+		ch := sabre.NewChan(1)
+
+		sent, err := ch.Put(ctx, sabre.String("hello"))
+		if err != nil || !sent {
+			t.Fatalf("Put() got = (%t, %v), want (true, nil)", sent, err)
+		}
+
+		got, err := ch.Take(ctx)
+		if err != nil {
+			t.Fatalf("Take() unexpected error: %v", err)
+		}
+
+		if !reflect.DeepEqual(got, sabre.String("hello")) {
+			t.Errorf("Take() got = %v, want \"hello\"", got)
+		}
+	})
+
+	t.Run("Closed", func(t *testing.T) {
+		ch := sabre.NewChan(0)
+		if err := ch.Close(); err != nil {
+			t.Fatalf("Close() unexpected error: %v", err)
+		}
+
+		if err := ch.Close(); err == nil {
+			t.Errorf("Close() expecting error on closed channel")
+		}
+
+		sent, err := ch.Put(ctx, sabre.Int64(1))
+		if err != nil || sent {
+			t.Errorf("Put() got = (%t, %v), want (false, nil)", sent, err)
+		}
+
+		got, err := ch.Take(ctx)
+		if err != nil || got != (sabre.Nil{}) {
+			t.Errorf("Take() got = (%v, %v), want (nil, nil)", got, err)
+		}
+	})
+
+	t.Run("NativeChan", func(t *testing.T) {
+		native := make(chan int, 2)
+		ch, isChan := sabre.ValueOf(native).(*sabre.Chan)
+		if !isChan {
+			t.Fatalf("ValueOf() expected to return *Chan")
+		}
+
+		native <- 10
+		got, err := ch.Take(ctx)
+		if err != nil || got != sabre.Int64(10) {
+			t.Errorf("Take() got = (%v, %v), want (10, nil)", got, err)
+		}
+
+		if _, err := ch.Put(ctx, sabre.Int64(20)); err != nil {
+			t.Fatalf("Put() unexpected error: %v", err)
+		}
+
+		if v := <-native; v != 20 {
+			t.Errorf("native channel got = %d, want 20", v)
+		}
+
+		if _, err := ch.Put(ctx, sabre.String("hello")); err == nil {
+			t.Errorf("Put() expecting conversion error")
+		}
+	})
+
+	t.Run("Cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		ch := sabre.NewChan(0)
+		if _, err := ch.Take(ctx); err != context.Canceled {
+			t.Errorf("Take() error = %v, want context.Canceled", err)
+		}
+
+		if _, err := ch.Put(ctx, sabre.Int64(1)); err != context.Canceled {
+			t.Errorf("Put() error = %v, want context.Canceled", err)
+		}
+
+		if _, _, err := sabre.Alts(ctx, []sabre.Value{ch}, nil); err != context.Canceled {
+			t.Errorf("Alts() error = %v, want context.Canceled", err)
+		}
+	})
+
+	t.Run("Direction", func(t *testing.T) {
+		var recvOnly <-chan int = make(chan int)
+		ch := sabre.ValueOf(recvOnly).(*sabre.Chan)
+
+		if _, err := ch.Put(ctx, sabre.Int64(1)); err == nil {
+			t.Errorf("Put() expecting error on receive-only channel")
+		}
+
+		if err := ch.Close(); err == nil {
+			t.Errorf("Close() expecting error on receive-only channel")
+		}
+	})
+}
+
+func TestAlts(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+
+	t.Run("Take", func(t *testing.T) {
+		ch1, ch2 := sabre.NewChan(1), sabre.NewChan(1)
+		_, _ = ch2.Put(ctx, sabre.Keyword("ready"))
+
+		got, ch, err := sabre.Alts(ctx, []sabre.Value{ch1, ch2}, nil)
+		if err != nil {
+			t.Fatalf("Alts() unexpected error: %v", err)
+		}
+
+		if ch != ch2 || got != sabre.Keyword("ready") {
+			t.Errorf("Alts() got = (%v, %v), want (:ready, ch2)", got, ch)
+		}
+	})
+
+	t.Run("Put", func(t *testing.T) {
+		ch1 := sabre.NewChan(1)
+
+		got, ch, err := sabre.Alts(ctx, []sabre.Value{
+			sabre.Vector{Values: []sabre.Value{ch1, sabre.Int64(1)}},
+		}, nil)
+		if err != nil {
+			t.Fatalf("Alts() unexpected error: %v", err)
+		}
+
+		if ch != ch1 || got != sabre.Bool(true) {
+			t.Errorf("Alts() got = (%v, %v), want (true, ch1)", got, ch)
+		}
+	})
+
+	t.Run("Default", func(t *testing.T) {
+		got, ch, err := sabre.Alts(ctx, []sabre.Value{sabre.NewChan(0)}, sabre.Keyword("none"))
+		if err != nil {
+			t.Fatalf("Alts() unexpected error: %v", err)
+		}
+
+		if ch != nil || got != sabre.Keyword("none") {
+			t.Errorf("Alts() got = (%v, %v), want (:none, nil)", got, ch)
+		}
+	})
+
+	t.Run("InvalidOp", func(t *testing.T) {
+		if _, _, err := sabre.Alts(ctx, []sabre.Value{sabre.Int64(1)}, nil); err == nil {
+			t.Errorf("Alts() expecting error for invalid operation")
+		}
+
+		if _, _, err := sabre.Alts(ctx, nil, nil); err == nil {
+			t.Errorf("Alts() expecting error for no operations")
+		}
+	})
+}
@@ -0,0 +1,602 @@
+package sabre
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Rules reported by Check.
+const (
+	RuleUnresolvedSymbol = "unresolved-symbol"
+	RuleArity            = "arity"
+	RuleShadowedName     = "shadowed-name"
+	RuleUnreachableCode  = "unreachable-code"
+	RuleMacroExpansion   = "macro-expansion"
+	RuleUnusedBinding    = "unused-binding"
+	RuleRedundantDo      = "redundant-do"
+	RuleIfWithoutElse    = "if-without-else"
+	RuleDuplicateElement = "duplicate-element"
+)
+
+// Severity indicates how serious a problem reported by Check is.
+type Severity int
+
+const (
+	// SeverityError is used for problems that cause an error when the form
+	// is evaluated.
+	SeverityError Severity = iota
+
+	// SeverityWarning is used for likely mistakes which do not cause an
+	// error by themselves.
+	SeverityWarning
+)
+
+func (s Severity) String() string {
+	if s == SeverityError {
+		return "error"
+	}
+
+	return "warning"
+}
+
+// Diagnostic represents a problem found in a form by Check.
+type Diagnostic struct {
+	Position
+	Severity Severity
+	Rule     string
+	Message  string
+	Form     Value
+}
+
+func (d Diagnostic) String() string {
+	return fmt.Sprintf("%s: %s: %s (%s)", d.Position, d.Severity, d.Message, d.Rule)
+}
+
+// Check statically analyses the form against the scope and returns all the
+// problems found. Local bindings introduced by let* and fn* are tracked
+// and macros bound in the scope are expanded before checking. Following
+// problems are reported:
+//
+//  1. Symbols that cannot be resolved.
+//  2. Calls to known functions with wrong number of arguments.
+//  3. Local bindings that shadow names bound in the scope or special forms.
+//  4. Forms that can never be evaluated (e.g., forms following a throw).
+//  5. let* bindings that are never used. Names starting with '_' are ignored.
+//  6. do forms with at most one form or directly within another body.
+//  7. if forms without else which implicitly return nil.
+//  8. Sets containing duplicate elements.
+//
+// Scope can be nil in which case only the forms defined using def within the
+// form are considered bound. Diagnostics are returned in the order of their
+// positions.
+func Check(scope Scope, form Value) []Diagnostic {
+	c := &checker{
+		scope:   scope,
+		globals: map[string]struct{}{},
+		fns:     map[string]MultiFn{},
+		used:    map[*localEnv]bool{},
+	}
+	collectDefs(form, c.globals)
+	c.collectFns(form, map[string]int{})
+
+	c.check(form, nil)
+
+	sort.SliceStable(c.diags, func(i, j int) bool {
+		a, b := c.diags[i].Position, c.diags[j].Position
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+
+	return c.diags
+}
+
+type checker struct {
+	scope   Scope
+	globals map[string]struct{}
+	fns     map[string]MultiFn
+	used    map[*localEnv]bool
+	pos     Position
+	diags   []Diagnostic
+}
+
+func (c *checker) check(form Value, locals *localEnv) {
+	switch v := form.(type) {
+	case Symbol:
+		c.checkSymbol(v, locals)
+
+	case Module:
+		c.checkBody(v, locals)
+
+	case Vector:
+		c.checkList(v.Values, locals)
+
+	case Set:
+		if !v.valid() {
+			c.report(v, SeverityError, RuleDuplicateElement, "duplicate value in set")
+		}
+		c.checkList(v.Values, locals)
+
+	case *HashMap:
+		c.checkList(v.kvs(), locals)
+
+	case *List:
+		pos := c.pos
+		if v.Line != 0 {
+			c.pos = v.Position
+		}
+		c.checkInvocation(v, locals)
+		c.pos = pos
+	}
+}
+
+func (c *checker) checkInvocation(lf *List, locals *localEnv) {
+	if lf.Size() == 0 {
+		return
+	}
+
+	args := lf.Values[1:]
+
+	sym, isSymbol := lf.Values[0].(Symbol)
+	if isSymbol && !locals.has(sym.Value) {
+		if getSpecial(sym) != nil {
+			c.checkSpecial(lf, sym.Value, locals)
+			return
+		}
+
+		if macro, isMacro := c.resolveMacro(sym.Value); isMacro {
+			expanded, err := macro.expand(c.scope, args)
+			if err != nil {
+				c.report(lf, SeverityError, RuleMacroExpansion, err.Error())
+				return
+			}
+
+			collectDefs(expanded, c.globals)
+			c.check(expanded, locals)
+			return
+		}
+	}
+
+	c.check(lf.Values[0], locals)
+	c.checkList(args, locals)
+
+	if fn, known := c.resolveFn(lf.Values[0], locals); known {
+		if _, err := fn.selectMethod(args); err != nil {
+			c.report(lf, SeverityError, RuleArity, err.Error())
+		}
+	}
+}
+
+func (c *checker) checkSpecial(lf *List, name string, locals *localEnv) {
+	args := lf.Values[1:]
+
+	switch name {
+	case "quote":
+
+	case "syntax-quote":
+		if len(args) > 0 {
+			c.checkUnquoted(args[0], locals)
+		}
+
+	case "fn*", "λ":
+		c.checkFn(args, locals)
+
+	case "let*":
+		c.checkLet(args, locals)
+
+	case "def":
+		if len(args) > 0 {
+			c.checkList(args[1:], locals)
+		}
+
+	case "if":
+		c.checkList(args, locals)
+		if len(args) == 3 {
+			c.checkIf(args)
+		} else if len(args) == 2 {
+			c.report(lf, SeverityWarning, RuleIfWithoutElse,
+				"if without else returns nil when test fails")
+		}
+
+	case "do":
+		if len(args) <= 1 {
+			c.report(lf, SeverityWarning, RuleRedundantDo,
+				fmt.Sprintf("do with %d form(s) is redundant", len(args)))
+		}
+		c.checkNestedDo(args)
+		c.checkBody(args, locals)
+
+	case "go", "future", "delay":
+		c.checkBody(args, locals)
+
+	default:
+		c.checkList(args, locals)
+	}
+}
+
+// checkFn checks the args of (fn* name? [arg*] expr*) or (fn* name? ([arg*]
+// expr*)+) forms.
+func (c *checker) checkFn(args []Value, locals *localEnv) {
+	if len(args) > 0 {
+		if _, isName := args[0].(Symbol); isName {
+			args = args[1:]
+		}
+	}
+
+	if len(args) == 0 {
+		return
+	}
+
+	if _, isList := args[0].(*List); !isList {
+		c.checkMethod(args, locals)
+		return
+	}
+
+	for _, arg := range args {
+		if spec, isList := arg.(*List); isList {
+			c.checkMethod(spec.Values, locals)
+		}
+	}
+}
+
+func (c *checker) checkMethod(spec []Value, locals *localEnv) {
+	if len(spec) == 0 {
+		return
+	}
+
+	var names []string
+	if vec, isVector := spec[0].(Vector); isVector {
+		for _, arg := range vec.Values {
+			if sym, isSymbol := arg.(Symbol); isSymbol && sym.Value != "&" {
+				c.checkShadowing(sym)
+				names = append(names, sym.Value)
+			}
+		}
+	}
+
+	c.checkNestedDo(spec[1:])
+	c.checkBody(spec[1:], locals.child(names...))
+}
+
+// checkLet checks the args of (let* [binding*] expr*) form.
+func (c *checker) checkLet(args []Value, locals *localEnv) {
+	if len(args) == 0 {
+		return
+	}
+
+	vec, isVector := args[0].(Vector)
+	if !isVector {
+		c.checkList(args, locals)
+		return
+	}
+
+	var bindings []Symbol
+	var envs []*localEnv
+
+	letLocals := locals.child()
+	for i := 0; i+1 < len(vec.Values); i += 2 {
+		c.check(vec.Values[i+1], letLocals)
+
+		if sym, isSymbol := vec.Values[i].(Symbol); isSymbol {
+			c.checkShadowing(sym)
+			letLocals = letLocals.child(sym.Value)
+			bindings = append(bindings, sym)
+			envs = append(envs, letLocals)
+		}
+	}
+
+	c.checkNestedDo(args[1:])
+	c.checkBody(args[1:], letLocals)
+
+	for i, sym := range bindings {
+		if !c.used[envs[i]] && !strings.HasPrefix(sym.Value, "_") {
+			c.report(sym, SeverityWarning, RuleUnusedBinding,
+				fmt.Sprintf("binding '%s' is never used", sym.Value))
+		}
+	}
+}
+
+// checkIf reports the branch of (if test then else) which is never taken
+// when the test is a constant.
+func (c *checker) checkIf(args []Value) {
+	var dead Value
+
+	switch test := args[0].(type) {
+	case Nil:
+		dead = args[1]
+
+	case Bool:
+		if test {
+			dead = args[2]
+		} else {
+			dead = args[1]
+		}
+
+	case Int64, BigInt, Float64, String, Character, Keyword:
+		dead = args[2]
+
+	default:
+		return
+	}
+
+	c.report(dead, SeverityWarning, RuleUnreachableCode,
+		fmt.Sprintf("'%s' is never evaluated since test is always %v",
+			dead, isTruthy(args[0])))
+}
+
+// checkBody checks forms which are evaluated in sequence and reports the
+// forms following a throw.
+func (c *checker) checkBody(forms []Value, locals *localEnv) {
+	for i, form := range forms {
+		c.check(form, locals)
+
+		if isThrow(form) && i < len(forms)-1 {
+			c.report(forms[i+1], SeverityWarning, RuleUnreachableCode,
+				fmt.Sprintf("'%s' is never evaluated since it follows throw", forms[i+1]))
+			c.checkList(forms[i+1:], locals)
+			return
+		}
+	}
+}
+
+// checkNestedDo reports do forms (with more than one form) appearing directly
+// in a body since the body is already evaluated in sequence.
+func (c *checker) checkNestedDo(body []Value) {
+	for _, form := range body {
+		lf, isList := form.(*List)
+		if !isList || lf.Size() <= 2 {
+			continue
+		}
+
+		if sym, isSymbol := lf.Values[0].(Symbol); isSymbol && sym.Value == "do" {
+			c.report(lf, SeverityWarning, RuleRedundantDo,
+				"do within a body is redundant")
+		}
+	}
+}
+
+// checkUnquoted checks the forms which are unquoted within a syntax-quote
+// form and will be evaluated.
+func (c *checker) checkUnquoted(form Value, locals *localEnv) {
+	var vals []Value
+
+	switch v := form.(type) {
+	case *List:
+		if isUnquote(v.Values) {
+			c.checkList(v.Values[1:], locals)
+			return
+		}
+		vals = v.Values
+
+	case Vector:
+		vals = v.Values
+
+	case Set:
+		vals = v.Values
+
+	case *HashMap:
+		vals = v.kvs()
+	}
+
+	for _, v := range vals {
+		c.checkUnquoted(v, locals)
+	}
+}
+
+func (c *checker) checkList(forms []Value, locals *localEnv) {
+	for _, form := range forms {
+		c.check(form, locals)
+	}
+}
+
+func (c *checker) checkSymbol(sym Symbol, locals *localEnv) {
+	if env := locals.lookup(sym.Value); env != nil {
+		c.used[env] = true
+		return
+	}
+
+	if c.isGlobal(sym.Value) {
+		return
+	}
+
+	c.report(sym, SeverityError, RuleUnresolvedSymbol,
+		fmt.Sprintf("unable to resolve symbol: %v", sym.Value))
+}
+
+func (c *checker) checkShadowing(sym Symbol) {
+	if getSpecial(sym) != nil {
+		c.report(sym, SeverityWarning, RuleShadowedName,
+			fmt.Sprintf("binding '%s' shadows special form", sym.Value))
+		return
+	}
+
+	if c.isGlobal(sym.Value) {
+		c.report(sym, SeverityWarning, RuleShadowedName,
+			fmt.Sprintf("binding '%s' shadows global binding", sym.Value))
+	}
+}
+
+func (c *checker) isGlobal(name string) bool {
+	if _, found := c.globals[name]; found {
+		return true
+	}
+
+	if c.scope == nil {
+		return false
+	}
+
+	_, err := c.scope.Resolve(name)
+	return err == nil
+}
+
+func (c *checker) resolveMacro(name string) (MultiFn, bool) {
+	if c.scope == nil {
+		return MultiFn{}, false
+	}
+
+	v, err := c.scope.Resolve(name)
+	if err != nil {
+		return MultiFn{}, false
+	}
+
+	multiFn, isMultiFn := v.(MultiFn)
+	return multiFn, isMultiFn && multiFn.IsMacro
+}
+
+// resolveFn returns the function the form evaluates to if it is known at
+// the time of checking. It is either a fn* form, a symbol defined once
+// using def with a fn* form or a function bound in the scope.
+func (c *checker) resolveFn(form Value, locals *localEnv) (MultiFn, bool) {
+	switch v := form.(type) {
+	case *List:
+		return parseFnForm(v)
+
+	case Symbol:
+		if locals.has(v.Value) {
+			return MultiFn{}, false
+		}
+
+		if fn, found := c.fns[v.Value]; found {
+			return fn, true
+		}
+
+		if _, found := c.globals[v.Value]; found || c.scope == nil {
+			return MultiFn{}, false
+		}
+
+		val, err := c.scope.Resolve(v.Value)
+		if err != nil {
+			return MultiFn{}, false
+		}
+
+		fn, isFn := val.(MultiFn)
+		return fn, isFn && !fn.IsMacro
+	}
+
+	return MultiFn{}, false
+}
+
+// collectFns collects the functions defined using (def name (fn* ...))
+// within the form. Names defined more than once are ignored since the
+// function being called cannot be known statically.
+func (c *checker) collectFns(form Value, defs map[string]int) {
+	var vals []Value
+
+	switch v := form.(type) {
+	case Module:
+		vals = v
+
+	case Vector:
+		vals = v.Values
+
+	case Set:
+		vals = v.Values
+
+	case *HashMap:
+		vals = v.kvs()
+
+	case *List:
+		vals = v.Values
+
+		if len(vals) == 3 {
+			head, isSymbol := vals[0].(Symbol)
+			name, isName := vals[1].(Symbol)
+			if isSymbol && isName && head.Value == "def" {
+				defs[name.Value]++
+
+				fnForm, isList := vals[2].(*List)
+				fn, isFn := MultiFn{}, false
+				if isList {
+					fn, isFn = parseFnForm(fnForm)
+				}
+
+				if isFn && defs[name.Value] == 1 {
+					if fn.Name == "" {
+						fn.Name = name.Value
+					}
+					c.fns[name.Value] = fn
+				} else {
+					delete(c.fns, name.Value)
+				}
+			}
+		}
+	}
+
+	for _, v := range vals {
+		c.collectFns(v, defs)
+	}
+}
+
+func (c *checker) report(form Value, severity Severity, rule, msg string) {
+	pos := getPosition(form)
+	if pos.Line == 0 {
+		pos = c.pos
+	}
+
+	c.diags = append(c.diags, Diagnostic{
+		Position: pos,
+		Severity: severity,
+		Rule:     rule,
+		Message:  msg,
+		Form:     form,
+	})
+}
+
+// parseFnForm returns the function (without the body) defined by the list
+// if it is a valid fn* form. Only the argument specs are parsed which is
+// sufficient for checking the arity of calls.
+func parseFnForm(lf *List) (MultiFn, bool) {
+	if lf.Size() < 2 {
+		return MultiFn{}, false
+	}
+
+	sym, isSymbol := lf.Values[0].(Symbol)
+	if !isSymbol || (sym.Value != "fn*" && sym.Value != "λ") {
+		return MultiFn{}, false
+	}
+
+	def := MultiFn{}
+	args := lf.Values[1:]
+	if name, isName := args[0].(Symbol); isName {
+		def.Name = name.String()
+		args = args[1:]
+	}
+
+	if len(args) == 0 {
+		return MultiFn{}, false
+	}
+
+	specs := []Value{args[0]}
+	if _, isList := args[0].(*List); isList {
+		specs = specs[:0]
+		for _, arg := range args {
+			spec, isList := arg.(*List)
+			if !isList || spec.Size() == 0 {
+				return MultiFn{}, false
+			}
+			specs = append(specs, spec.Values[0])
+		}
+	}
+
+	for _, spec := range specs {
+		var fn Fn
+		if err := fn.parseArgSpec(spec); err != nil {
+			return MultiFn{}, false
+		}
+		def.Methods = append(def.Methods, fn)
+	}
+
+	return def, true
+}
+
+func isThrow(form Value) bool {
+	lf, isList := form.(*List)
+	if !isList || lf.Size() == 0 {
+		return false
+	}
+
+	sym, isSymbol := lf.Values[0].(Symbol)
+	return isSymbol && sym.Value == "throw"
+}
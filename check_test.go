@@ -0,0 +1,248 @@
+package sabre_test
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spy16/sabre"
+)
+
+func TestCheck(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		name     string
+		src      string
+		getScope func() sabre.Scope
+		getForm  func() sabre.Value
+		want     []string
+	}{
+		{
+			name: "NoProblems",
+			src:  sampleProgram,
+			want: nil,
+		},
+		{
+			name: "UnresolvedSymbols",
+			src: `(def f (fn* [a] (g a)))
+(let* [b 1] [a b c])`,
+			want: []string{
+				"1:18 unresolved-symbol",
+				"2:14 unresolved-symbol",
+				"2:18 unresolved-symbol",
+			},
+		},
+		{
+			name: "SyntaxQuote",
+			src:  "(let* [a 1] `(a ~a ~b))",
+			want: []string{"1:21 unresolved-symbol"},
+		},
+		{
+			name: "ArityOfDefinedFn",
+			src: `(def f (fn* ([a] a) ([a b & rest] b)))
+(f) (f 1) (f 1 2 3 4)`,
+			want: []string{"2:1 arity"},
+		},
+		{
+			name: "ArityOfRedefinedFn",
+			src: `(def f (fn* [a] a))
+(def f (fn* [] 1))
+(f)`,
+			want: nil,
+		},
+		{
+			name: "ArityOfFnLiteral",
+			src:  `((fn* [a] a))`,
+			want: []string{"1:1 arity"},
+		},
+		{
+			name: "ArityOfScopeFn",
+			src:  `(inc 1 2)`,
+			getScope: func() sabre.Scope {
+				scope := sabre.NewScope(nil)
+				_ = scope.Bind("inc", sabre.MultiFn{
+					Name:    "inc",
+					Methods: []sabre.Fn{{Args: []string{"a"}}},
+				})
+				return scope
+			},
+			want: []string{"1:1 arity"},
+		},
+		{
+			name: "LocalShadowsFn",
+			src:  `(def f (fn* [a] a)) (let* [f (fn* [] 1)] (f))`,
+			want: []string{"1:28 shadowed-name"},
+		},
+		{
+			name: "ShadowedNames",
+			src:  `(fn* [if x] (let* [str 1 y 2] [if x str y]))`,
+			getScope: func() sabre.Scope {
+				scope := sabre.NewScope(nil)
+				_ = scope.Bind("str", sabre.String(""))
+				return scope
+			},
+			want: []string{
+				"1:7 shadowed-name",
+				"1:20 shadowed-name",
+			},
+		},
+		{
+			name: "CodeAfterThrow",
+			src: `(do
+  (throw "failed")
+  (undefined))`,
+			want: []string{
+				"3:3 unreachable-code",
+				"3:4 unresolved-symbol",
+			},
+		},
+		{
+			name: "ConstantTest",
+			src:  `(if true :yes :no) (if nil :yes :no) (if :x 1)`,
+			want: []string{
+				"1:1 unreachable-code",
+				"1:20 unreachable-code",
+				"1:38 if-without-else",
+			},
+		},
+		{
+			name: "UnusedBindings",
+			src: `(let* [a 1 b 2 _c 3 d 4]
+  (let* [b a] ` + "`" + `(~d)))`,
+			want: []string{
+				"1:12 unused-binding",
+				"2:10 unused-binding",
+			},
+		},
+		{
+			name: "RedundantDo",
+			src: `(do 1)
+(fn* [] (do 1 2))
+(let* [] (do) (do 1 2))`,
+			want: []string{
+				"1:1 redundant-do",
+				"2:9 redundant-do",
+				"3:10 redundant-do",
+				"3:15 redundant-do",
+			},
+		},
+		{
+			name: "DuplicateSetElements",
+			getForm: func() sabre.Value {
+				return sabre.Set{
+					Values:   []sabre.Value{sabre.Int64(1), sabre.Int64(1)},
+					Position: sabre.Position{Line: 1, Column: 1},
+				}
+			},
+			want: []string{"1:1 duplicate-element"},
+		},
+		{
+			name: "DuplicateSetElementsRead",
+			getForm: func() sabre.Value {
+				rd := sabre.NewReader(strings.NewReader(`#{1 1} (g)`))
+				rd.KeepDuplicates(true)
+
+				form, err := rd.All()
+				if err != nil {
+					panic(err)
+				}
+				return form
+			},
+			want: []string{"1:2 duplicate-element", "1:9 unresolved-symbol"},
+		},
+		{
+			name: "Macro",
+			src:  `(unless (undefined) 1 2) (unless 1)`,
+			getScope: func() sabre.Scope {
+				scope := sabre.NewScope(nil)
+				_ = scope.Bind("unless", sabre.MultiFn{
+					Name:    "unless",
+					IsMacro: true,
+					Methods: []sabre.Fn{{
+						Args: []string{"test", "a", "b"},
+						Func: sabre.GoFunc(func(_ sabre.Scope, args []sabre.Value) (sabre.Value, error) {
+							return &sabre.List{Values: []sabre.Value{
+								sabre.Symbol{Value: "if"}, args[0], args[2], args[1],
+							}}, nil
+						}),
+					}},
+				})
+				return scope
+			},
+			want: []string{
+				"1:10 unresolved-symbol",
+				"1:26 macro-expansion",
+			},
+		},
+		{
+			name: "MacroDefinitions",
+			src:  `(defconst answer 42) answer`,
+			getScope: func() sabre.Scope {
+				scope := sabre.NewScope(nil)
+				_ = scope.Bind("defconst", sabre.MultiFn{
+					Name:    "defconst",
+					IsMacro: true,
+					Methods: []sabre.Fn{{
+						Args: []string{"name", "value"},
+						Func: sabre.GoFunc(func(_ sabre.Scope, args []sabre.Value) (sabre.Value, error) {
+							return &sabre.List{Values: []sabre.Value{
+								sabre.Symbol{Value: "def"}, args[0], args[1],
+							}}, nil
+						}),
+					}},
+				})
+				return scope
+			},
+			want: nil,
+		},
+	}
+
+	for _, tt := range table {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var scope sabre.Scope
+			if tt.getScope != nil {
+				scope = tt.getScope()
+			}
+
+			form, err := sabre.NewReader(strings.NewReader(tt.src)).All()
+			if err != nil {
+				t.Fatalf("All() unexpected error: %v", err)
+			}
+
+			if tt.getForm != nil {
+				form = tt.getForm()
+			}
+
+			var got []string
+			for _, d := range sabre.Check(scope, form) {
+				got = append(got, fmt.Sprintf("%d:%d %s", d.Line, d.Column, d.Rule))
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Check() got = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheck_ArityMessage(t *testing.T) {
+	t.Parallel()
+
+	form, err := sabre.NewReader(strings.NewReader(`(def f (fn* [a] a)) (f)`)).All()
+	if err != nil {
+		t.Fatalf("All() unexpected error: %v", err)
+	}
+
+	diags := sabre.Check(nil, form)
+	if len(diags) != 1 {
+		t.Fatalf("Check() got %d diagnostics, want 1", len(diags))
+	}
+
+	want := "wrong number of args (0) to 'f'"
+	if diags[0].Message != want {
+		t.Errorf("Check() message = %q, want %q", diags[0].Message, want)
+	}
+}
@@ -0,0 +1,886 @@
+package sabre
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// compileClosure compiles the form into a tree of Go closures. Local
+// bindings introduced by fn* and let* are resolved at compile time to slot
+// indices in a frame. Other symbols are resolved against the scope during
+// execution.
+func compileClosure(scope Scope, form Value) (code, int, error) {
+	cc := &closureCompiler{scope: scope}
+	top := &lexScope{}
+
+	c, err := cc.compile(form, top)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return c, top.nslots, nil
+}
+
+// code represents a form compiled into a Go closure.
+type code func(env *frame) (Value, error)
+
+type closureCompiler struct {
+	scope Scope
+}
+
+func (cc *closureCompiler) compile(form Value, ls *lexScope) (code, error) {
+	var c code
+	var err error
+
+	switch v := form.(type) {
+	case Nil, Bool, Int64, BigInt, Float64, String, Character, Keyword, Regex, Time, Duration, UUID:
+		return constant(v), nil
+
+	case Symbol:
+		return cc.compileSymbol(v, ls), nil
+
+	case Module:
+		return cc.compileBody(v, ls)
+
+	case Vector:
+		var items []code
+		items, err = cc.compileList(v.Values, ls)
+		c = func(env *frame) (Value, error) {
+			vals, err := evalCodes(env, items, v.Values)
+			if err != nil {
+				return nil, err
+			}
+
+			return Vector{Values: vals, meta: v.meta}, nil
+		}
+
+	case Set:
+		var items []code
+		items, err = cc.compileList(v.Values, ls)
+		c = func(env *frame) (Value, error) {
+			vals, err := evalCodes(env, items, v.Values)
+			if err != nil {
+				return nil, err
+			}
+
+			return Set{Values: uniq(vals), meta: v.meta}, nil
+		}
+
+	case *HashMap:
+		forms := v.kvs()
+		var items []code
+		items, err = cc.compileList(forms, ls)
+		c = func(env *frame) (Value, error) {
+			kvs, err := evalCodes(env, items, forms)
+			if err != nil {
+				return nil, err
+			}
+
+			return v.withEntries(kvs), nil
+		}
+
+	case *List:
+		c, err = cc.compileInvocation(v, ls)
+
+	default:
+		return cc.compileFallback(form, ls), nil
+	}
+
+	if err != nil {
+		if _, ok := err.(EvalError); ok {
+			return nil, err
+		}
+
+		return nil, EvalError{
+			Cause:    err,
+			Position: getPosition(form),
+			Form:     form,
+		}
+	}
+
+	return c, nil
+}
+
+func (cc *closureCompiler) compileSymbol(sym Symbol, ls *lexScope) code {
+	depth, slot, found := ls.resolve(sym.Value)
+	if !found {
+		return func(env *frame) (Value, error) {
+			return env.global.Resolve(sym.Value)
+		}
+	}
+
+	switch depth {
+	case 0:
+		return func(env *frame) (Value, error) {
+			return env.slots[slot], nil
+		}
+
+	case 1:
+		return func(env *frame) (Value, error) {
+			return env.parent.slots[slot], nil
+		}
+
+	default:
+		return func(env *frame) (Value, error) {
+			return env.lookup(depth, slot), nil
+		}
+	}
+}
+
+// compileBody compiles the forms to be evaluated in order. Result of the
+// last form is returned.
+func (cc *closureCompiler) compileBody(forms []Value, ls *lexScope) (code, error) {
+	body, err := cc.compileList(forms, ls)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(body) {
+	case 0:
+		return constant(Nil{}), nil
+
+	case 1:
+		form, single := forms[0], body[0]
+		return func(env *frame) (Value, error) {
+			v, err := single(env)
+			if err != nil {
+				return nil, wrapEvalErr(form, err)
+			}
+
+			return v, nil
+		}, nil
+
+	default:
+		return func(env *frame) (Value, error) {
+			var v Value
+			var err error
+			for i, c := range body {
+				v, err = c(env)
+				if err != nil {
+					return nil, wrapEvalErr(forms[i], err)
+				}
+			}
+
+			return v, nil
+		}, nil
+	}
+}
+
+func (cc *closureCompiler) compileInvocation(lf *List, ls *lexScope) (code, error) {
+	if lf.Size() == 0 {
+		return func(_ *frame) (Value, error) {
+			return &List{}, nil
+		}, nil
+	}
+
+	if sym, isSymbol := lf.Values[0].(Symbol); isSymbol && getSpecial(sym) != nil {
+		return cc.compileSpecial(lf, sym.Value, ls)
+	}
+
+	target, err := cc.compile(lf.Values[0], ls)
+	if err != nil {
+		return nil, err
+	}
+
+	forms := lf.Values[1:]
+	args, err := cc.compileList(forms, ls)
+	if err != nil {
+		return nil, err
+	}
+
+	locals := ls.locals()
+	return func(env *frame) (Value, error) {
+		v, err := target(env)
+		if err != nil {
+			return nil, err
+		}
+
+		switch fn := v.(type) {
+		case MultiFn:
+			view := localScope{env: env, locals: locals}
+			if fn.IsMacro {
+				return fn.Invoke(view, forms...)
+			}
+
+			method, err := fn.selectMethod(forms)
+			if err != nil {
+				return nil, err
+			}
+
+			vals, err := evalCodes(env, args, forms)
+			if err != nil {
+				return nil, err
+			}
+
+			if cm, ok := method.Func.(*closureMethod); ok {
+				return cm.run(env, nil, vals)
+			}
+
+			return method.invoke(fn.bodyScope(view), view, vals)
+
+		case Invokable:
+			vals, err := evalCodes(env, args, forms)
+			if err != nil {
+				return nil, err
+			}
+
+			return applyInPlace(localScope{env: env, locals: locals}, fn, vals)
+
+		default:
+			return nil, fmt.Errorf("cannot invoke value of type '%s'", reflect.TypeOf(v))
+		}
+	}, nil
+}
+
+func (cc *closureCompiler) compileSpecial(lf *List, name string, ls *lexScope) (code, error) {
+	args := lf.Values[1:]
+
+	switch name {
+	case "quote":
+		if err := verifyArgCount([]int{1}, args); err != nil {
+			return nil, err
+		}
+		return constant(args[0]), nil
+
+	case "syntax-quote":
+		if err := verifyArgCount([]int{1}, args); err != nil {
+			return nil, err
+		}
+		return cc.compileSyntaxQuote(args[0], ls)
+
+	case "fn*", "λ":
+		return cc.compileLambda(args, ls)
+
+	case "let*":
+		return cc.compileLet(args, ls)
+
+	case "def":
+		return cc.compileDef(args, ls)
+
+	case "if":
+		return cc.compileIf(args, ls)
+
+	case "do":
+		return cc.compileBody(args, ls)
+
+	case "throw":
+		vals, err := cc.compileList(args, ls)
+		if err != nil {
+			return nil, err
+		}
+
+		return func(env *frame) (Value, error) {
+			vals, err := evalCodes(env, vals, args)
+			if err != nil {
+				return nil, err
+			}
+
+			return nil, errors.New(string(stringFromVals(vals)))
+		}, nil
+
+	case "go":
+		return cc.compileGo(args, ls)
+
+	case "future", "delay":
+		body, err := cc.compileBody(args, ls)
+		if err != nil {
+			return nil, err
+		}
+
+		locals := ls.locals()
+		isFuture := name == "future"
+		return func(env *frame) (Value, error) {
+			scope := localScope{env: env, locals: locals}
+			form := compiledForm{code: body, env: env}
+			if isFuture {
+				return NewFuture(scope, form), nil
+			}
+			return NewDelay(scope, form), nil
+		}, nil
+
+	default:
+		analyzed, err := lf.analyze(cc.scope)
+		if err != nil {
+			return nil, err
+		}
+
+		return cc.compileFallback(analyzed, ls), nil
+	}
+}
+
+// compileLambda compiles (fn* name? [arg*] expr*) or (fn* name? ([arg*]
+// expr*)+) forms.
+func (cc *closureCompiler) compileLambda(args []Value, ls *lexScope) (code, error) {
+	if len(args) < 1 {
+		return nil, fmt.Errorf("insufficient args (%d) for 'fn'", len(args))
+	}
+
+	var fnName string
+	nextIndex := 0
+
+	name, isName := args[nextIndex].(Symbol)
+	if isName {
+		fnName = name.String()
+		nextIndex++
+	}
+
+	var protos []*lambda
+	if _, isList := args[nextIndex].(*List); isList {
+		for _, arg := range args[nextIndex:] {
+			spec, isList := arg.(*List)
+			if !isList {
+				return nil, fmt.Errorf("expected arg to be list, not %s",
+					reflect.TypeOf(arg))
+			}
+
+			proto, err := cc.compileMethod(spec.Values, ls)
+			if err != nil {
+				return nil, err
+			}
+			protos = append(protos, proto)
+		}
+	} else {
+		proto, err := cc.compileMethod(args[nextIndex:], ls)
+		if err != nil {
+			return nil, err
+		}
+		protos = append(protos, proto)
+	}
+
+	return func(env *frame) (Value, error) {
+		methods := make([]Fn, len(protos))
+		for i, proto := range protos {
+			methods[i] = Fn{
+				Args:     proto.args,
+				Variadic: proto.variadic,
+				Func:     &closureMethod{proto: proto, env: env},
+			}
+		}
+
+		return MultiFn{Name: fnName, Methods: methods}, nil
+	}, nil
+}
+
+func (cc *closureCompiler) compileMethod(spec []Value, ls *lexScope) (*lambda, error) {
+	if len(spec) < 1 {
+		return nil, fmt.Errorf("insufficient args (%d) for 'fn'", len(spec))
+	}
+
+	var fn Fn
+	if err := fn.parseArgSpec(spec[0]); err != nil {
+		return nil, err
+	}
+
+	fnScope := &lexScope{parent: ls}
+	for _, arg := range fn.Args {
+		fnScope.declare(arg)
+	}
+
+	body, err := cc.compileBody(spec[1:], fnScope)
+	if err != nil {
+		return nil, err
+	}
+
+	return &lambda{
+		args:     fn.Args,
+		variadic: fn.Variadic,
+		nslots:   fnScope.nslots,
+		body:     body,
+	}, nil
+}
+
+// compileLet compiles (let* [binding*] expr*) form. Bindings are assigned
+// to slots in the frame of the enclosing function.
+func (cc *closureCompiler) compileLet(args []Value, ls *lexScope) (code, error) {
+	if len(args) < 1 {
+		return nil, fmt.Errorf("call requires at-least bindings argument")
+	}
+
+	vec, isVector := args[0].(Vector)
+	if !isVector {
+		return nil, fmt.Errorf(
+			"first argument to let must be bindings vector, not %v",
+			reflect.TypeOf(args[0]),
+		)
+	}
+
+	if len(vec.Values)%2 != 0 {
+		return nil, fmt.Errorf("bindings must contain event forms")
+	}
+
+	visible := len(ls.vars)
+	defer func() { ls.vars = ls.vars[:visible] }()
+
+	var slots []int
+	var exprs []code
+	for i := 0; i < len(vec.Values); i += 2 {
+		sym, isSymbol := vec.Values[i].(Symbol)
+		if !isSymbol {
+			return nil, fmt.Errorf(
+				"item at %d must be symbol, not %s",
+				i, vec.Values[i],
+			)
+		}
+
+		expr, err := cc.compile(vec.Values[i+1], ls)
+		if err != nil {
+			return nil, err
+		}
+
+		exprs = append(exprs, expr)
+		slots = append(slots, ls.declare(sym.Value))
+	}
+
+	body, err := cc.compileBody(args[1:], ls)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(env *frame) (Value, error) {
+		for i, expr := range exprs {
+			v, err := expr(env)
+			if err != nil {
+				return nil, err
+			}
+
+			env.slots[slots[i]] = v
+		}
+
+		return body(env)
+	}, nil
+}
+
+func (cc *closureCompiler) compileDef(args []Value, ls *lexScope) (code, error) {
+	if err := verifyArgCount([]int{2}, args); err != nil {
+		return nil, err
+	}
+
+	sym, isSymbol := args[0].(Symbol)
+	if !isSymbol {
+		return nil, fmt.Errorf("first argument must be symbol, not '%v'",
+			reflect.TypeOf(args[0]))
+	}
+
+	val, err := cc.compile(args[1], ls)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(env *frame) (Value, error) {
+		v, err := val(env)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := bindDef(env.global, sym, v); err != nil {
+			return nil, err
+		}
+
+		return sym, nil
+	}, nil
+}
+
+func (cc *closureCompiler) compileIf(args []Value, ls *lexScope) (code, error) {
+	if err := verifyArgCount([]int{2, 3}, args); err != nil {
+		return nil, err
+	}
+
+	branches, err := cc.compileList(args, ls)
+	if err != nil {
+		return nil, err
+	}
+
+	test, then := branches[0], branches[1]
+	orElse := constant(Nil{})
+	if len(branches) == 3 {
+		orElse = branches[2]
+	}
+
+	return func(env *frame) (Value, error) {
+		v, err := test(env)
+		if err != nil {
+			return nil, err
+		}
+
+		if !isTruthy(v) {
+			return orElse(env)
+		}
+
+		return then(env)
+	}, nil
+}
+
+func (cc *closureCompiler) compileGo(args []Value, ls *lexScope) (code, error) {
+	body, err := cc.compileBody(args, ls)
+	if err != nil {
+		return nil, err
+	}
+
+	return func(env *frame) (Value, error) {
+		ch := NewChan(1)
+
+		go func() {
+			var v Value
+			err := Context(localScope{env: env}).Err()
+			if err == nil {
+				v, err = body(env)
+			}
+
+			if err != nil {
+				ch.closeWithErr(err)
+				return
+			}
+
+			ch.rv.Send(reflect.ValueOf(&v).Elem())
+			ch.rv.Close()
+		}()
+
+		return ch, nil
+	}, nil
+}
+
+// compileSyntaxQuote compiles the syntax-quoted form. Only the unquoted
+// forms within are evaluated.
+func (cc *closureCompiler) compileSyntaxQuote(form Value, ls *lexScope) (code, error) {
+	var vals []Value
+	var build func(vals []Value) Value
+
+	switch v := form.(type) {
+	case *List:
+		if isUnquote(v.Values) {
+			if err := verifyArgCount([]int{1}, v.Values[1:]); err != nil {
+				return nil, err
+			}
+			return cc.compile(v.Values[1], ls)
+		}
+
+		vals = v.Values
+		build = func(vals []Value) Value { return &List{Values: vals} }
+
+	case Set:
+		vals = v.Values
+		build = func(vals []Value) Value { return Set{Values: vals} }
+
+	case Vector:
+		vals = v.Values
+		build = func(vals []Value) Value { return Vector{Values: vals} }
+
+	case *HashMap:
+		vals = v.kvs()
+		build = func(vals []Value) Value { return v.withEntries(vals) }
+
+	default:
+		return constant(form), nil
+	}
+
+	items := make([]code, len(vals))
+	for i, v := range vals {
+		c, err := cc.compileSyntaxQuote(v, ls)
+		if err != nil {
+			return nil, err
+		}
+		items[i] = c
+	}
+
+	return func(env *frame) (Value, error) {
+		var quoted []Value
+		for _, item := range items {
+			v, err := item(env)
+			if err != nil {
+				return nil, err
+			}
+			quoted = append(quoted, v)
+		}
+
+		return build(quoted), nil
+	}, nil
+}
+
+// compileFallback returns code that evaluates the form directly against a
+// scope through which the local bindings are accessible. This is used for
+// custom Value types and special forms not known to the compiler.
+func (cc *closureCompiler) compileFallback(form Value, ls *lexScope) code {
+	locals := ls.locals()
+	return func(env *frame) (Value, error) {
+		return form.Eval(localScope{env: env, locals: locals})
+	}
+}
+
+func (cc *closureCompiler) compileList(forms []Value, ls *lexScope) ([]code, error) {
+	res := make([]code, len(forms))
+	for i, form := range forms {
+		c, err := cc.compile(form, ls)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = c
+	}
+
+	return res, nil
+}
+
+func constant(v Value) code {
+	return func(_ *frame) (Value, error) {
+		return v, nil
+	}
+}
+
+func evalCodes(env *frame, codes []code, forms []Value) ([]Value, error) {
+	if len(codes) == 0 {
+		return nil, nil
+	}
+
+	vals := make([]Value, len(codes))
+	for i, c := range codes {
+		v, err := c(env)
+		if err != nil {
+			return nil, wrapEvalErr(forms[i], err)
+		}
+		vals[i] = v
+	}
+
+	return vals, nil
+}
+
+func wrapEvalErr(form Value, err error) error {
+	return EvalError{
+		Position: getPosition(form),
+		Cause:    err,
+		Form:     form,
+	}
+}
+
+// lambda is the compiled form of a single function method.
+type lambda struct {
+	args     []string
+	variadic bool
+	nslots   int
+	body     code
+}
+
+// closureMethod binds a compiled method to the frame it was defined in. It
+// is used as Fn.Func of the compiled functions.
+type closureMethod struct {
+	proto *lambda
+	env   *frame
+}
+
+// Invoke executes the method with already evaluated args.
+func (cm *closureMethod) Invoke(scope Scope, args ...Value) (Value, error) {
+	return cm.call(scope, args)
+}
+
+func (cm *closureMethod) call(caller Scope, args []Value) (Value, error) {
+	return cm.run(nil, caller, args)
+}
+
+// run executes the method in a new frame. Either 'callerFrame' or
+// 'callerScope' refers to the call site. Returns the error of the context if
+// the evaluation has been cancelled.
+func (cm *closureMethod) run(callerFrame *frame, callerScope Scope, args []Value) (Value, error) {
+	var ctx context.Context
+	if callerFrame != nil {
+		ctx = Context(localScope{env: callerFrame})
+	} else {
+		ctx = Context(callerScope)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	proto := cm.proto
+	env := &frame{
+		slots:       make([]Value, proto.nslots),
+		parent:      cm.env,
+		global:      cm.env.global,
+		callerFrame: callerFrame,
+		callerScope: callerScope,
+		ctx:         ctx,
+	}
+
+	for idx := range proto.args {
+		if idx == len(proto.args)-1 && proto.variadic {
+			env.slots[idx] = &List{Values: args[idx:]}
+		} else {
+			env.slots[idx] = args[idx]
+		}
+	}
+
+	return proto.body(env)
+}
+
+// frame holds the local bindings of a single function invocation (or the
+// top level of a program) in slots.
+type frame struct {
+	slots  []Value
+	parent *frame
+	global Scope
+
+	// callerFrame or callerScope refers to the call site and is used only
+	// for finding evaluation-level state such as context.
+	callerFrame *frame
+	callerScope Scope
+
+	// ctx is the context of the evaluation that created the frame. It is set
+	// for frames of function invocations only.
+	ctx context.Context
+}
+
+func (env *frame) lookup(depth, slot int) Value {
+	f := env
+	for ; depth > 0; depth-- {
+		f = f.parent
+	}
+
+	return f.slots[slot]
+}
+
+// withCaller returns a copy of the frame sharing the slots, with the call
+// site set to 'scope'.
+func (env *frame) withCaller(scope Scope) *frame {
+	return &frame{
+		slots:       env.slots,
+		parent:      env.parent,
+		global:      env.global,
+		callerScope: scope,
+	}
+}
+
+// dynamicParent returns the next scope in the dynamic chain (See walkDynamic).
+func (env *frame) dynamicParent() Scope {
+	f := env
+	for {
+		switch {
+		case f.callerScope != nil:
+			return f.callerScope
+
+		case f.callerFrame != nil:
+			f = f.callerFrame
+
+		case f.parent != nil:
+			f = f.parent
+
+		default:
+			return f.global
+		}
+	}
+}
+
+// localScope exposes the local bindings visible at some point in compiled
+// code as a Scope. It is passed to values that are not compiled (e.g., Go
+// functions) so that they can resolve local bindings as usual.
+type localScope struct {
+	env    *frame
+	locals []localRef
+}
+
+func (ls localScope) Parent() Scope {
+	return ls.env.global
+}
+
+func (ls localScope) Bind(symbol string, v Value) error {
+	if ref, found := ls.find(symbol); found {
+		f := ls.env
+		for d := ref.depth; d > 0; d-- {
+			f = f.parent
+		}
+		f.slots[ref.slot] = v
+		return nil
+	}
+
+	return ls.env.global.Bind(symbol, v)
+}
+
+func (ls localScope) Resolve(symbol string) (Value, error) {
+	if ref, found := ls.find(symbol); found {
+		return ls.env.lookup(ref.depth, ref.slot), nil
+	}
+
+	return ls.env.global.Resolve(symbol)
+}
+
+func (ls localScope) find(symbol string) (localRef, bool) {
+	for i := len(ls.locals) - 1; i >= 0; i-- {
+		if ls.locals[i].name == symbol {
+			return ls.locals[i], true
+		}
+	}
+
+	return localRef{}, false
+}
+
+// compiledForm adapts compiled code to the Value interface so that it can
+// be evaluated later (e.g., by Future or Delay).
+type compiledForm struct {
+	code code
+	env  *frame
+}
+
+func (cf compiledForm) Eval(scope Scope) (Value, error) {
+	return cf.code(cf.env.withCaller(scope))
+}
+
+func (cf compiledForm) String() string {
+	return "<compiled>"
+}
+
+// lexScope tracks the local bindings visible during compilation of a single
+// function.
+type lexScope struct {
+	parent *lexScope
+	vars   []localVar
+	nslots int
+}
+
+type localVar struct {
+	name string
+	slot int
+}
+
+type localRef struct {
+	name  string
+	depth int
+	slot  int
+}
+
+// declare allocates a new slot for the local binding and makes it visible.
+func (ls *lexScope) declare(name string) int {
+	slot := ls.nslots
+	ls.nslots++
+	ls.vars = append(ls.vars, localVar{name: name, slot: slot})
+	return slot
+}
+
+func (ls *lexScope) resolve(name string) (depth, slot int, found bool) {
+	for s := ls; s != nil; s = s.parent {
+		for i := len(s.vars) - 1; i >= 0; i-- {
+			if s.vars[i].name == name {
+				return depth, s.vars[i].slot, true
+			}
+		}
+		depth++
+	}
+
+	return 0, 0, false
+}
+
+// locals returns all the visible local bindings with the innermost ones at
+// the end.
+func (ls *lexScope) locals() []localRef {
+	var scopes []*lexScope
+	for s := ls; s != nil; s = s.parent {
+		scopes = append(scopes, s)
+	}
+
+	var refs []localRef
+	for depth := len(scopes) - 1; depth >= 0; depth-- {
+		for _, v := range scopes[depth].vars {
+			refs = append(refs, localRef{name: v.name, depth: depth, slot: v.slot})
+		}
+	}
+
+	return refs
+}
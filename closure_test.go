@@ -0,0 +1,119 @@
+package sabre_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/spy16/sabre"
+)
+
+const fibProgram = `
+(def fib (fn* [n]
+  (if (< n 2)
+    n
+    (+ (fib (- n 1)) (fib (- n 2))))))
+
+(fib 20)
+`
+
+const loopProgram = `
+(def count-down (fn* [n acc]
+  (if (zero? n)
+    acc
+    (let* [next (dec n)]
+      (count-down next (inc acc))))))
+
+(count-down 5000 0)
+`
+
+const collectionsProgram = `
+(def build (fn* [n acc]
+  (if (zero? n)
+    acc
+    (build (dec n) (conj acc [n #{n :x} (vector n n)])))))
+
+(def pick (fn* [v i acc]
+  (if (zero? i)
+    acc
+    (pick v (dec i) (conj acc ((v i) 0))))))
+
+(let* [v (build 200 [])]
+  (count (pick v (dec (count v)) [])))
+`
+
+func BenchmarkFib(b *testing.B) {
+	benchmarkBackends(b, fibProgram, sabre.Int64(6765))
+}
+
+func BenchmarkLoop(b *testing.B) {
+	benchmarkBackends(b, loopProgram, sabre.Int64(5000))
+}
+
+func BenchmarkCollections(b *testing.B) {
+	benchmarkBackends(b, collectionsProgram, sabre.Int64(199))
+}
+
+func benchmarkBackends(b *testing.B, src string, want sabre.Value) {
+	for _, be := range backends {
+		b.Run(be.name, func(b *testing.B) {
+			scope := benchScope()
+
+			prog, err := sabre.ReadCompile(scope, strings.NewReader(src), sabre.WithBackend(be.backend))
+			if err != nil {
+				b.Fatalf("Compile() unexpected error: %v", err)
+			}
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				got, err := prog.Run(context.Background(), scope)
+				if err != nil {
+					b.Fatalf("Run() unexpected error: %v", err)
+				}
+				if got != want {
+					b.Fatalf("Run() got = %v, want %v", got, want)
+				}
+			}
+		})
+	}
+}
+
+func benchScope() sabre.Scope {
+	scope := sabre.NewScope(nil)
+
+	fns := map[string]func(args []sabre.Value) sabre.Value{
+		"+":     func(args []sabre.Value) sabre.Value { return args[0].(sabre.Int64) + args[1].(sabre.Int64) },
+		"-":     func(args []sabre.Value) sabre.Value { return args[0].(sabre.Int64) - args[1].(sabre.Int64) },
+		"<":     func(args []sabre.Value) sabre.Value { return sabre.Bool(args[0].(sabre.Int64) < args[1].(sabre.Int64)) },
+		"inc":   func(args []sabre.Value) sabre.Value { return args[0].(sabre.Int64) + 1 },
+		"dec":   func(args []sabre.Value) sabre.Value { return args[0].(sabre.Int64) - 1 },
+		"zero?": func(args []sabre.Value) sabre.Value { return sabre.Bool(args[0].(sabre.Int64) == 0) },
+		"count": func(args []sabre.Value) sabre.Value { return sabre.Int64(len(args[0].(sabre.Vector).Values)) },
+		"vector": func(args []sabre.Value) sabre.Value {
+			return sabre.Vector{Values: args}
+		},
+		"conj": func(args []sabre.Value) sabre.Value {
+			vec := args[0].(sabre.Vector)
+			vals := append(vec.Values[:len(vec.Values):len(vec.Values)], args[1])
+			return sabre.Vector{Values: vals}
+		},
+	}
+
+	for name, fn := range fns {
+		fn := fn
+		_ = scope.Bind(name, sabre.GoFunc(func(scope sabre.Scope, args []sabre.Value) (sabre.Value, error) {
+			vals := make([]sabre.Value, len(args))
+			for i, arg := range args {
+				v, err := arg.Eval(scope)
+				if err != nil {
+					return nil, err
+				}
+				vals[i] = v
+			}
+
+			return fn(vals), nil
+		}))
+	}
+
+	return scope
+}
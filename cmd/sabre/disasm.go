@@ -0,0 +1,51 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spy16/sabre"
+	"github.com/spy16/sabre/core"
+)
+
+// disasm compiles the file (or the expression given with -e) to bytecode
+// and prints the disassembly.
+//
+// Usage: sabre disasm [-e expr] [file]
+func disasm(args []string) {
+	flags := flag.NewFlagSet("disasm", flag.ExitOnError)
+	expr := flags.String("e", "", "Disassemble string")
+	_ = flags.Parse(args)
+
+	var src io.Reader
+	switch {
+	case *expr != "":
+		src = strings.NewReader(*expr)
+
+	case flags.NArg() == 1:
+		fh, err := os.Open(flags.Arg(0))
+		if err != nil {
+			fatalf("error: %v\n", err)
+		}
+		defer fh.Close()
+		src = fh
+
+	default:
+		fatalf("usage: sabre disasm [-e expr] [file]\n")
+	}
+
+	scope := sabre.NewScope(nil)
+	core.BindAll(scope)
+	scope.Bind("version", sabre.String(version))
+
+	prog, err := sabre.ReadCompile(scope, src, sabre.WithBackend(sabre.Bytecode))
+	if err != nil {
+		fatalf("error: %v\n", err)
+	}
+
+	if err := prog.Disassemble(os.Stdout); err != nil {
+		fatalf("error: %v\n", err)
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+
+	"github.com/spy16/sabre/format"
+)
+
+// formatFiles formats the files (or stdin if no files are given) and prints
+// the result to stdout. With -w, files are overwritten instead and with -d
+// the diffs are printed.
+//
+// Usage: sabre fmt [-w] [-d] [file...]
+func formatFiles(args []string) {
+	flags := flag.NewFlagSet("fmt", flag.ExitOnError)
+	write := flags.Bool("w", false, "Write result to the source file instead of stdout")
+	diff := flags.Bool("d", false, "Print diffs instead of the formatted source")
+	_ = flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		if *write {
+			fatalf("error: cannot use -w with standard input\n")
+		}
+
+		src, err := ioutil.ReadAll(os.Stdin)
+		if err != nil {
+			fatalf("error: %v\n", err)
+		}
+
+		if err := formatSource("<standard input>", src, false, *diff); err != nil {
+			fatalf("error: %v\n", err)
+		}
+		return
+	}
+
+	for _, file := range flags.Args() {
+		src, err := ioutil.ReadFile(file)
+		if err != nil {
+			fatalf("error: %v\n", err)
+		}
+
+		if err := formatSource(file, src, *write, *diff); err != nil {
+			fatalf("error: %s: %v\n", file, err)
+		}
+	}
+}
+
+func formatSource(file string, src []byte, write, diff bool) error {
+	res, err := format.Source(src)
+	if err != nil {
+		return err
+	}
+
+	if diff {
+		if !bytes.Equal(src, res) {
+			d, err := diffSource(file, src, res)
+			if err != nil {
+				return err
+			}
+			os.Stdout.Write(d)
+		}
+	}
+
+	if write {
+		if bytes.Equal(src, res) {
+			return nil
+		}
+
+		info, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+		return ioutil.WriteFile(file, res, info.Mode().Perm())
+	}
+
+	if !diff {
+		_, err = os.Stdout.Write(res)
+	}
+	return err
+}
+
+// diffSource returns the unified diff of the sources using the diff
+// command (similar to gofmt).
+func diffSource(file string, a, b []byte) ([]byte, error) {
+	fa, err := writeTemp(a)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(fa)
+
+	fb, err := writeTemp(b)
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(fb)
+
+	out, err := exec.Command("diff", "-u",
+		"--label", file+".orig", "--label", file, fa, fb).Output()
+	if len(out) > 0 {
+		// diff exits with status 1 when files differ.
+		return out, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute diff: %v", err)
+	}
+	return out, nil
+}
+
+func writeTemp(data []byte) (string, error) {
+	f, err := ioutil.TempFile("", "sabrefmt")
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	if _, err := f.Write(data); err != nil {
+		return "", err
+	}
+	return f.Name(), nil
+}
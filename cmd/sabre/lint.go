@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/spy16/sabre"
+	"github.com/spy16/sabre/core"
+)
+
+// lint checks the files statically and prints the diagnostics. Exits with
+// status 1 if any problems are found.
+//
+// Usage: sabre lint [-json] file...
+func lint(args []string) {
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	asJSON := flags.Bool("json", false, "Print diagnostics as JSON")
+	_ = flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		fatalf("usage: sabre lint [-json] file...\n")
+	}
+
+	var diags []lintDiagnostic
+	for _, file := range flags.Args() {
+		found, err := lintFile(file)
+		if err != nil {
+			fatalf("error: %v\n", err)
+		}
+		diags = append(diags, found...)
+	}
+
+	if *asJSON {
+		if diags == nil {
+			diags = []lintDiagnostic{}
+		}
+
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(diags); err != nil {
+			fatalf("error: %v\n", err)
+		}
+	} else {
+		for _, d := range diags {
+			fmt.Printf("%s:%d:%d: %s: %s (%s)\n",
+				d.File, d.Line, d.Column, d.Severity, d.Message, d.Rule)
+		}
+	}
+
+	if len(diags) > 0 {
+		os.Exit(1)
+	}
+}
+
+type lintDiagnostic struct {
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+	Column   int    `json:"column"`
+	Severity string `json:"severity"`
+	Rule     string `json:"rule"`
+	Message  string `json:"message"`
+}
+
+// lintFile reads all the forms in the file and checks them against a scope
+// with core functions bound. Syntax errors are reported as diagnostics. Sets
+// with duplicate elements are read as is so that they are reported by Check
+// along with the other problems.
+func lintFile(file string) ([]lintDiagnostic, error) {
+	fh, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer fh.Close()
+
+	rd := sabre.NewReader(fh)
+	rd.KeepDuplicates(true)
+
+	form, err := rd.All()
+	if err != nil {
+		var readErr sabre.ReadError
+		if !errors.As(err, &readErr) {
+			return nil, err
+		}
+
+		return []lintDiagnostic{{
+			File:     file,
+			Line:     readErr.Line,
+			Column:   readErr.Column,
+			Severity: sabre.SeverityError.String(),
+			Rule:     "syntax",
+			Message:  readErr.Cause.Error(),
+		}}, nil
+	}
+
+	scope := sabre.NewScope(nil)
+	core.BindAll(scope)
+	scope.Bind("version", sabre.String(version))
+
+	var diags []lintDiagnostic
+	for _, d := range sabre.Check(scope, form) {
+		diags = append(diags, lintDiagnostic{
+			File:     file,
+			Line:     d.Line,
+			Column:   d.Column,
+			Severity: d.Severity.String(),
+			Rule:     d.Rule,
+			Message:  d.Message,
+		})
+	}
+
+	return diags, nil
+}
@@ -20,11 +20,37 @@ var executeStr = flag.String("e", "", "Execute string")
 var noREPL = flag.Bool("norepl", false, "Don't start REPL after executing file and string")
 
 func main() {
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "disasm":
+			disasm(os.Args[2:])
+			return
+
+		case "lint":
+			lint(os.Args[2:])
+			return
+
+		case "fmt":
+			formatFiles(os.Args[2:])
+			return
+		}
+	}
+
 	flag.Parse()
 
-	scope := sabre.NewScope(nil)
-	core.BindAll(scope)
-	scope.Bind("version", sabre.String(version))
+	root := sabre.NewScope(nil)
+	core.BindAll(root)
+	root.Bind("version", sabre.String(version))
+
+	// scripts can access files under the working directory using slurp, spit
+	// etc.
+	ctx := core.WithIO(context.Background(), core.IO{
+		In:  os.Stdin,
+		Out: os.Stdout,
+		Err: os.Stderr,
+		FS:  core.DirFS("."),
+	})
+	scope := sabre.WithContext(ctx, root)
 
 	var result interface{}
 	var err error
@@ -9,6 +9,7 @@ import (
 
 	"github.com/chzyer/readline"
 	"github.com/spy16/sabre"
+	"github.com/spy16/sabre/core"
 )
 
 const help = `Sabre %s [Commit: %s]
@@ -133,6 +134,15 @@ func formatResult(v interface{}) string {
 	if v == nil {
 		return "nil"
 	}
+
+	if val, isValue := v.(sabre.Value); isValue {
+		width := readline.GetScreenWidth()
+		if width <= 0 {
+			width = core.PrettyWidth
+		}
+		return sabre.Pretty(val, width)
+	}
+
 	rval := reflect.ValueOf(v)
 	switch rval.Kind() {
 	case reflect.Func:
@@ -2,6 +2,7 @@ package sabre
 
 import (
 	"fmt"
+	"hash/fnv"
 	"reflect"
 	"strings"
 )
@@ -15,6 +16,7 @@ type List struct {
 	// special is set in case if the list represents invocation of
 	// a special form such as def, fn* etc.
 	special func(scope Scope) (Value, error)
+	meta    *HashMap
 }
 
 // Eval performs an invocation.
@@ -27,6 +29,17 @@ func (lf *List) Eval(scope Scope) (Value, error) {
 		return lf.special(scope)
 	}
 
+	if special := getSpecial(lf.Values[0]); special != nil {
+		// list was not analyzed before evaluation (e.g., result of macro
+		// expansion).
+		expr, err := special(scope, lf.Values[1:])
+		if err != nil {
+			return nil, err
+		}
+
+		return expr(scope)
+	}
+
 	target, err := lf.Values[0].Eval(scope)
 	if err != nil {
 		return nil, err
@@ -44,23 +57,33 @@ func (lf List) String() string {
 	return containerString(lf.Values, "(", ")", " ")
 }
 
-func (lf *List) parseSpecial(scope Scope) error {
+// analyze returns an analyzed copy of the list. If the list represents a
+// special form, the copy is bound to the special form implementation.
+func (lf *List) analyze(scope Scope) (Value, error) {
 	if lf.Size() == 0 {
-		return nil
+		return lf, nil
 	}
 
 	special := getSpecial(lf.Values[0])
 	if special == nil {
-		return analyzeSeq(scope, lf)
+		vals, err := analyzeList(scope, lf.Values)
+		if err != nil {
+			return nil, err
+		}
+
+		return &List{Values: vals, Position: lf.Position}, nil
 	}
 
 	expr, err := special(scope, lf.Values[1:])
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	lf.special = expr
-	return nil
+	return &List{
+		Values:   lf.Values,
+		Position: lf.Position,
+		special:  expr,
+	}, nil
 }
 
 func getSpecial(v Value) specialForm {
@@ -77,6 +100,8 @@ func getSpecial(v Value) specialForm {
 type Vector struct {
 	Values
 	Position
+
+	meta *HashMap
 }
 
 // Eval evaluates each value in the vector form and returns the resultant
@@ -87,7 +112,7 @@ func (vf Vector) Eval(scope Scope) (Value, error) {
 		return nil, err
 	}
 
-	return Vector{Values: vals}, nil
+	return Vector{Values: vals, meta: vf.meta}, nil
 }
 
 // Invoke of a vector performs a index lookup. Only arity 1 is allowed
@@ -122,6 +147,8 @@ func (vf Vector) String() string {
 type Set struct {
 	Values
 	Position
+
+	meta *HashMap
 }
 
 // Eval evaluates each value in the set form and returns the resultant
@@ -132,7 +159,7 @@ func (set Set) Eval(scope Scope) (Value, error) {
 		return nil, err
 	}
 
-	return Set{Values: uniq(vals)}, nil
+	return Set{Values: uniq(vals), meta: set.meta}, nil
 }
 
 func (set Set) String() string {
@@ -140,20 +167,401 @@ func (set Set) String() string {
 }
 
 func (set Set) valid() bool {
-	// TODO: Remove this naive solution
-	s := map[string]struct{}{}
-
+	seen := &HashMap{}
 	for _, v := range set.Values {
-		str := v.String()
-		if _, found := s[str]; found {
+		if seen.find(v) >= 0 {
+			return false
+		}
+		seen.put(v, Nil{})
+	}
+
+	return true
+}
+
+// HashMap represents a container for key-value pairs. Keys are compared by
+// value (See equalKeys) and reference types such as atoms are compared by
+// identity. Entries are kept in insertion order.
+type HashMap struct {
+	Position
+
+	entries []mapEntry
+	index   map[interface{}][]int
+	meta    *HashMap
+}
+
+type mapEntry struct {
+	key, val Value
+}
+
+// NewHashMap returns a hash map containing the given key-value pairs. If a
+// key appears more than once, the last value is retained.
+func NewHashMap(kvs ...Value) (*HashMap, error) {
+	if len(kvs)%2 != 0 {
+		return nil, fmt.Errorf("map requires even number of forms, got %d", len(kvs))
+	}
+
+	hm := &HashMap{}
+	for i := 0; i < len(kvs); i += 2 {
+		hm.put(kvs[i], kvs[i+1])
+	}
+
+	return hm, nil
+}
+
+// Eval evaluates all the keys and values in the map and returns the result
+// as a new map.
+func (hm *HashMap) Eval(scope Scope) (Value, error) {
+	kvs, err := evalValueList(scope, hm.kvs())
+	if err != nil {
+		return nil, err
+	}
+
+	return hm.withEntries(kvs), nil
+}
+
+// Invoke of a map performs a lookup. (m key) returns the value of the key
+// or nil and (m key default) returns default if the key is not present.
+func (hm *HashMap) Invoke(scope Scope, args ...Value) (Value, error) {
+	vals, err := evalValueList(scope, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(vals) != 1 && len(vals) != 2 {
+		return nil, fmt.Errorf("call requires 1 or 2 argument(s), got %d", len(vals))
+	}
+
+	return lookup(hm, vals), nil
+}
+
+// Get returns the value associated with the key.
+func (hm *HashMap) Get(key Value) (Value, bool) {
+	if hm == nil {
+		return nil, false
+	}
+
+	idx := hm.find(key)
+	if idx < 0 {
+		return nil, false
+	}
+
+	return hm.entries[idx].val, true
+}
+
+// Assoc returns a copy of the map with the key associated with the value.
+func (hm *HashMap) Assoc(key, val Value) *HashMap {
+	res := hm.withEntries(hm.kvs())
+	res.put(key, val)
+	return res
+}
+
+// Dissoc returns a copy of the map without the key.
+func (hm *HashMap) Dissoc(key Value) *HashMap {
+	var kvs []Value
+	for _, e := range hm.items() {
+		if !equalKeys(e.key, key) {
+			kvs = append(kvs, e.key, e.val)
+		}
+	}
+
+	return hm.withEntries(kvs)
+}
+
+// Keys returns the keys of the map in insertion order.
+func (hm *HashMap) Keys() []Value {
+	var keys []Value
+	for _, e := range hm.items() {
+		keys = append(keys, e.key)
+	}
+	return keys
+}
+
+// Vals returns the values of the map in the order of keys.
+func (hm *HashMap) Vals() []Value {
+	var vals []Value
+	for _, e := range hm.items() {
+		vals = append(vals, e.val)
+	}
+	return vals
+}
+
+// Size returns the number of entries in the map.
+func (hm *HashMap) Size() int {
+	if hm == nil {
+		return 0
+	}
+
+	return len(hm.entries)
+}
+
+func (hm *HashMap) String() string {
+	parts := make([]string, hm.Size())
+	for i, e := range hm.items() {
+		parts[i] = e.key.String() + " " + e.val.String()
+	}
+	return "{" + strings.Join(parts, ", ") + "}"
+}
+
+// put associates the key with the value in place. Must be used only while
+// constructing the map.
+func (hm *HashMap) put(key, val Value) {
+	if idx := hm.find(key); idx >= 0 {
+		hm.entries[idx].val = val
+		return
+	}
+
+	if hm.index == nil {
+		hm.index = map[interface{}][]int{}
+	}
+
+	h := hashKey(key)
+	hm.index[h] = append(hm.index[h], len(hm.entries))
+	hm.entries = append(hm.entries, mapEntry{key: key, val: val})
+}
+
+// find returns the index of the entry with a key equal to 'key' or -1 if
+// there is no such entry.
+func (hm *HashMap) find(key Value) int {
+	for _, idx := range hm.index[hashKey(key)] {
+		if equalKeys(hm.entries[idx].key, key) {
+			return idx
+		}
+	}
+
+	return -1
+}
+
+// kvs returns the keys and values of the map as a flat list.
+func (hm *HashMap) kvs() []Value {
+	var kvs []Value
+	for _, e := range hm.items() {
+		kvs = append(kvs, e.key, e.val)
+	}
+	return kvs
+}
+
+// withEntries returns a new map with the position and metadata of hm and
+// the given key-value pairs.
+func (hm *HashMap) withEntries(kvs []Value) *HashMap {
+	res := &HashMap{}
+	if hm != nil {
+		res.Position, res.meta = hm.Position, hm.meta
+	}
+
+	for i := 0; i+1 < len(kvs); i += 2 {
+		res.put(kvs[i], kvs[i+1])
+	}
+
+	return res
+}
+
+// items returns the entries of the map. Returns nil for a nil map.
+func (hm *HashMap) items() []mapEntry {
+	if hm == nil {
+		return nil
+	}
+
+	return hm.entries
+}
+
+// hashKey returns a Go comparable value such that equal keys (See equalKeys)
+// have equal hashes. Values of comparable types (e.g., numbers, strings,
+// keywords, pointers to atoms) are used as is. Collections are hashed by
+// their items and other values by their type only and told apart using
+// equalKeys.
+func hashKey(v Value) interface{} {
+	switch key := v.(type) {
+	case Symbol:
+		return Symbol{Value: key.Value}
+
+	case BigInt:
+		if key.Int == nil {
+			return reflect.TypeOf(v)
+		}
+		return bigIntKey(key.Int.String())
+
+	case Time:
+		return key.UTC().Round(0)
+
+	case *List:
+		return collectionKey{kind: reflect.TypeOf(v), sum: hashValues(key.Values, true)}
+
+	case Vector:
+		return collectionKey{kind: reflect.TypeOf(v), sum: hashValues(key.Values, true)}
+
+	case Set:
+		return collectionKey{kind: reflect.TypeOf(v), sum: hashValues(key.Values, false)}
+
+	case *HashMap:
+		var sum uint64
+		for _, e := range key.items() {
+			sum += hashValues([]Value{e.key, e.val}, true)
+		}
+		return collectionKey{kind: reflect.TypeOf(v), sum: sum}
+	}
+
+	if rt := reflect.TypeOf(v); rt != nil && !rt.Comparable() {
+		return rt
+	}
+
+	return v
+}
+
+// bigIntKey is the hash key of big integers.
+type bigIntKey string
+
+// collectionKey is the hash key of collections.
+type collectionKey struct {
+	kind reflect.Type
+	sum  uint64
+}
+
+// hashValues combines the hash keys of the values into a single hash. If
+// 'ordered' is false, the result does not depend on the order of values.
+func hashValues(vals []Value, ordered bool) uint64 {
+	var sum uint64
+	for _, v := range vals {
+		key := hashKey(v)
+
+		// reference types are hashed by identity and not by the current
+		// state of the value they point to.
+		h := fnv.New64a()
+		switch reflect.ValueOf(key).Kind() {
+		case reflect.Ptr, reflect.Chan, reflect.UnsafePointer:
+			fmt.Fprintf(h, "%T:%p", key, key)
+
+		default:
+			fmt.Fprintf(h, "%T:%v", key, key)
+		}
+
+		if ordered {
+			sum = sum*31 + h.Sum64()
+		} else {
+			sum += h.Sum64()
+		}
+	}
+
+	return sum
+}
+
+// equalKeys returns true if the values are equal when used as map keys.
+// Collections are equal if their items are equal, and positions and metadata
+// are ignored. Big integers are compared by value and times by the instant
+// they represent. Values of comparable types are compared using ==, Go
+// functions by their code pointer and other values using reflect.DeepEqual.
+func equalKeys(a, b Value) bool {
+	if reflect.TypeOf(a) != reflect.TypeOf(b) {
+		return false
+	}
+
+	switch x := a.(type) {
+	case Symbol:
+		return x.Value == b.(Symbol).Value
+
+	case BigInt:
+		y := b.(BigInt)
+		if x.Int == nil || y.Int == nil {
+			return x.Int == y.Int
+		}
+		return x.Int.Cmp(y.Int) == 0
+
+	case Time:
+		return x.Equal(b.(Time).Time)
+
+	case *List:
+		return equalValues(x.Values, b.(*List).Values)
+
+	case Vector:
+		return equalValues(x.Values, b.(Vector).Values)
+
+	case Set:
+		y := b.(Set)
+		return len(x.Values) == len(y.Values) &&
+			containsAll(x.Values, y.Values) && containsAll(y.Values, x.Values)
+
+	case *HashMap:
+		y := b.(*HashMap)
+		if x.Size() != y.Size() {
+			return false
+		}
+
+		for _, e := range x.items() {
+			v, found := y.Get(e.key)
+			if !found || !equalKeys(e.val, v) {
+				return false
+			}
+		}
+		return true
+	}
+
+	rt := reflect.TypeOf(a)
+	if rt == nil || rt.Comparable() {
+		return a == b
+	}
+
+	if rt.Kind() == reflect.Func {
+		return reflect.ValueOf(a).Pointer() == reflect.ValueOf(b).Pointer()
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+func equalValues(xs, ys []Value) bool {
+	if len(xs) != len(ys) {
+		return false
+	}
+
+	for i := range xs {
+		if !equalKeys(xs[i], ys[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// containsAll returns true if every value in 'xs' is equal to some value in
+// 'ys'.
+func containsAll(xs, ys []Value) bool {
+	for _, x := range xs {
+		found := false
+		for _, y := range ys {
+			if equalKeys(x, y) {
+				found = true
+				break
+			}
+		}
+
+		if !found {
 			return false
 		}
-		s[v.String()] = struct{}{}
 	}
 
 	return true
 }
 
+// getter is implemented by values which support lookup by key (i.e., maps
+// and records).
+type getter interface {
+	Get(key Value) (Value, bool)
+}
+
+// lookup returns the value of key args[0] in 'm' or args[1] (if present)
+// when the key is not found or 'm' is nil.
+func lookup(m getter, args []Value) Value {
+	if m != nil {
+		if v, found := m.Get(args[0]); found {
+			return v
+		}
+	}
+
+	if len(args) > 1 {
+		return args[1]
+	}
+
+	return Nil{}
+}
+
 // Module represents a group of forms. Evaluating a module leads to evaluation
 // of each form in order and result will be the result of last evaluation.
 type Module []Value
@@ -203,16 +611,14 @@ func containerString(vals []Value, begin, end, sep string) string {
 }
 
 func uniq(items []Value) []Value {
-	// TODO: remove this naive implementation
-	vs := map[string]Value{}
-	for _, v := range items {
-		s := v.String()
-		vs[s] = v
-	}
+	seen := &HashMap{}
 
 	var set []Value
-	for _, v := range vs {
-		set = append(set, v)
+	for _, v := range items {
+		if seen.find(v) < 0 {
+			seen.put(v, Nil{})
+			set = append(set, v)
+		}
 	}
 
 	return set
@@ -2,8 +2,10 @@ package sabre_test
 
 import (
 	"fmt"
+	"math/big"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/spy16/sabre"
 )
@@ -276,3 +278,196 @@ func TestVector_Invoke(t *testing.T) {
 		})
 	}
 }
+
+func TestHashMap_Invoke(t *testing.T) {
+	t.Parallel()
+
+	hm, err := sabre.NewHashMap(sabre.Keyword("a"), sabre.Int64(1), sabre.String("b"), sabre.Nil{})
+	if err != nil {
+		t.Fatalf("NewHashMap() unexpected error: %v", err)
+	}
+
+	table := []struct {
+		name    string
+		target  sabre.Invokable
+		args    []sabre.Value
+		want    sabre.Value
+		wantErr bool
+	}{
+		{
+			name:    "NoArgs",
+			target:  hm,
+			args:    []sabre.Value{},
+			wantErr: true,
+		},
+		{
+			name:   "Found",
+			target: hm,
+			args:   []sabre.Value{sabre.Keyword("a")},
+			want:   sabre.Int64(1),
+		},
+		{
+			name:   "FoundNil",
+			target: hm,
+			args:   []sabre.Value{sabre.String("b"), sabre.Int64(0)},
+			want:   sabre.Nil{},
+		},
+		{
+			name:   "NotFound",
+			target: hm,
+			args:   []sabre.Value{sabre.Keyword("b")},
+			want:   sabre.Nil{},
+		},
+		{
+			name:   "Default",
+			target: hm,
+			args:   []sabre.Value{sabre.Keyword("b"), sabre.Int64(0)},
+			want:   sabre.Int64(0),
+		},
+		{
+			name:   "Keyword",
+			target: sabre.Keyword("a"),
+			args:   []sabre.Value{hm},
+			want:   sabre.Int64(1),
+		},
+		{
+			name:   "KeywordNotMap",
+			target: sabre.Keyword("a"),
+			args:   []sabre.Value{sabre.Int64(1), sabre.Int64(0)},
+			want:   sabre.Int64(0),
+		},
+		{
+			name:    "KeywordTooManyArgs",
+			target:  sabre.Keyword("a"),
+			args:    []sabre.Value{hm, sabre.Int64(0), sabre.Int64(0)},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range table {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.target.Invoke(nil, tt.args...)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Invoke() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Invoke() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHashMap_Assoc(t *testing.T) {
+	t.Parallel()
+
+	hm, err := sabre.NewHashMap(sabre.Keyword("a"), sabre.Int64(1), sabre.Keyword("b"), sabre.Int64(2))
+	if err != nil {
+		t.Fatalf("NewHashMap() unexpected error: %v", err)
+	}
+
+	updated := hm.Assoc(sabre.Keyword("a"), sabre.Int64(3)).Assoc(sabre.Keyword("c"), sabre.Int64(4))
+	if got, want := updated.String(), "{:a 3, :b 2, :c 4}"; got != want {
+		t.Errorf("Assoc() got = %s, want %s", got, want)
+	}
+
+	removed := updated.Dissoc(sabre.Keyword("b"))
+	if got, want := removed.String(), "{:a 3, :c 4}"; got != want {
+		t.Errorf("Dissoc() got = %s, want %s", got, want)
+	}
+
+	if got, want := hm.String(), "{:a 1, :b 2}"; got != want {
+		t.Errorf("original map modified: got = %s, want %s", got, want)
+	}
+}
+
+func TestHashMap_KeyEquality(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Functions", func(t *testing.T) {
+		got, err := sabre.ReadEvalStr(nil, `{(fn* [] 1) 1 (fn* [] 2) 2}`)
+		if err != nil {
+			t.Fatalf("ReadEvalStr() unexpected error: %v", err)
+		}
+
+		if size := got.(*sabre.HashMap).Size(); size != 2 {
+			t.Errorf("Size() got = %d, want 2", size)
+		}
+	})
+
+	t.Run("Atom", func(t *testing.T) {
+		atom := sabre.NewAtom(sabre.Int64(1))
+		hm, err := sabre.NewHashMap(atom, sabre.Keyword("found"))
+		if err != nil {
+			t.Fatalf("NewHashMap() unexpected error: %v", err)
+		}
+
+		if _, err := atom.Reset(nil, sabre.Int64(2)); err != nil {
+			t.Fatalf("Reset() unexpected error: %v", err)
+		}
+
+		if v, found := hm.Get(atom); !found || v != sabre.Keyword("found") {
+			t.Errorf("Get() got = (%v, %v), want (:found, true)", v, found)
+		}
+
+		if _, found := hm.Get(sabre.NewAtom(sabre.Int64(2))); found {
+			t.Errorf("Get() with a different atom expected to not find the key")
+		}
+	})
+
+	t.Run("Collections", func(t *testing.T) {
+		got, err := sabre.ReadEvalStr(nil, `{[1 #{:a :b}] :vec (quote sym) :sym}`)
+		if err != nil {
+			t.Fatalf("ReadEvalStr() unexpected error: %v", err)
+		}
+		hm := got.(*sabre.HashMap)
+
+		vec := sabre.Vector{Values: []sabre.Value{
+			sabre.Int64(1),
+			sabre.Set{Values: []sabre.Value{sabre.Keyword("b"), sabre.Keyword("a")}},
+		}}
+		if v, found := hm.Get(vec); !found || v != sabre.Keyword("vec") {
+			t.Errorf("Get(%s) got = (%v, %v), want (:vec, true)", vec, v, found)
+		}
+
+		if v, found := hm.Get(sabre.Symbol{Value: "sym"}); !found || v != sabre.Keyword("sym") {
+			t.Errorf("Get(sym) got = (%v, %v), want (:sym, true)", v, found)
+		}
+	})
+
+	t.Run("BigInt", func(t *testing.T) {
+		hm, err := sabre.NewHashMap(sabre.BigInt{Int: big.NewInt(10)}, sabre.Keyword("ten"))
+		if err != nil {
+			t.Fatalf("NewHashMap() unexpected error: %v", err)
+		}
+
+		if v, found := hm.Get(sabre.BigInt{Int: big.NewInt(10)}); !found || v != sabre.Keyword("ten") {
+			t.Errorf("Get(10N) got = (%v, %v), want (:ten, true)", v, found)
+		}
+	})
+
+	t.Run("Time", func(t *testing.T) {
+		now := time.Now()
+		hm, err := sabre.NewHashMap(sabre.Time{Time: now}, sabre.Keyword("now"))
+		if err != nil {
+			t.Fatalf("NewHashMap() unexpected error: %v", err)
+		}
+
+		key := sabre.Time{Time: now.Round(0).In(time.FixedZone("X", 3600))}
+		if v, found := hm.Get(key); !found || v != sabre.Keyword("now") {
+			t.Errorf("Get(%s) got = (%v, %v), want (:now, true)", key, v, found)
+		}
+	})
+
+	t.Run("SetElements", func(t *testing.T) {
+		got, err := sabre.ReadEvalStr(nil, `(let* [x 1] #{x 1 {:a x :b 2} {:b 2 :a 1}})`)
+		if err != nil {
+			t.Fatalf("ReadEvalStr() unexpected error: %v", err)
+		}
+
+		if n := len(got.(sabre.Set).Values); n != 2 {
+			t.Errorf("ReadEvalStr() got = %s, want 2 elements", got)
+		}
+	})
+}
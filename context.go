@@ -0,0 +1,79 @@
+package sabre
+
+import "context"
+
+// WithContext returns a scope that carries the given context and delegates
+// everything else to 'scope'. Forms evaluated within the returned scope that
+// block or spawn goroutines (e.g., go, future, promise, channel operations)
+// stop waiting once the context is cancelled.
+func WithContext(ctx context.Context, scope Scope) Scope {
+	return contextScope{Scope: scope, ctx: ctx}
+}
+
+// Context returns the context associated with the scope. The context is
+// searched along the chain of calls that led to the scope. Returns a non-nil
+// background context if no context is associated.
+func Context(scope Scope) context.Context {
+	var ctx context.Context
+
+	walkDynamic(scope, func(s Scope) bool {
+		switch v := s.(type) {
+		case contextScope:
+			ctx = v.ctx
+
+		case *MapScope:
+			ctx = v.ctx
+
+		case localScope:
+			ctx = v.env.ctx
+
+		case vmScope:
+			ctx = v.vm.ctx
+		}
+		return ctx != nil
+	})
+
+	if ctx == nil {
+		return context.Background()
+	}
+
+	return ctx
+}
+
+type contextScope struct {
+	Scope
+	ctx context.Context
+}
+
+// walkDynamic visits the scopes along the dynamic chain starting from 'scope'
+// until 'visit' returns true. Unlike the lexical chain obtained by Parent(),
+// the dynamic chain continues from function invocation scopes to the scope
+// of the call site.
+func walkDynamic(scope Scope, visit func(s Scope) bool) {
+	for s := scope; s != nil; {
+		if visit(s) {
+			return
+		}
+
+		switch v := s.(type) {
+		case contextScope:
+			s = v.Scope
+
+		case localScope:
+			s = v.env.dynamicParent()
+
+		case vmScope:
+			s = v.vm.dynamic()
+
+		case *MapScope:
+			if v.caller != nil {
+				s = v.caller
+			} else {
+				s = v.parent
+			}
+
+		default:
+			s = s.Parent()
+		}
+	}
+}
@@ -0,0 +1,153 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/spy16/sabre"
+)
+
+// MakeChan creates a new channel. (chan) creates an unbuffered channel and
+// (chan n) creates a channel with buffer size n.
+func MakeChan(vals []sabre.Value) (sabre.Value, error) {
+	if err := verifyArgCount([]int{0, 1}, vals); err != nil {
+		return nil, err
+	}
+
+	if len(vals) == 0 {
+		return sabre.NewChan(0), nil
+	}
+
+	size, isInt := vals[0].(sabre.Int64)
+	if !isInt || size < 0 {
+		return nil, fmt.Errorf("buffer size must be a non-negative integer, not '%v'", vals[0])
+	}
+
+	return sabre.NewChan(int(size)), nil
+}
+
+// Take receives a value from the channel and blocks until a value is
+// available. Returns nil if the channel is closed. Blocked takes are
+// cancelled if the context of the evaluation is cancelled.
+func Take(scope sabre.Scope, args []sabre.Value) (sabre.Value, error) {
+	vals, err := evalValueList(scope, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyArgCount([]int{1}, vals); err != nil {
+		return nil, err
+	}
+
+	ch, err := toChan(vals[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return ch.Take(sabre.Context(scope))
+}
+
+// Put sends a value into the channel and blocks until the value is accepted.
+// Returns false if the channel is closed.
+func Put(scope sabre.Scope, args []sabre.Value) (sabre.Value, error) {
+	vals, err := evalValueList(scope, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyArgCount([]int{2}, vals); err != nil {
+		return nil, err
+	}
+
+	ch, err := toChan(vals[0])
+	if err != nil {
+		return nil, err
+	}
+
+	sent, err := ch.Put(sabre.Context(scope), vals[1])
+	return sabre.Bool(sent), err
+}
+
+// CloseChan closes the channel.
+func CloseChan(vals []sabre.Value) (sabre.Value, error) {
+	if err := verifyArgCount([]int{1}, vals); err != nil {
+		return nil, err
+	}
+
+	ch, err := toChan(vals[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return sabre.Nil{}, ch.Close()
+}
+
+// Alts completes at-most one of the channel operations given as a vector
+// and returns a vector [val channel]. Operations are either channels to take
+// from or vectors [channel value] to put into. If the options contain
+// ':default val' and no operation is ready, [val :default] is returned.
+func Alts(scope sabre.Scope, args []sabre.Value) (sabre.Value, error) {
+	vals, err := evalValueList(scope, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyArgCount([]int{1, 3}, vals); err != nil {
+		return nil, err
+	}
+
+	ops, isVector := vals[0].(sabre.Vector)
+	if !isVector {
+		return nil, fmt.Errorf("first argument must be a vector of operations, not '%s'",
+			reflect.TypeOf(vals[0]))
+	}
+
+	var def sabre.Value
+	if len(vals) == 3 {
+		if vals[1] != sabre.Keyword("default") {
+			return nil, fmt.Errorf("unknown option '%v'", vals[1])
+		}
+		def = vals[2]
+	}
+
+	v, ch, err := sabre.Alts(sabre.Context(scope), ops.Values, def)
+	if err != nil {
+		return nil, err
+	}
+
+	if ch == nil {
+		return sabre.Vector{Values: []sabre.Value{v, sabre.Keyword("default")}}, nil
+	}
+
+	return sabre.Vector{Values: []sabre.Value{v, ch}}, nil
+}
+
+// Timeout returns a channel which will be closed after given number of
+// milliseconds.
+func Timeout(vals []sabre.Value) (sabre.Value, error) {
+	if err := verifyArgCount([]int{1}, vals); err != nil {
+		return nil, err
+	}
+
+	ms, isInt := vals[0].(sabre.Int64)
+	if !isInt {
+		return nil, fmt.Errorf("timeout must be integer milliseconds, not '%v'", vals[0])
+	}
+
+	ch := sabre.NewChan(0)
+	time.AfterFunc(time.Duration(ms)*time.Millisecond, func() {
+		_ = ch.Close()
+	})
+
+	return ch, nil
+}
+
+func toChan(v sabre.Value) (*sabre.Chan, error) {
+	ch, isChan := v.(*sabre.Chan)
+	if !isChan {
+		return nil, fmt.Errorf("expecting channel, not '%s'", reflect.TypeOf(v))
+	}
+
+	return ch, nil
+}
@@ -1,13 +1,20 @@
 package core
 
 import (
+	"math"
 	"reflect"
+	"strings"
+	"time"
 
 	"github.com/spy16/sabre"
 )
 
-// BindAll binds all core functions into the given scope.
+// BindAll binds all core functions into the given scope. Each call creates
+// a new hierarchy which is used by derive, isa? and the multimethods defined
+// using defmulti.
 func BindAll(scope sabre.Scope) error {
+	hierarchy := sabre.NewHierarchy()
+
 	core := map[string]sabre.Value{
 		"eval":     sabre.GoFunc(Eval),
 		"not":      Fn(Not),
@@ -17,6 +24,20 @@ func BindAll(scope sabre.Scope) error {
 		"set":      makeContainer(sabre.Set{}),
 		"list":     makeContainer(&sabre.List{}),
 		"vector":   makeContainer(sabre.Vector{}),
+		"hash-map": makeContainer(&sabre.HashMap{}),
+		"chan":     Fn(MakeChan),
+		"<!":       sabre.GoFunc(Take),
+		">!":       sabre.GoFunc(Put),
+		"close!":   Fn(CloseChan),
+		"alts!":    sabre.GoFunc(Alts),
+		"timeout":  Fn(Timeout),
+		"atom":     sabre.GoFunc(MakeAtom),
+		"deref":    sabre.GoFunc(Deref),
+		"swap!":    sabre.GoFunc(Swap),
+		"reset!":   sabre.GoFunc(Reset),
+		"promise":  sabre.GoFunc(MakePromise),
+		"deliver":  Fn(Deliver),
+		"force":    sabre.GoFunc(Force),
 		"nil?":     IsType(reflect.TypeOf(sabre.Nil{})),
 		"int?":     IsType(reflect.TypeOf(sabre.Int64(0))),
 		"set?":     IsType(reflect.TypeOf(sabre.Set{})),
@@ -27,6 +48,153 @@ func BindAll(scope sabre.Scope) error {
 		"vector?":  IsType(reflect.TypeOf(sabre.Vector{})),
 		"keyword?": IsType(reflect.TypeOf(sabre.Keyword(""))),
 		"symbol?":  IsType(reflect.TypeOf(sabre.Symbol{})),
+		"chan?":    IsType(reflect.TypeOf(&sabre.Chan{})),
+		"atom?":    IsType(reflect.TypeOf(&sabre.Atom{})),
+		"map?":     IsType(reflect.TypeOf(&sabre.HashMap{})),
+		"inst?":    IsType(reflect.TypeOf(sabre.Time{})),
+		"uuid?":    IsType(reflect.TypeOf(sabre.UUID{})),
+
+		"compare-and-set!": sabre.GoFunc(CompareAndSet),
+		"set-validator!":   sabre.GoFunc(SetValidator),
+		"add-watch":        Fn(AddWatch),
+		"remove-watch":     Fn(RemoveWatch),
+		"realized?":        Fn(IsRealized),
+		"future-cancel":    Fn(CancelFuture),
+		"future?":          IsType(reflect.TypeOf(&sabre.Future{})),
+
+		"re-pattern": Fn(RePattern),
+		"re-matcher": Fn(ReMatcher),
+		"re-find":    Fn(ReFind),
+		"re-matches": Fn(ReMatches),
+		"re-seq":     Fn(ReSeq),
+		"re-groups":  Fn(ReGroups),
+		"regex?":     IsType(reflect.TypeOf(sabre.Regex{})),
+
+		"count":  Fn(Count),
+		"subs":   Fn(Subs),
+		"format": Fn(Format),
+
+		"string/split":        Fn(Split),
+		"string/join":         Fn(Join),
+		"string/chars":        Fn(Chars),
+		"string/trim":         StringFn(strings.TrimSpace),
+		"string/triml":        StringFn(trimLeft),
+		"string/trimr":        StringFn(trimRight),
+		"string/upper-case":   StringFn(strings.ToUpper),
+		"string/lower-case":   StringFn(strings.ToLower),
+		"string/starts-with?": StringPredicate(strings.HasPrefix),
+		"string/ends-with?":   StringPredicate(strings.HasSuffix),
+		"string/includes?":    StringPredicate(strings.Contains),
+		"string/index-of":     Fn(IndexOf),
+		"string/replace":      sabre.GoFunc(Replace),
+		"string/blank?":       Fn(IsBlank),
+
+		"math/PI":        sabre.Float64(math.Pi),
+		"math/E":         sabre.Float64(math.E),
+		"math/abs":       MathFn(math.Abs),
+		"math/sqrt":      MathFn(math.Sqrt),
+		"math/cbrt":      MathFn(math.Cbrt),
+		"math/pow":       MathFn2(math.Pow),
+		"math/exp":       MathFn(math.Exp),
+		"math/log":       MathFn(math.Log),
+		"math/log10":     MathFn(math.Log10),
+		"math/log2":      MathFn(math.Log2),
+		"math/sin":       MathFn(math.Sin),
+		"math/cos":       MathFn(math.Cos),
+		"math/tan":       MathFn(math.Tan),
+		"math/asin":      MathFn(math.Asin),
+		"math/acos":      MathFn(math.Acos),
+		"math/atan":      MathFn(math.Atan),
+		"math/atan2":     MathFn2(math.Atan2),
+		"math/sinh":      MathFn(math.Sinh),
+		"math/cosh":      MathFn(math.Cosh),
+		"math/tanh":      MathFn(math.Tanh),
+		"math/hypot":     MathFn2(math.Hypot),
+		"math/floor":     MathFn(math.Floor),
+		"math/ceil":      MathFn(math.Ceil),
+		"math/round":     MathFn(math.Round),
+		"math/trunc":     MathFn(math.Trunc),
+		"math/NaN?":      Fn(IsNaN),
+		"math/infinite?": Fn(IsInfinite),
+
+		"rand":     sabre.GoFunc(Rand),
+		"rand-int": sabre.GoFunc(RandInt),
+		"rand-nth": sabre.GoFunc(RandNth),
+		"shuffle":  sabre.GoFunc(Shuffle),
+
+		"time/RFC3339":     sabre.String(time.RFC3339),
+		"time/RFC3339Nano": sabre.String(time.RFC3339Nano),
+		"time/RFC1123":     sabre.String(time.RFC1123),
+		"time/Kitchen":     sabre.String(time.Kitchen),
+		"time/now":         sabre.GoFunc(Now),
+		"time/parse":       Fn(ParseTime),
+		"time/format":      Fn(FormatTime),
+		"time/duration":    Fn(MakeDuration),
+		"time/plus":        Fn(Plus),
+		"time/minus":       Fn(Minus),
+		"time/before?":     TimeComparator(time.Time.Before),
+		"time/after?":      TimeComparator(time.Time.After),
+		"time/same?":       TimeComparator(time.Time.Equal),
+		"time/in-zone":     Fn(InZone),
+		"time/field":       Fn(TimeField),
+		"duration?":        IsType(reflect.TypeOf(sabre.Duration(0))),
+
+		"defrecord":   Macro("defrecord", DefRecord),
+		"defprotocol": Macro("defprotocol", DefProtocol),
+		"extend-type": Macro("extend-type", ExtendType),
+		"extend":      Fn(Extend),
+		"satisfies?":  Fn(Satisfies),
+		"record?":     IsType(reflect.TypeOf(sabre.Record{})),
+
+		"defmulti":      Macro("defmulti", DefMulti(hierarchy)),
+		"defmethod":     Macro("defmethod", DefMethod),
+		"prefer-method": Fn(PreferMethod),
+		"remove-method": Fn(RemoveMethod),
+		"derive":        Derive(hierarchy),
+		"underive":      Underive(hierarchy),
+		"isa?":          IsA(hierarchy),
+		"parents":       Parents(hierarchy),
+		"ancestors":     Ancestors(hierarchy),
+
+		"Nil":       NewType(reflect.TypeOf(sabre.Nil{})),
+		"Bool":      NewType(reflect.TypeOf(sabre.Bool(false))),
+		"Int64":     NewType(reflect.TypeOf(sabre.Int64(0))),
+		"BigInt":    NewType(reflect.TypeOf(sabre.BigInt{})),
+		"Float64":   NewType(reflect.TypeOf(sabre.Float64(0))),
+		"String":    NewType(reflect.TypeOf(sabre.String(""))),
+		"Character": NewType(reflect.TypeOf(sabre.Character(0))),
+		"Keyword":   NewType(reflect.TypeOf(sabre.Keyword(""))),
+		"Symbol":    NewType(reflect.TypeOf(sabre.Symbol{})),
+		"List":      NewType(reflect.TypeOf(&sabre.List{})),
+		"Vector":    NewType(reflect.TypeOf(sabre.Vector{})),
+		"Set":       NewType(reflect.TypeOf(sabre.Set{})),
+		"HashMap":   NewType(reflect.TypeOf(&sabre.HashMap{})),
+		"Regex":     NewType(reflect.TypeOf(sabre.Regex{})),
+		"Time":      NewType(reflect.TypeOf(sabre.Time{})),
+		"Duration":  NewType(reflect.TypeOf(sabre.Duration(0))),
+		"UUID":      NewType(reflect.TypeOf(sabre.UUID{})),
+
+		"json/parse":     Fn(ParseJSON),
+		"json/stringify": Fn(StringifyJSON),
+
+		"meta":      Fn(Meta),
+		"with-meta": Fn(WithMeta),
+		"vary-meta": sabre.GoFunc(VaryMeta),
+
+		"pr-str":  Fn(PrStr),
+		"pr":      StreamPrinter(true, false),
+		"prn":     StreamPrinter(true, true),
+		"print":   StreamPrinter(false, false),
+		"println": StreamPrinter(false, true),
+		"pprint":  sabre.GoFunc(StreamPrettyPrinter),
+
+		"*in*":      StdIn,
+		"*out*":     StdOut,
+		"*err*":     StdErr,
+		"slurp":     sabre.GoFunc(Slurp),
+		"spit":      sabre.GoFunc(Spit),
+		"line-seq":  sabre.GoFunc(LineSeq),
+		"read-line": sabre.GoFunc(ReadLine),
 	}
 
 	for sym, val := range core {
@@ -49,7 +217,7 @@ func Eval(scope sabre.Scope, args []sabre.Value) (sabre.Value, error) {
 		return nil, err
 	}
 
-	return vals[0].Eval(scope)
+	return sabre.Eval(scope, vals[0])
 }
 
 // Not returns the negated version of the argument value.
@@ -19,6 +19,17 @@ func TestCore(t *testing.T) {
 		want     sabre.Value
 		wantErr  bool
 	}{
+		{
+			name: "MakeString",
+			fn:   core.Fn(core.MakeString),
+			args: []sabre.Value{
+				sabre.String("say \"hi\" "),
+				sabre.Character('λ'),
+				sabre.Float64(1.5),
+				sabre.Vector{Values: []sabre.Value{sabre.String("a")}},
+			},
+			want: sabre.String(`say "hi" λ1.5["a"]`),
+		},
 		{
 			name:    "Not_InsufficientArgs",
 			fn:      core.Fn(core.Not),
@@ -49,6 +60,34 @@ func TestCore(t *testing.T) {
 			args: []sabre.Value{sabre.Bool(true)},
 			want: sabre.Bool(false),
 		},
+		{
+			name:    "MakeChan_InvalidSize",
+			fn:      core.Fn(core.MakeChan),
+			args:    []sabre.Value{sabre.Int64(-1)},
+			wantErr: true,
+		},
+		{
+			name:    "Take_NotChan",
+			fn:      sabre.GoFunc(core.Take),
+			args:    []sabre.Value{sabre.Int64(1)},
+			wantErr: true,
+		},
+		{
+			name:    "Alts_NotVector",
+			fn:      sabre.GoFunc(core.Alts),
+			args:    []sabre.Value{sabre.Int64(1)},
+			wantErr: true,
+		},
+		{
+			name: "Alts_Default",
+			fn:   sabre.GoFunc(core.Alts),
+			args: []sabre.Value{
+				sabre.Vector{Values: []sabre.Value{sabre.NewChan(0)}},
+				sabre.Keyword("default"),
+				sabre.Int64(1),
+			},
+			want: sabre.Vector{Values: []sabre.Value{sabre.Int64(1), sabre.Keyword("default")}},
+		},
 	}
 
 	for _, tt := range table {
@@ -70,3 +109,244 @@ func TestCore(t *testing.T) {
 		})
 	}
 }
+
+func TestAsync(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		name    string
+		src     string
+		want    sabre.Value
+		wantErr bool
+	}{
+		{
+			name: "GoBlock",
+			src:  `(<! (go 10))`,
+			want: sabre.Int64(10),
+		},
+		{
+			name: "PutTake",
+			src: `
+(def ch (chan 1))
+(>! ch :hello)
+(<! ch)`,
+			want: sabre.Keyword("hello"),
+		},
+		{
+			name: "Producer",
+			src: `
+(def ch (chan))
+(go (>! ch 1) (close! ch))
+[(<! ch) (<! ch)]`,
+			want: sabre.Vector{Values: []sabre.Value{sabre.Int64(1), sabre.Nil{}}},
+		},
+		{
+			name: "AltsTimeout",
+			src:  `((alts! [(chan) (timeout 10)]) 0)`,
+			want: sabre.Nil{},
+		},
+		{
+			name:    "GoBlockFailure",
+			src:     `(<! (go (throw "failed")))`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			scope := sabre.NewScope(nil)
+			if err := core.BindAll(scope); err != nil {
+				t.Fatalf("BindAll() unexpected error: %v", err)
+			}
+
+			got, err := sabre.ReadEvalStr(scope, tt.src)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRef(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		name    string
+		src     string
+		want    sabre.Value
+		wantErr bool
+	}{
+		{
+			name: "Deref",
+			src:  `(def a (atom 1)) @a`,
+			want: sabre.Int64(1),
+		},
+		{
+			name: "Swap",
+			src:  `(def a (atom 1)) (swap! a inc) (swap! a inc) (deref a)`,
+			want: sabre.Int64(3),
+		},
+		{
+			name: "Reset",
+			src:  `(def a (atom 1)) (reset! a [1 2]) @a`,
+			want: sabre.Vector{Values: []sabre.Value{sabre.Int64(1), sabre.Int64(2)}},
+		},
+		{
+			name: "CompareAndSet",
+			src:  `(def a (atom 1)) [(compare-and-set! a 2 3) (compare-and-set! a 1 3) @a]`,
+			want: sabre.Vector{Values: []sabre.Value{sabre.Bool(false), sabre.Bool(true), sabre.Int64(3)}},
+		},
+		{
+			name:    "Validator",
+			src:     `(def a (atom 1 :validator int?)) (reset! a "hello")`,
+			wantErr: true,
+		},
+		{
+			name: "Watch",
+			src: `
+(def seen (atom nil))
+(def a (atom 1))
+(add-watch a :w (fn* [k r old new] (reset! seen [k old new])))
+(reset! a 2)
+@seen`,
+			want: sabre.Vector{Values: []sabre.Value{sabre.Keyword("w"), sabre.Int64(1), sabre.Int64(2)}},
+		},
+		{
+			name:    "DerefInvalid",
+			src:     `(deref 10)`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			scope := sabre.NewScope(nil)
+			if err := core.BindAll(scope); err != nil {
+				t.Fatalf("BindAll() unexpected error: %v", err)
+			}
+			_ = scope.BindGo("inc", func(i sabre.Int64) sabre.Int64 { return i + 1 })
+
+			got, err := sabre.ReadEvalStr(scope, tt.src)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFutures(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		name    string
+		src     string
+		want    sabre.Value
+		wantErr bool
+	}{
+		{
+			name: "Future",
+			src:  `@(future :done)`,
+			want: sabre.Keyword("done"),
+		},
+		{
+			name: "FutureTimeout",
+			src:  `(deref (future (<! (chan))) 1 :timeout)`,
+			want: sabre.Keyword("timeout"),
+		},
+		{
+			name: "FutureCancel",
+			src:  `(def f (future (<! (chan)))) [(future-cancel f) (realized? f)]`,
+			want: sabre.Vector{Values: []sabre.Value{sabre.Bool(true), sabre.Bool(true)}},
+		},
+		{
+			name: "Promise",
+			src: `
+(def p (promise))
+(future (deliver p 10))
+[@p (realized? p) (deliver p 20)]`,
+			want: sabre.Vector{Values: []sabre.Value{sabre.Int64(10), sabre.Bool(true), sabre.Nil{}}},
+		},
+		{
+			name: "Delay",
+			src: `
+(def a (atom 0))
+(def d (delay (swap! a inc)))
+[(realized? d) (force d) @d (realized? d) @a]`,
+			want: sabre.Vector{Values: []sabre.Value{
+				sabre.Bool(false), sabre.Int64(1), sabre.Int64(1), sabre.Bool(true), sabre.Int64(1),
+			}},
+		},
+		{
+			name:    "DelaySelfDeref",
+			src:     `(def d (delay @d)) @d`,
+			wantErr: true,
+		},
+		{
+			name:    "DelayCycle",
+			src:     `(def a (delay (force b))) (def b (delay @a)) @a`,
+			wantErr: true,
+		},
+		{
+			name:    "FutureFailure",
+			src:     `@(future (throw "failed"))`,
+			wantErr: true,
+		},
+		{
+			name:    "RealizedInvalid",
+			src:     `(realized? 10)`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range table {
+		t.Run(tt.name, func(t *testing.T) {
+			scope := sabre.NewScope(nil)
+			if err := core.BindAll(scope); err != nil {
+				t.Fatalf("BindAll() unexpected error: %v", err)
+			}
+			_ = scope.BindGo("inc", func(i sabre.Int64) sabre.Int64 { return i + 1 })
+
+			got, err := sabre.ReadEvalStr(scope, tt.src)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestStringInterpolation(t *testing.T) {
+	t.Parallel()
+
+	scope := sabre.NewScope(nil)
+	if err := core.BindAll(scope); err != nil {
+		t.Fatalf("BindAll() unexpected error: %v", err)
+	}
+
+	src := `(def user {:name "Bob"}) (def n 2)
+#f"Hello {(:name user)}, you have {n} new {"message"}s {{}}\u{1F4E9}"`
+
+	got, err := sabre.ReadEvalStr(scope, src)
+	if err != nil {
+		t.Fatalf("ReadEvalStr() unexpected error: %v", err)
+	}
+
+	want := sabre.String("Hello Bob, you have 2 new messages {}📩")
+	if got != want {
+		t.Errorf("got = %v, want %v", got, want)
+	}
+}
@@ -29,3 +29,22 @@ func (fn Fn) Invoke(scope sabre.Scope, args ...sabre.Value) (sabre.Value, error)
 
 	return fn(vals)
 }
+
+// Macro returns a macro which computes its expansion using the Go function.
+// The function receives the unevaluated args of the macro call and must
+// return the form to be evaluated in place of the call.
+func Macro(name string, expand func(args []sabre.Value) (sabre.Value, error)) sabre.MultiFn {
+	return sabre.MultiFn{
+		Name:    name,
+		IsMacro: true,
+		Methods: []sabre.Fn{
+			{
+				Args:     []string{"args"},
+				Variadic: true,
+				Func: sabre.GoFunc(func(_ sabre.Scope, args []sabre.Value) (sabre.Value, error) {
+					return expand(args)
+				}),
+			},
+		},
+	}
+}
@@ -0,0 +1,89 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/spy16/sabre"
+)
+
+// MakePromise returns a new promise. Blocked deref of the promise fails if
+// the evaluation that created the promise is cancelled.
+func MakePromise(scope sabre.Scope, args []sabre.Value) (sabre.Value, error) {
+	if err := verifyArgCount([]int{}, args); err != nil {
+		return nil, err
+	}
+
+	return sabre.NewPromise(sabre.Context(scope)), nil
+}
+
+// Deliver sets the value of the promise. Returns the promise if delivered
+// or nil if the promise was already delivered.
+// Usage: (deliver promise val)
+func Deliver(vals []sabre.Value) (sabre.Value, error) {
+	if err := verifyArgCount([]int{2}, vals); err != nil {
+		return nil, err
+	}
+
+	p, isPromise := vals[0].(*sabre.Promise)
+	if !isPromise {
+		return nil, fmt.Errorf("expecting promise, not '%s'", reflect.TypeOf(vals[0]))
+	}
+
+	if !p.Deliver(vals[1]) {
+		return sabre.Nil{}, nil
+	}
+
+	return p, nil
+}
+
+// Force returns the value of the delay by evaluating it if required. If the
+// argument is not a delay, it is returned as is.
+func Force(scope sabre.Scope, args []sabre.Value) (sabre.Value, error) {
+	vals, err := evalValueList(scope, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyArgCount([]int{1}, vals); err != nil {
+		return nil, err
+	}
+
+	d, isDelay := vals[0].(*sabre.Delay)
+	if !isDelay {
+		return vals[0], nil
+	}
+
+	return d.Force(scope)
+}
+
+// IsRealized returns true if the value of a future, promise or delay is
+// available.
+func IsRealized(vals []sabre.Value) (sabre.Value, error) {
+	if err := verifyArgCount([]int{1}, vals); err != nil {
+		return nil, err
+	}
+
+	p, isPending := vals[0].(sabre.Pending)
+	if !isPending {
+		return nil, fmt.Errorf("realized? not supported on value of type '%s'",
+			reflect.TypeOf(vals[0]))
+	}
+
+	return sabre.Bool(p.Realized()), nil
+}
+
+// CancelFuture cancels the future if it has not completed already. Returns
+// true if the future was cancelled.
+func CancelFuture(vals []sabre.Value) (sabre.Value, error) {
+	if err := verifyArgCount([]int{1}, vals); err != nil {
+		return nil, err
+	}
+
+	f, isFuture := vals[0].(*sabre.Future)
+	if !isFuture {
+		return nil, fmt.Errorf("expecting future, not '%s'", reflect.TypeOf(vals[0]))
+	}
+
+	return sabre.Bool(f.Cancel()), nil
+}
@@ -0,0 +1,402 @@
+package core
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/spy16/sabre"
+)
+
+// Standard streams of an evaluation. They can be passed to slurp, spit,
+// line-seq and read-line in place of file names.
+const (
+	StdIn  = Stream("in")
+	StdOut = Stream("out")
+	StdErr = Stream("err")
+)
+
+var errNoFS = errors.New("no filesystem is available for the evaluation")
+
+// IO is the environment used by the I/O functions. Zero values of the
+// streams default to os.Stdin, os.Stdout and os.Stderr. File functions are
+// available only if FS is set and spit requires FS to be a WritableFS.
+type IO struct {
+	In  io.Reader
+	Out io.Writer
+	Err io.Writer
+	FS  fs.FS
+}
+
+// WritableFS is a filesystem which also supports writing files.
+type WritableFS interface {
+	fs.FS
+
+	// WriteFile writes the data to the named file, creating it if required.
+	// If appendData is true, data is appended to the file instead of
+	// replacing its content.
+	WriteFile(name string, data []byte, appendData bool) error
+}
+
+// WithIO returns a context which makes the I/O functions (print functions,
+// slurp, spit, line-seq, read-line) evaluated with it (See sabre.WithContext)
+// use the given streams and filesystem.
+func WithIO(ctx context.Context, env IO) context.Context {
+	if env.In == nil {
+		env.In = os.Stdin
+	}
+
+	if env.Out == nil {
+		env.Out = os.Stdout
+	}
+
+	if env.Err == nil {
+		env.Err = os.Stderr
+	}
+
+	return context.WithValue(ctx, ioKey{}, &ioEnv{IO: env, in: bufio.NewReader(env.In)})
+}
+
+// DirFS returns a WritableFS for the files in the directory tree rooted at
+// dir. Names must be slash separated and relative to dir (See fs.ValidPath).
+// Symbolic links are resolved and names which resolve to a file outside dir
+// are rejected with fs.ErrPermission, which prevents access to files outside
+// dir.
+func DirFS(dir string) WritableFS {
+	return dirFS{dir: dir}
+}
+
+// Stream represents one of the standard streams (*in*, *out* or *err*) of an
+// evaluation. The actual reader or writer is resolved when the stream is
+// used (See WithIO).
+type Stream string
+
+// Eval returns the stream itself.
+func (s Stream) Eval(_ sabre.Scope) (sabre.Value, error) { return s, nil }
+
+func (s Stream) String() string { return "*" + string(s) + "*" }
+
+// StreamPrinter is same as Printer but writes to the *out* stream of the
+// evaluation.
+func StreamPrinter(readably, newline bool) sabre.GoFunc {
+	return func(scope sabre.Scope, args []sabre.Value) (sabre.Value, error) {
+		vals, err := evalValueList(scope, args)
+		if err != nil {
+			return nil, err
+		}
+
+		return Printer(ioFrom(scope).Out, readably, newline)(vals)
+	}
+}
+
+// StreamPrettyPrinter is same as PrettyPrinter but writes to the *out*
+// stream of the evaluation.
+func StreamPrettyPrinter(scope sabre.Scope, args []sabre.Value) (sabre.Value, error) {
+	vals, err := evalValueList(scope, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return PrettyPrinter(ioFrom(scope).Out)(vals)
+}
+
+// Slurp returns the content of the file (or everything remaining in *in*)
+// as a string.
+// Usage: (slurp "data/config.edn") or (slurp *in*)
+func Slurp(scope sabre.Scope, args []sabre.Value) (sabre.Value, error) {
+	vals, err := evalValueList(scope, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyArgCount([]int{1}, vals); err != nil {
+		return nil, err
+	}
+
+	env := ioFrom(scope)
+	if vals[0] == StdIn {
+		env.mu.Lock()
+		defer env.mu.Unlock()
+
+		data, err := io.ReadAll(env.in)
+		if err != nil {
+			return nil, err
+		}
+		return sabre.String(data), nil
+	}
+
+	name, err := toString(vals[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if env.FS == nil {
+		return nil, errNoFS
+	}
+
+	data, err := fs.ReadFile(env.FS, name)
+	if err != nil {
+		return nil, err
+	}
+
+	return sabre.String(data), nil
+}
+
+// Spit writes the content (converted to string same as str) to the file
+// or to *out* / *err*. File content is replaced unless :append true is
+// passed.
+// Usage: (spit "out.txt" content), (spit "log.txt" line :append true) or
+// (spit *err* "warning")
+func Spit(scope sabre.Scope, args []sabre.Value) (sabre.Value, error) {
+	vals, err := evalValueList(scope, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyArgCount([]int{2, 4}, vals); err != nil {
+		return nil, err
+	}
+
+	appendData := false
+	if len(vals) == 4 {
+		if vals[2] != sabre.Keyword("append") {
+			return nil, fmt.Errorf("invalid option '%s' (only :append is supported)", vals[2])
+		}
+		appendData = isTruthy(vals[3])
+	}
+
+	content := []byte(stringFromVals(vals[1:2]))
+	env := ioFrom(scope)
+
+	switch vals[0] {
+	case StdOut:
+		_, err := env.Out.Write(content)
+		return sabre.Nil{}, err
+
+	case StdErr:
+		_, err := env.Err.Write(content)
+		return sabre.Nil{}, err
+	}
+
+	name, err := toString(vals[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if env.FS == nil {
+		return nil, errNoFS
+	}
+
+	wfs, isWritable := env.FS.(WritableFS)
+	if !isWritable {
+		return nil, errors.New("filesystem available for the evaluation is read-only")
+	}
+
+	if err := wfs.WriteFile(name, content, appendData); err != nil {
+		return nil, err
+	}
+
+	return sabre.Nil{}, nil
+}
+
+// LineSeq returns the lines of the file (or the remaining lines of *in*)
+// as a list of strings without the line terminators. The lines are read
+// one at a time but the list is built eagerly, i.e., reading *in* blocks
+// until the end of the input (or until the evaluation is cancelled between
+// lines).
+// Usage: (line-seq "data.csv") or (line-seq *in*)
+func LineSeq(scope sabre.Scope, args []sabre.Value) (sabre.Value, error) {
+	vals, err := evalValueList(scope, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyArgCount([]int{1}, vals); err != nil {
+		return nil, err
+	}
+
+	env := ioFrom(scope)
+	if vals[0] == StdIn {
+		env.mu.Lock()
+		defer env.mu.Unlock()
+
+		return readLines(sabre.Context(scope), env.in)
+	}
+
+	name, err := toString(vals[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if env.FS == nil {
+		return nil, errNoFS
+	}
+
+	f, err := env.FS.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return readLines(sabre.Context(scope), bufio.NewReader(f))
+}
+
+// ReadLine reads the next line from *in* and returns it without the line
+// terminator. Returns nil at the end of the input.
+// Usage: (read-line)
+func ReadLine(scope sabre.Scope, args []sabre.Value) (sabre.Value, error) {
+	if err := verifyArgCount(nil, args); err != nil {
+		return nil, err
+	}
+
+	env := ioFrom(scope)
+	env.mu.Lock()
+	defer env.mu.Unlock()
+
+	line, err := env.in.ReadString('\n')
+	if err != nil && (err != io.EOF || line == "") {
+		if err == io.EOF {
+			return sabre.Nil{}, nil
+		}
+		return nil, err
+	}
+
+	return sabre.String(strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")), nil
+}
+
+// readLines reads the lines until the end of the input and returns them as
+// a list of strings without the line terminators.
+func readLines(ctx context.Context, rd *bufio.Reader) (sabre.Value, error) {
+	lines := &sabre.List{}
+	for {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		line, err := rd.ReadString('\n')
+		if line != "" {
+			line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+			lines.Values = append(lines.Values, sabre.String(line))
+		}
+
+		if err != nil {
+			if err == io.EOF {
+				return lines, nil
+			}
+			return nil, err
+		}
+	}
+}
+
+type ioKey struct{}
+
+// ioEnv is the IO of an evaluation along with the buffered reader for *in*
+// which is shared by the functions reading from it.
+type ioEnv struct {
+	IO
+
+	mu sync.Mutex
+	in *bufio.Reader
+}
+
+var (
+	defaultIOOnce sync.Once
+	defaultIO     *ioEnv
+)
+
+func ioFrom(scope sabre.Scope) *ioEnv {
+	if env, ok := sabre.Context(scope).Value(ioKey{}).(*ioEnv); ok {
+		return env
+	}
+
+	defaultIOOnce.Do(func() {
+		defaultIO = &ioEnv{
+			IO: IO{In: os.Stdin, Out: os.Stdout, Err: os.Stderr},
+			in: bufio.NewReader(os.Stdin),
+		}
+	})
+	return defaultIO
+}
+
+type dirFS struct {
+	dir string
+}
+
+func (d dirFS) Open(name string) (fs.File, error) {
+	path, err := d.resolve("open", name)
+	if err != nil {
+		return nil, err
+	}
+
+	return os.Open(path)
+}
+
+func (d dirFS) WriteFile(name string, data []byte, appendData bool) error {
+	path, err := d.resolve("write", name)
+	if err != nil {
+		return err
+	}
+
+	flag := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+	if appendData {
+		flag = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+	}
+
+	f, err := os.OpenFile(path, flag, 0644)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// resolve returns the path of the named file with all the symbolic links
+// resolved. Returns an error if the path is outside the directory. A file
+// that does not exist yet is resolved relative to its parent directory so
+// that it can be created.
+func (d dirFS) resolve(op, name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrInvalid}
+	}
+
+	root, err := filepath.EvalSymlinks(d.dir)
+	if err != nil {
+		return "", &fs.PathError{Op: op, Path: name, Err: err}
+	}
+
+	path := filepath.Join(root, filepath.FromSlash(name))
+	resolved, err := filepath.EvalSymlinks(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		if _, lerr := os.Lstat(path); lerr == nil {
+			// a dangling symbolic link could point outside the directory.
+			return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrPermission}
+		}
+
+		var dir string
+		dir, err = filepath.EvalSymlinks(filepath.Dir(path))
+		resolved = filepath.Join(dir, filepath.Base(path))
+	}
+
+	if err != nil {
+		return "", &fs.PathError{Op: op, Path: name, Err: err}
+	}
+
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", &fs.PathError{Op: op, Path: name, Err: fs.ErrPermission}
+	}
+
+	return resolved, nil
+}
@@ -0,0 +1,262 @@
+package core_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/spy16/sabre"
+	"github.com/spy16/sabre/core"
+)
+
+func TestIO(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		name     string
+		src      string
+		in       string
+		fs       func() fs.FS
+		want     string
+		wantOut  string
+		wantErr  bool
+		wantFile string
+	}{
+		{
+			name:    "Println",
+			src:     `(println "hello" [1 "a"]) (pr "a") (prn :b) (pprint {:c 1})`,
+			want:    `nil`,
+			wantOut: "hello [1 a]\n\"a\":b\n{:c 1}\n",
+		},
+		{
+			name:    "PrintlnInFunction",
+			src:     `(def greet (fn* [name] (print "hi" name))) (greet "bob")`,
+			want:    `nil`,
+			wantOut: "hi bob",
+		},
+		{
+			name: "Slurp",
+			src:  `(slurp "data/config.edn")`,
+			fs:   readOnlyFS,
+			want: `"{:port 8080}\n"`,
+		},
+		{
+			name:    "SlurpNotFound",
+			src:     `(slurp "missing.txt")`,
+			fs:      readOnlyFS,
+			wantErr: true,
+		},
+		{
+			name:    "SlurpOutsideFS",
+			src:     `(slurp "../secret.txt")`,
+			fs:      readOnlyFS,
+			wantErr: true,
+		},
+		{
+			name:    "SlurpWithoutFS",
+			src:     `(slurp "data/config.edn")`,
+			wantErr: true,
+		},
+		{
+			name: "SlurpIn",
+			src:  `[(read-line) (slurp *in*) (read-line)]`,
+			in:   "first\r\nsecond\nthird",
+			want: `["first" "second\nthird" nil]`,
+		},
+		{
+			name: "LineSeq",
+			src:  `[(line-seq "lines.txt") (line-seq "empty.txt")]`,
+			fs:   readOnlyFS,
+			want: `[("a" "b" "" "c") ()]`,
+		},
+		{
+			name: "LineSeqIn",
+			src:  `(line-seq *in*)`,
+			in:   "x\ny\n",
+			want: `("x" "y")`,
+		},
+		{
+			name: "LineSeqRemainingIn",
+			src:  `[(read-line) (line-seq *in*) (read-line)]`,
+			in:   "first\r\nx\r\n\ny",
+			want: `["first" ("x" "" "y") nil]`,
+		},
+		{
+			name:    "SpitReadOnly",
+			src:     `(spit "out.txt" "data")`,
+			fs:      readOnlyFS,
+			wantErr: true,
+		},
+		{
+			name:     "Spit",
+			src:      `(spit "out.txt" [1 2]) (spit "out.txt" "\n3" :append true) (slurp "out.txt")`,
+			fs:       writableFS,
+			want:     `"[1 2]\n3"`,
+			wantFile: "[1 2]\n3",
+		},
+		{
+			name:    "SpitInvalidOption",
+			src:     `(spit "out.txt" "data" :mode "w")`,
+			fs:      writableFS,
+			wantErr: true,
+		},
+		{
+			name:    "SpitStreams",
+			src:     `(spit *out* "to out") (spit *err* " to err")`,
+			want:    `nil`,
+			wantOut: "to out to err",
+		},
+	}
+
+	for _, tt := range table {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			scope := sabre.NewScope(nil)
+			if err := core.BindAll(scope); err != nil {
+				t.Fatalf("BindAll() unexpected error: %v", err)
+			}
+
+			var out bytes.Buffer
+			env := core.IO{In: strings.NewReader(tt.in), Out: &out, Err: &out}
+			if tt.fs != nil {
+				env.FS = tt.fs()
+			}
+
+			ctx := core.WithIO(context.Background(), env)
+			got, err := sabre.ReadEvalStr(sabre.WithContext(ctx, scope), tt.src)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if got.String() != tt.want {
+				t.Errorf("got = %s, want %s", got, tt.want)
+			}
+
+			if out.String() != tt.wantOut {
+				t.Errorf("output = %q, want %q", out.String(), tt.wantOut)
+			}
+
+			if tt.wantFile != "" {
+				data, _ := fs.ReadFile(env.FS, "out.txt")
+				if string(data) != tt.wantFile {
+					t.Errorf("file content = %q, want %q", data, tt.wantFile)
+				}
+			}
+		})
+	}
+}
+
+func TestDirFS(t *testing.T) {
+	t.Parallel()
+
+	fsys := core.DirFS(t.TempDir())
+
+	if err := fsys.WriteFile("a.txt", []byte("hello"), false); err != nil {
+		t.Fatalf("WriteFile() unexpected error: %v", err)
+	}
+
+	if err := fsys.WriteFile("a.txt", []byte(" world"), true); err != nil {
+		t.Fatalf("WriteFile() unexpected error: %v", err)
+	}
+
+	data, err := fs.ReadFile(fsys, "a.txt")
+	if err != nil || string(data) != "hello world" {
+		t.Errorf("ReadFile() = %q, %v, want \"hello world\"", data, err)
+	}
+
+	for _, name := range []string{"../a.txt", "/tmp/a.txt", "dir/../../a.txt"} {
+		if err := fsys.WriteFile(name, nil, false); err == nil {
+			t.Errorf("WriteFile(%q) expected error", name)
+		}
+	}
+}
+
+func TestDirFS_Symlinks(t *testing.T) {
+	t.Parallel()
+
+	root, outside := t.TempDir(), t.TempDir()
+	if err := os.WriteFile(filepath.Join(outside, "secret.txt"), []byte("secret"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "a.txt"), []byte("hello"), 0644); err != nil {
+		t.Fatalf("failed to write file: %v", err)
+	}
+
+	links := map[string]string{
+		"inside":   filepath.Join(root, "a.txt"),
+		"secret":   filepath.Join(outside, "secret.txt"),
+		"out":      outside,
+		"dangling": filepath.Join(outside, "new.txt"),
+	}
+	for name, target := range links {
+		if err := os.Symlink(target, filepath.Join(root, name)); err != nil {
+			t.Skipf("symbolic links are not supported: %v", err)
+		}
+	}
+
+	fsys := core.DirFS(root)
+
+	data, err := fs.ReadFile(fsys, "inside")
+	if err != nil || string(data) != "hello" {
+		t.Errorf("ReadFile(inside) = %q, %v, want \"hello\"", data, err)
+	}
+
+	for _, name := range []string{"secret", "out/secret.txt"} {
+		if _, err := fs.ReadFile(fsys, name); !errors.Is(err, fs.ErrPermission) {
+			t.Errorf("ReadFile(%q) error = %v, want fs.ErrPermission", name, err)
+		}
+	}
+
+	for _, name := range []string{"secret", "out/new.txt", "dangling"} {
+		if err := fsys.WriteFile(name, []byte("x"), false); !errors.Is(err, fs.ErrPermission) {
+			t.Errorf("WriteFile(%q) error = %v, want fs.ErrPermission", name, err)
+		}
+	}
+
+	if data, _ := os.ReadFile(filepath.Join(outside, "secret.txt")); string(data) != "secret" {
+		t.Errorf("file outside the directory modified: %q", data)
+	}
+	if _, err := os.Stat(filepath.Join(outside, "new.txt")); err == nil {
+		t.Errorf("file created outside the directory")
+	}
+}
+
+func readOnlyFS() fs.FS {
+	return fstest.MapFS{
+		"data/config.edn": {Data: []byte("{:port 8080}\n")},
+		"lines.txt":       {Data: []byte("a\r\nb\n\nc")},
+		"empty.txt":       {Data: nil},
+	}
+}
+
+func writableFS() fs.FS {
+	return memFS{MapFS: fstest.MapFS{}}
+}
+
+// memFS is an in-memory WritableFS.
+type memFS struct {
+	fstest.MapFS
+}
+
+func (m memFS) WriteFile(name string, data []byte, appendData bool) error {
+	if !fs.ValidPath(name) {
+		return fs.ErrInvalid
+	}
+
+	if f, found := m.MapFS[name]; found && appendData {
+		data = append(append([]byte{}, f.Data...), data...)
+	}
+
+	m.MapFS[name] = &fstest.MapFile{Data: data}
+	return nil
+}
@@ -0,0 +1,42 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/spy16/sabre"
+)
+
+// ParseJSON reads the JSON string into sabre values (See sabre.ParseJSON).
+// Object keys are read as keywords if the second argument is truthy and as
+// strings otherwise.
+// Usage: (json/parse s) or (json/parse s true)
+func ParseJSON(vals []sabre.Value) (sabre.Value, error) {
+	if err := verifyArgCount([]int{1, 2}, vals); err != nil {
+		return nil, err
+	}
+
+	s, isString := vals[0].(sabre.String)
+	if !isString {
+		return nil, fmt.Errorf("json/parse expects a string, not '%s'", reflect.TypeOf(vals[0]))
+	}
+
+	keywordKeys := len(vals) == 2 && isTruthy(vals[1])
+	return sabre.ParseJSON([]byte(s), keywordKeys)
+}
+
+// StringifyJSON returns the JSON representation of the value as string.
+// Keywords and symbols are written as strings without the ':'.
+// Usage: (json/stringify {:a [1 2]})
+func StringifyJSON(vals []sabre.Value) (sabre.Value, error) {
+	if err := verifyArgCount([]int{1}, vals); err != nil {
+		return nil, err
+	}
+
+	data, err := sabre.ToJSON(vals[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return sabre.String(data), nil
+}
@@ -0,0 +1,74 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/spy16/sabre"
+	"github.com/spy16/sabre/core"
+)
+
+func TestJSON(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		name    string
+		src     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Parse",
+			src:  `(json/parse "{\"a\": [1, 2.5, null]}")`,
+			want: `{"a" [1 2.5 nil]}`,
+		},
+		{
+			name: "ParseKeywordKeys",
+			src:  `(:user (json/parse "{\"user\": {\"id\": 7}}" true))`,
+			want: `{:id 7}`,
+		},
+		{
+			name: "Stringify",
+			src:  `(json/stringify {:ok true :ids #{1} :msg "hi"})`,
+			want: `"{\"ok\":true,\"ids\":[1],\"msg\":\"hi\"}"`,
+		},
+		{
+			name: "RoundTrip",
+			src:  `(json/parse (json/stringify [{"a" [nil 1.5]}]))`,
+			want: `[{"a" [nil 1.5]}]`,
+		},
+		{
+			name:    "ParseInvalid",
+			src:     `(json/parse "{")`,
+			wantErr: true,
+		},
+		{
+			name:    "ParseNotString",
+			src:     `(json/parse 1)`,
+			wantErr: true,
+		},
+		{
+			name:    "StringifyFunction",
+			src:     `(json/stringify [json/parse])`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range table {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			scope := sabre.NewScope(nil)
+			if err := core.BindAll(scope); err != nil {
+				t.Fatalf("BindAll() unexpected error: %v", err)
+			}
+
+			got, err := sabre.ReadEvalStr(scope, tt.src)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && got.String() != tt.want {
+				t.Errorf("got = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
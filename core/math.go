@@ -0,0 +1,230 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"math/rand"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/spy16/sabre"
+)
+
+var defaultRand = &lockedRand{r: rand.New(rand.NewSource(time.Now().UnixNano()))}
+
+type randKey struct{}
+
+// WithRandSource returns a context which makes the random functions (rand,
+// rand-int, rand-nth and shuffle) evaluated with it use the given source.
+// Use the returned context with sabre.WithContext and a seeded source (e.g.,
+// rand.NewSource(42)) for reproducible results. Source is not required to
+// be safe for concurrent use.
+func WithRandSource(ctx context.Context, src rand.Source) context.Context {
+	return context.WithValue(ctx, randKey{}, &lockedRand{r: rand.New(src)})
+}
+
+// MathFn returns a function which applies 'fn' to its only numeric argument.
+// Integers are converted to floats and the result is always a float.
+func MathFn(fn func(x float64) float64) Fn {
+	return func(vals []sabre.Value) (sabre.Value, error) {
+		if err := verifyArgCount([]int{1}, vals); err != nil {
+			return nil, err
+		}
+
+		x, err := toFloat(vals[0])
+		if err != nil {
+			return nil, err
+		}
+
+		return sabre.Float64(fn(x)), nil
+	}
+}
+
+// MathFn2 is same as MathFn but for functions of 2 arguments (e.g., pow).
+func MathFn2(fn func(x, y float64) float64) Fn {
+	return func(vals []sabre.Value) (sabre.Value, error) {
+		if err := verifyArgCount([]int{2}, vals); err != nil {
+			return nil, err
+		}
+
+		x, err := toFloat(vals[0])
+		if err != nil {
+			return nil, err
+		}
+
+		y, err := toFloat(vals[1])
+		if err != nil {
+			return nil, err
+		}
+
+		return sabre.Float64(fn(x, y)), nil
+	}
+}
+
+// IsNaN returns true if the argument is a float NaN value.
+// Usage: (math/NaN? x)
+func IsNaN(vals []sabre.Value) (sabre.Value, error) {
+	if err := verifyArgCount([]int{1}, vals); err != nil {
+		return nil, err
+	}
+
+	x, err := toFloat(vals[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return sabre.Bool(math.IsNaN(x)), nil
+}
+
+// IsInfinite returns true if the argument is positive or negative infinity.
+// Usage: (math/infinite? x)
+func IsInfinite(vals []sabre.Value) (sabre.Value, error) {
+	if err := verifyArgCount([]int{1}, vals); err != nil {
+		return nil, err
+	}
+
+	x, err := toFloat(vals[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return sabre.Bool(math.IsInf(x, 0)), nil
+}
+
+// Rand returns a random float in [0, 1) or in [0, n) if n is given.
+// Usage: (rand) or (rand n)
+func Rand(scope sabre.Scope, args []sabre.Value) (sabre.Value, error) {
+	vals, err := evalValueList(scope, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyArgCount([]int{0, 1}, vals); err != nil {
+		return nil, err
+	}
+
+	n := 1.0
+	if len(vals) == 1 {
+		if n, err = toFloat(vals[0]); err != nil {
+			return nil, err
+		}
+	}
+
+	var f float64
+	randFrom(scope).do(func(r *rand.Rand) { f = r.Float64() })
+	return sabre.Float64(f * n), nil
+}
+
+// RandInt returns a random integer in [0, n).
+// Usage: (rand-int n)
+func RandInt(scope sabre.Scope, args []sabre.Value) (sabre.Value, error) {
+	vals, err := evalValueList(scope, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyArgCount([]int{1}, vals); err != nil {
+		return nil, err
+	}
+
+	n, isInt := vals[0].(sabre.Int64)
+	if !isInt || n <= 0 {
+		return nil, fmt.Errorf("expecting positive integer, not '%s'", vals[0])
+	}
+
+	var i int64
+	randFrom(scope).do(func(r *rand.Rand) { i = r.Int63n(int64(n)) })
+	return sabre.Int64(i), nil
+}
+
+// RandNth returns a random item of the collection.
+// Usage: (rand-nth coll)
+func RandNth(scope sabre.Scope, args []sabre.Value) (sabre.Value, error) {
+	vals, err := evalValueList(scope, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyArgCount([]int{1}, vals); err != nil {
+		return nil, err
+	}
+
+	items, err := toItems(vals[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if len(items) == 0 {
+		return nil, fmt.Errorf("rand-nth called on empty collection")
+	}
+
+	var i int
+	randFrom(scope).do(func(r *rand.Rand) { i = r.Intn(len(items)) })
+	return items[i], nil
+}
+
+// Shuffle returns a vector containing the items of the collection in random
+// order.
+// Usage: (shuffle coll)
+func Shuffle(scope sabre.Scope, args []sabre.Value) (sabre.Value, error) {
+	vals, err := evalValueList(scope, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyArgCount([]int{1}, vals); err != nil {
+		return nil, err
+	}
+
+	items, err := toItems(vals[0])
+	if err != nil {
+		return nil, err
+	}
+
+	res := append([]sabre.Value{}, items...)
+	randFrom(scope).do(func(r *rand.Rand) {
+		r.Shuffle(len(res), func(i, j int) { res[i], res[j] = res[j], res[i] })
+	})
+
+	return sabre.Vector{Values: res}, nil
+}
+
+// lockedRand makes rand.Rand safe for concurrent use.
+type lockedRand struct {
+	mu sync.Mutex
+	r  *rand.Rand
+}
+
+func (lr *lockedRand) do(fn func(r *rand.Rand)) {
+	lr.mu.Lock()
+	defer lr.mu.Unlock()
+	fn(lr.r)
+}
+
+func randFrom(scope sabre.Scope) *lockedRand {
+	if lr, ok := sabre.Context(scope).Value(randKey{}).(*lockedRand); ok {
+		return lr
+	}
+
+	return defaultRand
+}
+
+func toFloat(v sabre.Value) (float64, error) {
+	switch num := v.(type) {
+	case sabre.Int64:
+		return float64(num), nil
+
+	case sabre.Float64:
+		return float64(num), nil
+
+	case sabre.BigInt:
+		f, _ := new(big.Float).SetInt(num.Int).Float64()
+		return f, nil
+
+	default:
+		return 0, fmt.Errorf("expecting number, not '%s'", reflect.TypeOf(v))
+	}
+}
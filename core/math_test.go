@@ -0,0 +1,133 @@
+package core_test
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/spy16/sabre"
+	"github.com/spy16/sabre/core"
+)
+
+func TestMath(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		name    string
+		src     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Basic",
+			src:  `[(math/sqrt 16) (math/pow 2 10) (math/abs -1.5) (math/exp 0) (math/log math/E) (math/log10 1000)]`,
+			want: `[4.0 1024.0 1.5 1.0 1.0 3.0]`,
+		},
+		{
+			name: "Trig",
+			src:  `[(math/sin 0) (math/cos math/PI) (math/atan2 0 1) (math/round (math/tan (math/atan 5)))]`,
+			want: `[0.0 -1.0 0.0 5.0]`,
+		},
+		{
+			name: "Rounding",
+			src:  `[(math/floor -1.5) (math/ceil 1.2) (math/round 2.5) (math/round -2.5) (math/trunc -1.7)]`,
+			want: `[-2.0 2.0 3.0 -3.0 -1.0]`,
+		},
+		{
+			name: "Predicates",
+			src:  `[(math/NaN? (math/sqrt -1)) (math/NaN? 1) (math/infinite? (math/log 0)) (math/infinite? 1e308)]`,
+			want: `[true false true false]`,
+		},
+		{
+			name: "BigInt",
+			src:  `(math/sqrt 100000000000000000000N)`,
+			want: `1e+10`,
+		},
+		{
+			name:    "NotNumber",
+			src:     `(math/sqrt "4")`,
+			wantErr: true,
+		},
+		{
+			name:    "WrongArgCount",
+			src:     `(math/pow 2)`,
+			wantErr: true,
+		},
+		{
+			name:    "RandIntInvalid",
+			src:     `(rand-int 0)`,
+			wantErr: true,
+		},
+		{
+			name:    "RandNthEmpty",
+			src:     `(rand-nth [])`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range table {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			scope := sabre.NewScope(nil)
+			if err := core.BindAll(scope); err != nil {
+				t.Fatalf("BindAll() unexpected error: %v", err)
+			}
+
+			got, err := sabre.ReadEvalStr(scope, tt.src)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && got.String() != tt.want {
+				t.Errorf("got = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRand(t *testing.T) {
+	t.Parallel()
+
+	const src = `[(rand) (rand 10) (rand-int 100) (rand-nth [:a :b :c]) (shuffle (list 1 2 3 4 5 6 7 8 9 10))]`
+
+	eval := func(seed int64) sabre.Value {
+		scope := sabre.NewScope(nil)
+		if err := core.BindAll(scope); err != nil {
+			t.Fatalf("BindAll() unexpected error: %v", err)
+		}
+
+		ctx := core.WithRandSource(context.Background(), rand.NewSource(seed))
+		got, err := sabre.ReadEvalStr(sabre.WithContext(ctx, scope), src)
+		if err != nil {
+			t.Fatalf("ReadEvalStr() unexpected error: %v", err)
+		}
+		return got
+	}
+
+	first, second := eval(42), eval(42)
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("same seed gave different results: %v and %v", first, second)
+	}
+
+	if other := eval(7); reflect.DeepEqual(first, other) {
+		t.Errorf("different seeds gave same results: %v", first)
+	}
+
+	vals := first.(sabre.Vector).Values
+	if f := vals[0].(sabre.Float64); f < 0 || f >= 1 {
+		t.Errorf("(rand) = %v, want value in [0, 1)", f)
+	}
+
+	if f := vals[1].(sabre.Float64); f < 0 || f >= 10 {
+		t.Errorf("(rand 10) = %v, want value in [0, 10)", f)
+	}
+
+	if i := vals[2].(sabre.Int64); i < 0 || i >= 100 {
+		t.Errorf("(rand-int 100) = %v, want value in [0, 100)", i)
+	}
+
+	if len(vals[4].(sabre.Vector).Values) != 10 {
+		t.Errorf("(shuffle) = %v, want 10 items", vals[4])
+	}
+}
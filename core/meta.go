@@ -0,0 +1,107 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/spy16/sabre"
+)
+
+// Meta returns the metadata of the value or nil if the value has no
+// metadata.
+func Meta(vals []sabre.Value) (sabre.Value, error) {
+	if err := verifyArgCount([]int{1}, vals); err != nil {
+		return nil, err
+	}
+
+	v, ok := vals[0].(sabre.Meta)
+	if !ok || v.Meta() == nil {
+		return sabre.Nil{}, nil
+	}
+
+	return v.Meta(), nil
+}
+
+// WithMeta returns a copy of the value with the given metadata.
+// Usage: (with-meta value meta)
+func WithMeta(vals []sabre.Value) (sabre.Value, error) {
+	if err := verifyArgCount([]int{2}, vals); err != nil {
+		return nil, err
+	}
+
+	v, err := toWithMeta(vals[0])
+	if err != nil {
+		return nil, err
+	}
+
+	meta, err := toMetaMap(vals[1])
+	if err != nil {
+		return nil, err
+	}
+
+	return v.WithMeta(meta), nil
+}
+
+// VaryMeta returns a copy of the value with metadata set to the result of
+// applying the function to the current metadata and any additional args.
+// Usage: (vary-meta value f & args)
+func VaryMeta(scope sabre.Scope, args []sabre.Value) (sabre.Value, error) {
+	vals, err := evalValueList(scope, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(vals) < 2 {
+		return nil, fmt.Errorf("call requires at-least 2 argument(s), got %d", len(vals))
+	}
+
+	v, err := toWithMeta(vals[0])
+	if err != nil {
+		return nil, err
+	}
+
+	fn, err := toInvokable(vals[1])
+	if err != nil {
+		return nil, err
+	}
+
+	var current sabre.Value = sabre.Nil{}
+	if v.Meta() != nil {
+		current = v.Meta()
+	}
+
+	res, err := sabre.Apply(scope, fn, append([]sabre.Value{current}, vals[2:]...))
+	if err != nil {
+		return nil, err
+	}
+
+	meta, err := toMetaMap(res)
+	if err != nil {
+		return nil, err
+	}
+
+	return v.WithMeta(meta), nil
+}
+
+func toWithMeta(v sabre.Value) (sabre.WithMeta, error) {
+	wm, ok := v.(sabre.WithMeta)
+	if !ok {
+		return nil, fmt.Errorf("value of type '%s' does not support metadata",
+			reflect.TypeOf(v))
+	}
+
+	return wm, nil
+}
+
+func toMetaMap(v sabre.Value) (*sabre.HashMap, error) {
+	switch m := v.(type) {
+	case *sabre.HashMap:
+		return m, nil
+
+	case sabre.Nil:
+		return nil, nil
+
+	default:
+		return nil, fmt.Errorf("metadata must be a map, not '%s'", reflect.TypeOf(v))
+	}
+}
@@ -0,0 +1,96 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/spy16/sabre"
+	"github.com/spy16/sabre/core"
+)
+
+func TestMeta(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		name    string
+		src     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "NoMeta",
+			src:  `(meta [1 2])`,
+			want: `nil`,
+		},
+		{
+			name: "NotMeta",
+			src:  `(meta 10)`,
+			want: `nil`,
+		},
+		{
+			name: "ReaderMeta",
+			src:  `(meta ^:private ^{:doc "numbers"} [1 2])`,
+			want: `{:doc "numbers", :private true}`,
+		},
+		{
+			name: "DefMeta",
+			src:  `(def ^{:doc "identity"} id (fn* [x] x)) (meta id)`,
+			want: `{:doc "identity"}`,
+		},
+		{
+			name: "WithMeta",
+			src:  `(def v (with-meta [1] {:a 1})) [v (meta v)]`,
+			want: `[[1] {:a 1}]`,
+		},
+		{
+			name: "WithNilMeta",
+			src:  `(meta (with-meta ^:a [1] nil))`,
+			want: `nil`,
+		},
+		{
+			name: "VaryMeta",
+			src:  `(meta (vary-meta ^{:a 1} {} assoc :b 2))`,
+			want: `{:a 1, :b 2}`,
+		},
+		{
+			name:    "WithMetaNotMap",
+			src:     `(with-meta [1] :a)`,
+			wantErr: true,
+		},
+		{
+			name:    "WithMetaNotSupported",
+			src:     `(with-meta 1 {})`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range table {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			scope := sabre.NewScope(nil)
+			if err := core.BindAll(scope); err != nil {
+				t.Fatalf("BindAll() unexpected error: %v", err)
+			}
+			_ = scope.Bind("assoc", sabre.GoFunc(func(scope sabre.Scope, args []sabre.Value) (sabre.Value, error) {
+				vals := make([]sabre.Value, len(args))
+				for i, arg := range args {
+					v, err := arg.Eval(scope)
+					if err != nil {
+						return nil, err
+					}
+					vals[i] = v
+				}
+
+				return vals[0].(*sabre.HashMap).Assoc(vals[1], vals[2]), nil
+			}))
+
+			got, err := sabre.ReadEvalStr(scope, tt.src)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && got.String() != tt.want {
+				t.Errorf("got = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
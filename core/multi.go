@@ -0,0 +1,238 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/spy16/sabre"
+)
+
+// DefMulti returns the expander for (defmulti name doc? dispatch-fn option*)
+// form which defines a multimethod dispatching on the result of invoking
+// dispatch-fn with the args. Dispatch values are matched using the given
+// hierarchy. Supported options are ':default value' which sets the dispatch
+// value of the fallback method (:default by default). Like def, defmulti can
+// be re-evaluated but if the name is already bound to a multimethod, the
+// multimethod (along with its methods) is retained as is.
+// Usage: (defmulti handle :type)
+func DefMulti(h *sabre.Hierarchy) func(args []sabre.Value) (sabre.Value, error) {
+	newMulti := sabre.GoFunc(func(scope sabre.Scope, args []sabre.Value) (sabre.Value, error) {
+		name := string(args[0].(sabre.String))
+		if v, err := scope.Resolve(name); err == nil {
+			if mm, isMulti := v.(*sabre.MultiMethod); isMulti {
+				return mm, nil
+			}
+		}
+
+		vals, err := evalValueList(scope, args)
+		if err != nil {
+			return nil, err
+		}
+
+		dispatch, isInvokable := vals[1].(sabre.Invokable)
+		if !isInvokable {
+			return nil, fmt.Errorf("dispatch function must be invokable, not '%s'",
+				reflect.TypeOf(vals[1]))
+		}
+
+		mm := sabre.NewMultiMethod(name, dispatch, h)
+
+		opts := vals[2:]
+		if len(opts)%2 != 0 {
+			return nil, fmt.Errorf("options must be keyword-value pairs")
+		}
+
+		for i := 0; i < len(opts); i += 2 {
+			switch opts[i] {
+			case sabre.Keyword("default"):
+				mm.Default = opts[i+1]
+
+			default:
+				return nil, fmt.Errorf("unknown option '%s'", opts[i])
+			}
+		}
+
+		return mm, nil
+	})
+
+	return func(args []sabre.Value) (sabre.Value, error) {
+		if len(args) < 2 {
+			return nil, fmt.Errorf("call requires at-least 2 argument(s), got %d", len(args))
+		}
+
+		name, isSymbol := args[0].(sabre.Symbol)
+		if !isSymbol {
+			return nil, fmt.Errorf("multimethod name must be symbol, not '%s'", reflect.TypeOf(args[0]))
+		}
+
+		rest := args[1:]
+		if _, isDoc := rest[0].(sabre.String); isDoc && len(rest) > 1 {
+			rest = rest[1:]
+		}
+
+		call := append([]sabre.Value{newMulti, sabre.String(name.Value)}, rest...)
+		return makeList(symbol("def"), name, makeList(call...)), nil
+	}
+}
+
+// DefMethod expands the (defmethod name dispatch-value [args] expr*) form
+// into a call which adds the method for the dispatch value to the
+// multimethod. Multiple arities can be defined using the form
+// (defmethod name dispatch-value ([args] expr*)+).
+// Usage: (defmethod handle :click [event] (println "clicked"))
+func DefMethod(args []sabre.Value) (sabre.Value, error) {
+	if len(args) < 3 {
+		return nil, fmt.Errorf("call requires at-least 3 argument(s), got %d", len(args))
+	}
+
+	fn := makeList(append([]sabre.Value{symbol("fn*")}, args[2:]...)...)
+	return makeList(Fn(addMethod), args[0], args[1], fn), nil
+}
+
+// PreferMethod causes the method for dispatch value x to be preferred over
+// the method for y when both match and neither is derived from the other.
+// Usage: (prefer-method multimethod x y)
+func PreferMethod(vals []sabre.Value) (sabre.Value, error) {
+	if err := verifyArgCount([]int{3}, vals); err != nil {
+		return nil, err
+	}
+
+	mm, err := toMultiMethod(vals[0])
+	if err != nil {
+		return nil, err
+	}
+
+	if err := mm.PreferMethod(vals[1], vals[2]); err != nil {
+		return nil, err
+	}
+
+	return mm, nil
+}
+
+// RemoveMethod removes the method for the dispatch value from the
+// multimethod.
+// Usage: (remove-method multimethod dispatch-value)
+func RemoveMethod(vals []sabre.Value) (sabre.Value, error) {
+	if err := verifyArgCount([]int{2}, vals); err != nil {
+		return nil, err
+	}
+
+	mm, err := toMultiMethod(vals[0])
+	if err != nil {
+		return nil, err
+	}
+
+	mm.RemoveMethod(vals[1])
+	return mm, nil
+}
+
+// Derive returns a Fn which establishes a parent-child relationship between
+// two keywords in the hierarchy.
+// Usage: (derive :child :parent)
+func Derive(h *sabre.Hierarchy) Fn {
+	return func(vals []sabre.Value) (sabre.Value, error) {
+		if err := verifyKeywords(2, vals); err != nil {
+			return nil, err
+		}
+
+		if err := h.Derive(vals[0], vals[1]); err != nil {
+			return nil, err
+		}
+
+		return sabre.Nil{}, nil
+	}
+}
+
+// Underive returns a Fn which removes the parent-child relationship between
+// two keywords from the hierarchy.
+// Usage: (underive :child :parent)
+func Underive(h *sabre.Hierarchy) Fn {
+	return func(vals []sabre.Value) (sabre.Value, error) {
+		if err := verifyKeywords(2, vals); err != nil {
+			return nil, err
+		}
+
+		h.Underive(vals[0], vals[1])
+		return sabre.Nil{}, nil
+	}
+}
+
+// IsA returns a Fn which checks if the child is equal to or derived from
+// the parent in the hierarchy. Vectors are compared element-wise.
+// Usage: (isa? child parent)
+func IsA(h *sabre.Hierarchy) Fn {
+	return func(vals []sabre.Value) (sabre.Value, error) {
+		if err := verifyArgCount([]int{2}, vals); err != nil {
+			return nil, err
+		}
+
+		return sabre.Bool(h.IsA(vals[0], vals[1])), nil
+	}
+}
+
+// Parents returns a Fn which returns the set of keywords the keyword is
+// derived from directly or nil if there are none.
+// Usage: (parents :child)
+func Parents(h *sabre.Hierarchy) Fn {
+	return func(vals []sabre.Value) (sabre.Value, error) {
+		if err := verifyKeywords(1, vals); err != nil {
+			return nil, err
+		}
+
+		return toSetOrNil(h.Parents(vals[0])), nil
+	}
+}
+
+// Ancestors returns a Fn which returns the set of keywords the keyword is
+// derived from directly or indirectly or nil if there are none.
+// Usage: (ancestors :child)
+func Ancestors(h *sabre.Hierarchy) Fn {
+	return func(vals []sabre.Value) (sabre.Value, error) {
+		if err := verifyKeywords(1, vals); err != nil {
+			return nil, err
+		}
+
+		return toSetOrNil(h.Ancestors(vals[0])), nil
+	}
+}
+
+func addMethod(vals []sabre.Value) (sabre.Value, error) {
+	mm, err := toMultiMethod(vals[0])
+	if err != nil {
+		return nil, err
+	}
+
+	mm.AddMethod(vals[1], vals[2].(sabre.Invokable))
+	return mm, nil
+}
+
+func toMultiMethod(v sabre.Value) (*sabre.MultiMethod, error) {
+	mm, isMulti := v.(*sabre.MultiMethod)
+	if !isMulti {
+		return nil, fmt.Errorf("expecting multimethod, not '%s'", reflect.TypeOf(v))
+	}
+
+	return mm, nil
+}
+
+func verifyKeywords(count int, vals []sabre.Value) error {
+	if err := verifyArgCount([]int{count}, vals); err != nil {
+		return err
+	}
+
+	for _, v := range vals {
+		if _, isKeyword := v.(sabre.Keyword); !isKeyword {
+			return fmt.Errorf("expecting keyword, not '%s'", reflect.TypeOf(v))
+		}
+	}
+
+	return nil
+}
+
+func toSetOrNil(vals []sabre.Value) sabre.Value {
+	if len(vals) == 0 {
+		return sabre.Nil{}
+	}
+
+	return sabre.Set{Values: vals}
+}
@@ -0,0 +1,202 @@
+package core_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/spy16/sabre"
+	"github.com/spy16/sabre/core"
+)
+
+func TestMultiMethods(t *testing.T) {
+	t.Parallel()
+
+	const defs = `
+(defmulti handle "Handles events." :type)
+(defmethod handle :click [e] (str "click at " (:x e)))
+(defmethod handle :key
+  ([e] (handle e "pressed"))
+  ([e action] (str (:key e) " " action)))
+(defmethod handle :default [e] (str "unknown " (:type e)))
+`
+
+	table := []struct {
+		name    string
+		src     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Dispatch",
+			src:  `[(handle {:type :click :x 10}) (handle {:type :key :key "a"}) (handle {:type :key :key "a"} "released")]`,
+			want: `["click at 10" "a pressed" "a released"]`,
+		},
+		{
+			name: "Default",
+			src:  `(handle {:type :scroll})`,
+			want: `"unknown :scroll"`,
+		},
+		{
+			name: "CustomDefault",
+			src: `(defmulti area (fn* [s] (s 0)) :default :fallback)
+(defmethod area :square [s] (s 1))
+(defmethod area :fallback [_] 0)
+[(area [:square 4]) (area [:circle 1])]`,
+			want: `[4 0]`,
+		},
+		{
+			name: "MapDispatchValue",
+			src: `(defmulti op (fn* [a b] {:a a :b b}))
+(defmethod op {:a 1 :b 2} [_ _] :one-two)
+(defmethod op {:b 2 :a 1} [_ _] :replaced)
+(op 1 2)`,
+			want: `:replaced`,
+		},
+		{
+			name: "Redefine",
+			src: `(defmulti handle :kind)
+(handle {:type :click :x 1})`,
+			want: `"click at 1"`,
+		},
+		{
+			name:    "NoMethod",
+			src:     `(defmulti f :type) (f {:type :x})`,
+			wantErr: true,
+		},
+		{
+			name: "RemoveMethod",
+			src: `(remove-method handle :click)
+(handle {:type :click})`,
+			want: `"unknown :click"`,
+		},
+		{
+			name: "Hierarchy",
+			src: `(derive :double-click :click)
+(derive :triple-click :double-click)
+(defmethod handle :double-click [e] "double")
+[(handle {:type :triple-click}) (handle {:type :double-click}) (isa? :triple-click :click) (isa? :click :triple-click)]`,
+			want: `["double" "double" true false]`,
+		},
+		{
+			name: "IsA",
+			src: `(derive :square :rect)
+[(isa? :square :square) (isa? [:square :a] [:rect :a]) (isa? [:square] [:rect :a]) (isa? 1 1)]`,
+			want: `[true true false true]`,
+		},
+		{
+			name: "ParentsAndAncestors",
+			src: `(derive :b :a)
+(derive :c :b)
+(derive :c :x)
+[(parents :c) (ancestors :c) (parents :a)]`,
+			want: `[#{:b :x} #{:b :x :a} nil]`,
+		},
+		{
+			name: "Underive",
+			src: `(derive :b :a)
+(underive :b :a)
+(isa? :b :a)`,
+			want: `false`,
+		},
+		{
+			name:    "CyclicDerive",
+			src:     `(derive :b :a) (derive :a :b)`,
+			wantErr: true,
+		},
+		{
+			name:    "DeriveNonKeyword",
+			src:     `(derive "b" :a)`,
+			wantErr: true,
+		},
+		{
+			name: "Ambiguous",
+			src: `(derive :rect-shape :shape)
+(derive :rect-shape :rect)
+(defmulti describe :kind)
+(defmethod describe :shape [_] "shape")
+(defmethod describe :rect [_] "rect")
+(describe {:kind :rect-shape})`,
+			wantErr: true,
+		},
+		{
+			name: "PreferMethod",
+			src: `(derive :rect-shape :shape)
+(derive :rect-shape :rect)
+(defmulti describe :kind)
+(defmethod describe :shape [_] "shape")
+(defmethod describe :rect [_] "rect")
+(prefer-method describe :rect :shape)
+(describe {:kind :rect-shape})`,
+			want: `"rect"`,
+		},
+		{
+			name: "PreferAncestor",
+			src: `(derive :rect-shape :shape)
+(derive :rect-shape :rect)
+(derive :square :rect-shape)
+(derive :rect :polygon)
+(defmulti describe :kind)
+(defmethod describe :shape [_] "shape")
+(defmethod describe :rect [_] "rect")
+(prefer-method describe :polygon :shape)
+(describe {:kind :square})`,
+			want: `"rect"`,
+		},
+		{
+			name: "PreferenceConflict",
+			src: `(defmulti g :kind)
+(prefer-method g :a :b)
+(prefer-method g :b :a)`,
+			wantErr: true,
+		},
+		{
+			name:    "InvalidDispatchFn",
+			src:     `(defmulti h 10)`,
+			wantErr: true,
+		},
+		{
+			name:    "UnknownOption",
+			src:     `(defmulti h :type :hierarchy nil)`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range table {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			scope := sabre.NewScope(nil)
+			if err := core.BindAll(scope); err != nil {
+				t.Fatalf("BindAll() unexpected error: %v", err)
+			}
+
+			if _, err := sabre.ReadEvalStr(scope, defs); err != nil {
+				t.Fatalf("ReadEvalStr() unexpected error: %v", err)
+			}
+
+			got, err := sabre.ReadEvalStr(scope, tt.src)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && got.String() != tt.want {
+				t.Errorf("got = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMultiMethods_NoMethodError(t *testing.T) {
+	t.Parallel()
+
+	scope := sabre.NewScope(nil)
+	if err := core.BindAll(scope); err != nil {
+		t.Fatalf("BindAll() unexpected error: %v", err)
+	}
+
+	_, err := sabre.ReadEvalStr(scope, `(defmulti h :type) (h {:type :x})`)
+
+	want := "no method in multimethod 'h' for dispatch value ':x'"
+	if err == nil || !strings.HasSuffix(err.Error(), want) {
+		t.Errorf("error = %v, want suffix %q", err, want)
+	}
+}
@@ -0,0 +1,66 @@
+package core
+
+import (
+	"io"
+	"strings"
+
+	"github.com/spy16/sabre"
+)
+
+// PrettyWidth is the line width used by pprint.
+const PrettyWidth = 80
+
+// PrStr returns the readable representation of all args separated by
+// space. Result can be read back using the Reader.
+func PrStr(vals []sabre.Value) (sabre.Value, error) {
+	return sabre.String(joinVals(vals, true)), nil
+}
+
+// Printer returns a function which writes the args separated by space to
+// 'w' and returns nil. Values are written in their readable form (same as
+// pr-str) if 'readably' is true and in the human readable form (See
+// sabre.Display) otherwise. A newline is written at the end if 'newline' is
+// true.
+func Printer(w io.Writer, readably, newline bool) Fn {
+	return func(vals []sabre.Value) (sabre.Value, error) {
+		s := joinVals(vals, readably)
+		if newline {
+			s += "\n"
+		}
+
+		if _, err := io.WriteString(w, s); err != nil {
+			return nil, err
+		}
+
+		return sabre.Nil{}, nil
+	}
+}
+
+// PrettyPrinter returns a function which writes the argument to 'w' in its
+// readable form formatted to fit in PrettyWidth columns (See sabre.Pretty).
+func PrettyPrinter(w io.Writer) Fn {
+	return func(vals []sabre.Value) (sabre.Value, error) {
+		if err := verifyArgCount([]int{1}, vals); err != nil {
+			return nil, err
+		}
+
+		if _, err := io.WriteString(w, sabre.Pretty(vals[0], PrettyWidth)+"\n"); err != nil {
+			return nil, err
+		}
+
+		return sabre.Nil{}, nil
+	}
+}
+
+func joinVals(vals []sabre.Value, readably bool) string {
+	parts := make([]string, len(vals))
+	for i, v := range vals {
+		if readably {
+			parts[i] = v.String()
+		} else {
+			parts[i] = sabre.Display(v)
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
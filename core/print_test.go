@@ -0,0 +1,102 @@
+package core_test
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/spy16/sabre"
+	"github.com/spy16/sabre/core"
+)
+
+func TestPrinter(t *testing.T) {
+	t.Parallel()
+
+	args := []sabre.Value{
+		sabre.String("a \"b\""),
+		sabre.Character('c'),
+		sabre.Vector{Values: []sabre.Value{sabre.String("d"), sabre.Float64(1)}},
+	}
+
+	table := []struct {
+		name     string
+		readably bool
+		newline  bool
+		want     string
+	}{
+		{
+			name:     "pr",
+			readably: true,
+			want:     `"a \"b\"" \c ["d" 1.0]`,
+		},
+		{
+			name:     "prn",
+			readably: true,
+			newline:  true,
+			want:     "\"a \\\"b\\\"\" \\c [\"d\" 1.0]\n",
+		},
+		{
+			name: "print",
+			want: `a "b" c [d 1.0]`,
+		},
+		{
+			name:    "println",
+			newline: true,
+			want:    "a \"b\" c [d 1.0]\n",
+		},
+	}
+
+	for _, tt := range table {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			got, err := core.Printer(&buf, tt.readably, tt.newline)(args)
+			if err != nil {
+				t.Fatalf("Printer() unexpected error: %v", err)
+			}
+
+			if got != (sabre.Nil{}) {
+				t.Errorf("Printer() got = %v, want nil", got)
+			}
+
+			if buf.String() != tt.want {
+				t.Errorf("Printer() wrote %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestPrStr(t *testing.T) {
+	t.Parallel()
+
+	got, err := core.PrStr([]sabre.Value{sabre.String("a\n"), sabre.Character(' '), sabre.Float64(0.5)})
+	if err != nil {
+		t.Fatalf("PrStr() unexpected error: %v", err)
+	}
+
+	want := sabre.String(`"a\n" \space 0.5`)
+	if got != want {
+		t.Errorf("PrStr() got = %v, want %v", got, want)
+	}
+}
+
+func TestPrettyPrinter(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	list := &sabre.List{}
+	for i := 0; i < 30; i++ {
+		list.Values = append(list.Values, sabre.Int64(i))
+	}
+
+	_, err := core.PrettyPrinter(&buf)([]sabre.Value{sabre.Vector{Values: []sabre.Value{list, sabre.Keyword("end")}}})
+	if err != nil {
+		t.Fatalf("PrettyPrinter() unexpected error: %v", err)
+	}
+
+	want := "[(0 1 2 3 4 5 6 7 8 9 10 11 12 13 14 15 16 17 18 19 20 21 22 23 24 25 26 27 28\n  29)\n :end]\n"
+	if buf.String() != want {
+		t.Errorf("PrettyPrinter() wrote %q, want %q", buf.String(), want)
+	}
+}
@@ -0,0 +1,225 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/spy16/sabre"
+)
+
+// DefProtocol expands the (defprotocol Name doc? (method [args+]+ doc?)+)
+// form into definitions of the protocol 'Name' and a function for each of
+// its methods. Methods dispatch on the type of their first argument.
+// Usage: (defprotocol Shape (area [s]) (scale [s factor]))
+func DefProtocol(args []sabre.Value) (sabre.Value, error) {
+	if len(args) < 1 {
+		return nil, fmt.Errorf("call requires at-least 1 argument(s), got 0")
+	}
+
+	name, isSymbol := args[0].(sabre.Symbol)
+	if !isSymbol {
+		return nil, fmt.Errorf("protocol name must be symbol, not '%s'", reflect.TypeOf(args[0]))
+	}
+
+	specs := args[1:]
+	if len(specs) > 0 {
+		if _, isDoc := specs[0].(sabre.String); isDoc {
+			specs = specs[1:]
+		}
+	}
+
+	var methods []string
+	for _, spec := range specs {
+		method, err := parseMethodSig(spec)
+		if err != nil {
+			return nil, err
+		}
+
+		methods = append(methods, method)
+	}
+
+	proto := sabre.NewProtocol(name.Value, methods...)
+
+	defs := []sabre.Value{symbol("do")}
+	for _, method := range methods {
+		fn := sabre.ProtocolFn{Protocol: proto, Name: method}
+		defs = append(defs, makeList(symbol("def"), symbol(method), fn))
+	}
+
+	return makeList(append(defs, makeList(symbol("def"), name, proto))...), nil
+}
+
+// ExtendType expands the (extend-type Type (Protocol (method [args] expr*)+)+)
+// form into a call to extend with the methods as functions. Type must be a
+// record type or a type such as Int64, String or Vector.
+// Usage: (extend-type Int64 Shape (area [n] (* n n)))
+func ExtendType(args []sabre.Value) (sabre.Value, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("call requires at-least 2 argument(s), got %d", len(args))
+	}
+
+	call := []sabre.Value{Fn(Extend), args[0]}
+
+	var impls []sabre.Value
+	for _, arg := range args[1:] {
+		if _, isSymbol := arg.(sabre.Symbol); isSymbol {
+			if impls != nil {
+				call = append(call, makeMap(impls))
+			}
+
+			call = append(call, arg)
+			impls = []sabre.Value{}
+			continue
+		}
+
+		if impls == nil {
+			return nil, fmt.Errorf("expecting protocol name before method definitions")
+		}
+
+		name, fn, err := parseMethodImpl(arg)
+		if err != nil {
+			return nil, err
+		}
+
+		impls = append(impls, sabre.Keyword(name), fn)
+	}
+
+	return makeList(append(call, makeMap(impls))...), nil
+}
+
+// Extend registers the functions in each method map as implementations of
+// the protocol methods for the type. Keys of the method maps must be
+// keywords naming the methods.
+// Usage: (extend Type Protocol {:method fn}+)
+func Extend(vals []sabre.Value) (sabre.Value, error) {
+	if len(vals) < 3 || len(vals)%2 != 1 {
+		return nil, fmt.Errorf("extend requires a type and protocol-map pairs")
+	}
+
+	for i := 1; i < len(vals); i += 2 {
+		proto, isProtocol := vals[i].(*sabre.Protocol)
+		if !isProtocol {
+			return nil, fmt.Errorf("expecting protocol, not '%s'", reflect.TypeOf(vals[i]))
+		}
+
+		impls, err := toImplMap(vals[i+1])
+		if err != nil {
+			return nil, err
+		}
+
+		switch target := vals[0].(type) {
+		case Type:
+			err = proto.Extend(target.rt, impls)
+
+		case *sabre.RecordType:
+			err = proto.ExtendRecord(target, impls)
+
+		default:
+			err = fmt.Errorf("cannot extend protocol to '%s'", reflect.TypeOf(vals[0]))
+		}
+
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return sabre.Nil{}, nil
+}
+
+// Satisfies returns true if the protocol has been extended to the type of
+// the value.
+// Usage: (satisfies? Protocol value)
+func Satisfies(vals []sabre.Value) (sabre.Value, error) {
+	if err := verifyArgCount([]int{2}, vals); err != nil {
+		return nil, err
+	}
+
+	proto, isProtocol := vals[0].(*sabre.Protocol)
+	if !isProtocol {
+		return nil, fmt.Errorf("expecting protocol, not '%s'", reflect.TypeOf(vals[0]))
+	}
+
+	return sabre.Bool(proto.Satisfies(vals[1])), nil
+}
+
+// NewType returns the type value for the Go type. Binding the type value
+// allows protocols to be extended to the Go type using extend-type.
+func NewType(rt reflect.Type) Type {
+	return Type{rt: rt}
+}
+
+// parseMethodSig validates the method signature (method [args+]+ doc?) and
+// returns the name of the method.
+func parseMethodSig(spec sabre.Value) (string, error) {
+	lf, isList := spec.(*sabre.List)
+	if !isList || lf.Size() < 2 {
+		return "", fmt.Errorf("method signature must be of the form (name [args]+)")
+	}
+
+	name, isSymbol := lf.Values[0].(sabre.Symbol)
+	if !isSymbol {
+		return "", fmt.Errorf("method name must be symbol, not '%s'", reflect.TypeOf(lf.Values[0]))
+	}
+
+	sigs := lf.Values[1:]
+	if _, isDoc := sigs[len(sigs)-1].(sabre.String); isDoc {
+		sigs = sigs[:len(sigs)-1]
+	}
+
+	if len(sigs) == 0 {
+		return "", fmt.Errorf("method '%s' must have at-least one argument vector", name)
+	}
+
+	for _, sig := range sigs {
+		vec, isVector := sig.(sabre.Vector)
+		if !isVector || len(vec.Values) == 0 {
+			return "", fmt.Errorf("arguments of method '%s' must be a non-empty vector", name)
+		}
+	}
+
+	return name.Value, nil
+}
+
+// parseMethodImpl converts the method definition (method [args] expr*) or
+// (method ([args] expr*)+) into the equivalent (fn* ...) form and returns it
+// along with the name of the method. The function is not named so that calls
+// to the method within the body dispatch through the protocol.
+func parseMethodImpl(v sabre.Value) (string, *sabre.List, error) {
+	lf, isList := v.(*sabre.List)
+	if !isList || lf.Size() < 2 {
+		return "", nil, fmt.Errorf("method definition must be of the form (name [args] expr*)")
+	}
+
+	name, isSymbol := lf.Values[0].(sabre.Symbol)
+	if !isSymbol {
+		return "", nil, fmt.Errorf("method name must be symbol, not '%s'", reflect.TypeOf(lf.Values[0]))
+	}
+
+	fn := makeList(append([]sabre.Value{symbol("fn*")}, lf.Values[1:]...)...)
+	fn.Position = lf.Position
+	return name.Value, fn, nil
+}
+
+func toImplMap(v sabre.Value) (map[string]sabre.Value, error) {
+	hm, isMap := v.(*sabre.HashMap)
+	if !isMap {
+		return nil, fmt.Errorf("method map must be a map, not '%s'", reflect.TypeOf(v))
+	}
+
+	impls := map[string]sabre.Value{}
+	for _, key := range hm.Keys() {
+		kw, isKeyword := key.(sabre.Keyword)
+		if !isKeyword {
+			return nil, fmt.Errorf("method map keys must be keywords, not '%s'", reflect.TypeOf(key))
+		}
+
+		impls[string(kw)], _ = hm.Get(key)
+	}
+
+	return impls, nil
+}
+
+func makeMap(kvs []sabre.Value) *sabre.HashMap {
+	hm, _ := sabre.NewHashMap(kvs...)
+	return hm
+}
@@ -0,0 +1,245 @@
+package core_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/spy16/sabre"
+	"github.com/spy16/sabre/core"
+)
+
+func TestRecordsAndProtocols(t *testing.T) {
+	t.Parallel()
+
+	const defs = `
+(defrecord Point [x y])
+(defrecord Circle [center radius])
+(defprotocol Describe
+  "Describes values."
+  (describe [v] "returns a description")
+  (label [v] [v prefix]))
+`
+
+	table := []struct {
+		name    string
+		src     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "DefRecord",
+			src:  `(defrecord Pair [a b])`,
+			want: `Pair`,
+		},
+		{
+			name: "Constructor",
+			src:  `(->Point 1 2)`,
+			want: `#Point {:x 1, :y 2}`,
+		},
+		{
+			name: "MapConstructor",
+			src:  `[(map->Point {:y 2 :x 1}) (map->Point {:x 1})]`,
+			want: `[#Point {:x 1, :y 2} #Point {:x 1, :y nil}]`,
+		},
+		{
+			name: "FieldAccess",
+			src: `(def c (->Circle (->Point 1 2) 5))
+[(:radius c) (:y (:center c)) (:z c) (:z c :none) (count c)]`,
+			want: `[5 2 nil :none 2]`,
+		},
+		{
+			name: "RecordPredicate",
+			src:  `[(record? (->Point 1 2)) (record? {:x 1 :y 2})]`,
+			want: `[true false]`,
+		},
+		{
+			name:    "ConstructorArity",
+			src:     `(->Point 1)`,
+			wantErr: true,
+		},
+		{
+			name:    "MapConstructorUnknownField",
+			src:     `(map->Point {:x 1 :z 2})`,
+			wantErr: true,
+		},
+		{
+			name:    "DuplicateField",
+			src:     `(defrecord Bad [a a])`,
+			wantErr: true,
+		},
+		{
+			name: "ExtendRecord",
+			src: `(extend-type Point Describe
+  (describe [p] (str "point " (:x p) "," (:y p))))
+(describe (->Point 1 2))`,
+			want: `"point 1,2"`,
+		},
+		{
+			name: "ExtendBuiltins",
+			src: `(extend-type Int64 Describe (describe [n] (str "int " n)))
+(extend-type String Describe (describe [s] (str "string " s)))
+(extend-type Vector Describe (describe [v] (str "vector of " (count v))))
+[(describe 1) (describe "a") (describe [1 2 3])]`,
+			want: `["int 1" "string a" "vector of 3"]`,
+		},
+		{
+			name: "MultiArityMethod",
+			src: `(extend-type Keyword Describe
+  (label
+    ([k] (label k "key"))
+    ([k prefix] (str prefix ": " k))))
+[(label :a) (label :a "kw")]`,
+			want: `["key: :a" "kw: :a"]`,
+		},
+		{
+			name: "DispatchWithinMethod",
+			src: `(extend-type Int64 Describe (describe [n] (str "int " n)))
+(extend-type Vector Describe (describe [v] (str "first " (describe (v 0)))))
+(describe [1 2])`,
+			want: `"first int 1"`,
+		},
+		{
+			name: "Extend",
+			src: `(extend Nil Describe {:describe (fn* [_] "nothing")})
+(describe nil)`,
+			want: `"nothing"`,
+		},
+		{
+			name: "Satisfies",
+			src: `(extend-type Point Describe (describe [p] "point"))
+[(satisfies? Describe (->Point 1 2)) (satisfies? Describe (->Circle nil 1)) (satisfies? Describe 1)]`,
+			want: `[true false false]`,
+		},
+		{
+			name:    "NoImplementation",
+			src:     `(describe 1.5)`,
+			wantErr: true,
+		},
+		{
+			name:    "NoArgs",
+			src:     `(describe)`,
+			wantErr: true,
+		},
+		{
+			name:    "UnknownMethod",
+			src:     `(extend-type Int64 Describe (area [n] n))`,
+			wantErr: true,
+		},
+		{
+			name:    "ExtendNonType",
+			src:     `(extend-type 10 Describe (describe [n] n))`,
+			wantErr: true,
+		},
+		{
+			name:    "InvalidMethodSig",
+			src:     `(defprotocol Bad (method))`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range table {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			scope := sabre.NewScope(nil)
+			if err := core.BindAll(scope); err != nil {
+				t.Fatalf("BindAll() unexpected error: %v", err)
+			}
+
+			if _, err := sabre.ReadEvalStr(scope, defs); err != nil {
+				t.Fatalf("ReadEvalStr() unexpected error: %v", err)
+			}
+
+			got, err := sabre.ReadEvalStr(scope, tt.src)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && got.String() != tt.want {
+				t.Errorf("got = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordsAndProtocols_Compiled(t *testing.T) {
+	t.Parallel()
+
+	const src = `
+(defrecord Point [x y])
+(defprotocol Describe (describe [v]))
+(extend-type Point Describe (describe [p] (str "point " (:x p))))
+(extend-type Int64 Describe (describe [n] (str "int " n)))
+[(describe (->Point 1 2)) (describe 3)]`
+
+	backends := map[string]sabre.Backend{
+		"TreeWalk": sabre.TreeWalk,
+		"Closure":  sabre.Closure,
+		"Bytecode": sabre.Bytecode,
+	}
+
+	for name, backend := range backends {
+		backend := backend
+		t.Run(name, func(t *testing.T) {
+			scope := sabre.NewScope(nil)
+			if err := core.BindAll(scope); err != nil {
+				t.Fatalf("BindAll() unexpected error: %v", err)
+			}
+
+			prog, err := sabre.ReadCompile(scope, strings.NewReader(src), sabre.WithBackend(backend))
+			if err != nil {
+				t.Fatalf("ReadCompile() unexpected error: %v", err)
+			}
+
+			got, err := prog.Run(context.Background(), scope)
+			if err != nil {
+				t.Fatalf("Run() unexpected error: %v", err)
+			}
+
+			want := `["point 1" "int 3"]`
+			if got.String() != want {
+				t.Errorf("got = %s, want %s", got, want)
+			}
+		})
+	}
+}
+
+func TestRecordsAndProtocols_ExpansionPosition(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "MethodDefinition",
+			src:  "(defprotocol Shape (area [s]))\n(extend-type Int64 Shape (area s 1))",
+			want: "(Line 2, Column 26)",
+		},
+		{
+			name: "Expansion",
+			src:  "(def Shape 1)\n(extend-type Int64 Shape (area [s] 1))",
+			want: "(Line 2, Column 1)",
+		},
+	}
+
+	for _, tt := range table {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			scope := sabre.NewScope(nil)
+			if err := core.BindAll(scope); err != nil {
+				t.Fatalf("BindAll() unexpected error: %v", err)
+			}
+
+			prog, err := sabre.ReadCompile(scope, strings.NewReader(tt.src), sabre.WithBackend(sabre.Bytecode))
+			if err == nil {
+				_, err = prog.Run(context.Background(), scope)
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("error = %v, want error at %s", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/spy16/sabre"
+)
+
+// DefRecord expands the (defrecord Name [field*]) form into definitions of
+// the record type 'Name', the positional constructor '->Name' and the map
+// constructor 'map->Name'.
+// Usage: (defrecord Point [x y])
+func DefRecord(args []sabre.Value) (sabre.Value, error) {
+	if err := verifyArgCount([]int{2}, args); err != nil {
+		return nil, err
+	}
+
+	name, isSymbol := args[0].(sabre.Symbol)
+	if !isSymbol {
+		return nil, fmt.Errorf("record name must be symbol, not '%s'", reflect.TypeOf(args[0]))
+	}
+
+	vec, isVector := args[1].(sabre.Vector)
+	if !isVector {
+		return nil, fmt.Errorf("record fields must be a vector, not '%s'", reflect.TypeOf(args[1]))
+	}
+
+	syms, err := toSymbolList(vec.Values)
+	if err != nil {
+		return nil, err
+	}
+
+	rt := &sabre.RecordType{Name: name.Value}
+	for _, sym := range syms {
+		for _, field := range rt.Fields {
+			if field == sym.Value {
+				return nil, fmt.Errorf("duplicate field '%s' in record '%s'", field, rt.Name)
+			}
+		}
+
+		rt.Fields = append(rt.Fields, sym.Value)
+	}
+
+	newRecord := Fn(func(vals []sabre.Value) (sabre.Value, error) {
+		return rt.New(vals...)
+	})
+
+	mapToRecord := Fn(func(vals []sabre.Value) (sabre.Value, error) {
+		if err := verifyArgCount([]int{1}, vals); err != nil {
+			return nil, err
+		}
+
+		hm, isMap := vals[0].(*sabre.HashMap)
+		if !isMap {
+			return nil, fmt.Errorf("argument must be a map, not '%s'", reflect.TypeOf(vals[0]))
+		}
+
+		return rt.FromMap(hm)
+	})
+
+	return makeList(symbol("do"),
+		makeList(symbol("def"), symbol("->"+rt.Name), newRecord),
+		makeList(symbol("def"), symbol("map->"+rt.Name), mapToRecord),
+		makeList(symbol("def"), name, rt),
+	), nil
+}
@@ -0,0 +1,232 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"github.com/spy16/sabre"
+)
+
+// MakeAtom creates a new atom with the given initial value. A validator
+// function can be provided using (atom val :validator fn).
+func MakeAtom(scope sabre.Scope, args []sabre.Value) (sabre.Value, error) {
+	vals, err := evalValueList(scope, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyArgCount([]int{1, 3}, vals); err != nil {
+		return nil, err
+	}
+
+	atom := sabre.NewAtom(vals[0])
+	if len(vals) == 3 {
+		if vals[1] != sabre.Keyword("validator") {
+			return nil, fmt.Errorf("unknown option '%v'", vals[1])
+		}
+
+		fn, err := toInvokable(vals[2])
+		if err != nil {
+			return nil, err
+		}
+
+		if err := atom.SetValidator(scope, fn); err != nil {
+			return nil, err
+		}
+	}
+
+	return atom, nil
+}
+
+// Deref returns the current value of a reference type such as atom. For
+// blocking references like future and promise, (deref ref timeout-ms val)
+// returns val if the value is not available within timeout-ms.
+func Deref(scope sabre.Scope, args []sabre.Value) (sabre.Value, error) {
+	vals, err := evalValueList(scope, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyArgCount([]int{1, 3}, vals); err != nil {
+		return nil, err
+	}
+
+	if len(vals) == 1 {
+		if d, isDelay := vals[0].(*sabre.Delay); isDelay {
+			return d.Force(scope)
+		}
+
+		ref, isRef := vals[0].(sabre.Derefable)
+		if !isRef {
+			return nil, fmt.Errorf("cannot deref value of type '%s'", reflect.TypeOf(vals[0]))
+		}
+
+		return ref.Deref()
+	}
+
+	ref, isBlocking := vals[0].(sabre.BlockingDerefable)
+	if !isBlocking {
+		return nil, fmt.Errorf("cannot deref value of type '%s' with timeout",
+			reflect.TypeOf(vals[0]))
+	}
+
+	ms, isInt := vals[1].(sabre.Int64)
+	if !isInt {
+		return nil, fmt.Errorf("timeout must be integer milliseconds, not '%v'", vals[1])
+	}
+
+	return ref.DerefTimeout(time.Duration(ms)*time.Millisecond, vals[2])
+}
+
+// Swap atomically updates the value of the atom to the result of applying
+// the function to the current value and any additional args.
+// Usage: (swap! atom f & args)
+func Swap(scope sabre.Scope, args []sabre.Value) (sabre.Value, error) {
+	vals, err := evalValueList(scope, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(vals) < 2 {
+		return nil, fmt.Errorf("call requires at-least 2 argument(s), got %d", len(vals))
+	}
+
+	atom, err := toAtom(vals[0])
+	if err != nil {
+		return nil, err
+	}
+
+	fn, err := toInvokable(vals[1])
+	if err != nil {
+		return nil, err
+	}
+
+	return atom.Swap(scope, fn, vals[2:])
+}
+
+// Reset sets the value of the atom to the given value.
+// Usage: (reset! atom val)
+func Reset(scope sabre.Scope, args []sabre.Value) (sabre.Value, error) {
+	vals, err := evalValueList(scope, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyArgCount([]int{2}, vals); err != nil {
+		return nil, err
+	}
+
+	atom, err := toAtom(vals[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return atom.Reset(scope, vals[1])
+}
+
+// CompareAndSet sets the value of the atom only if the current value is
+// equal to the old value and returns true if the value was set.
+// Usage: (compare-and-set! atom old new)
+func CompareAndSet(scope sabre.Scope, args []sabre.Value) (sabre.Value, error) {
+	vals, err := evalValueList(scope, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyArgCount([]int{3}, vals); err != nil {
+		return nil, err
+	}
+
+	atom, err := toAtom(vals[0])
+	if err != nil {
+		return nil, err
+	}
+
+	ok, err := atom.CompareAndSet(scope, vals[1], vals[2])
+	return sabre.Bool(ok), err
+}
+
+// SetValidator sets (or removes if nil) the validator function of the atom.
+// Usage: (set-validator! atom fn)
+func SetValidator(scope sabre.Scope, args []sabre.Value) (sabre.Value, error) {
+	vals, err := evalValueList(scope, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyArgCount([]int{2}, vals); err != nil {
+		return nil, err
+	}
+
+	atom, err := toAtom(vals[0])
+	if err != nil {
+		return nil, err
+	}
+
+	var fn sabre.Invokable
+	if vals[1] != (sabre.Nil{}) {
+		fn, err = toInvokable(vals[1])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return sabre.Nil{}, atom.SetValidator(scope, fn)
+}
+
+// AddWatch adds a watch function to the atom with the given key. Watch
+// function is invoked as (fn key atom old new) after every change.
+// Usage: (add-watch atom key fn)
+func AddWatch(vals []sabre.Value) (sabre.Value, error) {
+	if err := verifyArgCount([]int{3}, vals); err != nil {
+		return nil, err
+	}
+
+	atom, err := toAtom(vals[0])
+	if err != nil {
+		return nil, err
+	}
+
+	fn, err := toInvokable(vals[2])
+	if err != nil {
+		return nil, err
+	}
+
+	atom.AddWatch(vals[1], fn)
+	return atom, nil
+}
+
+// RemoveWatch removes the watch with given key from the atom.
+// Usage: (remove-watch atom key)
+func RemoveWatch(vals []sabre.Value) (sabre.Value, error) {
+	if err := verifyArgCount([]int{2}, vals); err != nil {
+		return nil, err
+	}
+
+	atom, err := toAtom(vals[0])
+	if err != nil {
+		return nil, err
+	}
+
+	atom.RemoveWatch(vals[1])
+	return atom, nil
+}
+
+func toAtom(v sabre.Value) (*sabre.Atom, error) {
+	atom, isAtom := v.(*sabre.Atom)
+	if !isAtom {
+		return nil, fmt.Errorf("expecting atom, not '%s'", reflect.TypeOf(v))
+	}
+
+	return atom, nil
+}
+
+func toInvokable(v sabre.Value) (sabre.Invokable, error) {
+	fn, isInvokable := v.(sabre.Invokable)
+	if !isInvokable {
+		return nil, fmt.Errorf("value of type '%s' is not invokable", reflect.TypeOf(v))
+	}
+
+	return fn, nil
+}
@@ -0,0 +1,250 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sync"
+
+	"github.com/spy16/sabre"
+)
+
+// RePattern compiles the string into a regex.
+// Usage: (re-pattern "\\d+")
+func RePattern(vals []sabre.Value) (sabre.Value, error) {
+	if err := verifyArgCount([]int{1}, vals); err != nil {
+		return nil, err
+	}
+
+	if re, isRegex := vals[0].(sabre.Regex); isRegex {
+		return re, nil
+	}
+
+	s, isString := vals[0].(sabre.String)
+	if !isString {
+		return nil, fmt.Errorf("pattern must be a string, not '%s'", reflect.TypeOf(vals[0]))
+	}
+
+	re, err := regexp.Compile(string(s))
+	if err != nil {
+		return nil, err
+	}
+
+	return sabre.Regex{Regexp: re}, nil
+}
+
+// ReMatcher returns a matcher that can be used with re-find to find the
+// successive matches of the regex in the string and with re-groups to get
+// the groups of the last match.
+// Usage: (re-matcher re s)
+func ReMatcher(vals []sabre.Value) (sabre.Value, error) {
+	if err := verifyArgCount([]int{2}, vals); err != nil {
+		return nil, err
+	}
+
+	re, s, err := regexArgs(vals)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Matcher{re: re, input: s}, nil
+}
+
+// ReFind returns the next match of the regex in the string. Match is
+// returned as a string if the regex has no groups and as a vector of the
+// match followed by the groups otherwise. Returns nil if there is no match.
+// Usage: (re-find re s) or (re-find matcher)
+func ReFind(vals []sabre.Value) (sabre.Value, error) {
+	if err := verifyArgCount([]int{1, 2}, vals); err != nil {
+		return nil, err
+	}
+
+	if len(vals) == 1 {
+		m, err := toMatcher(vals[0])
+		if err != nil {
+			return nil, err
+		}
+
+		return m.Find(), nil
+	}
+
+	re, s, err := regexArgs(vals)
+	if err != nil {
+		return nil, err
+	}
+
+	return matchResult(s, re.FindStringSubmatchIndex(s)), nil
+}
+
+// ReMatches returns the match if the regex matches the entire string and
+// nil otherwise. Result is same as that of re-find.
+// Usage: (re-matches re s)
+func ReMatches(vals []sabre.Value) (sabre.Value, error) {
+	if err := verifyArgCount([]int{2}, vals); err != nil {
+		return nil, err
+	}
+
+	re, s, err := regexArgs(vals)
+	if err != nil {
+		return nil, err
+	}
+
+	whole, err := regexp.Compile(`^(?:` + re.String() + `)$`)
+	if err != nil {
+		return nil, err
+	}
+
+	return matchResult(s, whole.FindStringSubmatchIndex(s)), nil
+}
+
+// ReSeq returns a list of all the successive matches of the regex in the
+// string or nil if there are no matches.
+// Usage: (re-seq re s)
+func ReSeq(vals []sabre.Value) (sabre.Value, error) {
+	if err := verifyArgCount([]int{2}, vals); err != nil {
+		return nil, err
+	}
+
+	re, s, err := regexArgs(vals)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []sabre.Value
+	for _, loc := range re.FindAllStringSubmatchIndex(s, -1) {
+		matches = append(matches, matchResult(s, loc))
+	}
+
+	if len(matches) == 0 {
+		return sabre.Nil{}, nil
+	}
+
+	return &sabre.List{Values: matches}, nil
+}
+
+// ReGroups returns the groups of the last match found by the matcher.
+// Usage: (re-groups matcher)
+func ReGroups(vals []sabre.Value) (sabre.Value, error) {
+	if err := verifyArgCount([]int{1}, vals); err != nil {
+		return nil, err
+	}
+
+	m, err := toMatcher(vals[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return m.Groups()
+}
+
+// Matcher holds the state of successive matches of a regex in a string.
+type Matcher struct {
+	mu    sync.Mutex
+	re    *regexp.Regexp
+	input string
+	pos   int
+	last  []int
+}
+
+// Eval returns the matcher itself.
+func (m *Matcher) Eval(_ sabre.Scope) (sabre.Value, error) { return m, nil }
+
+func (m *Matcher) String() string {
+	return fmt.Sprintf("Matcher{%s}", sabre.Regex{Regexp: m.re})
+}
+
+// Find returns the next match of the regex in the input (same as re-find)
+// or nil if there are no more matches.
+func (m *Matcher) Find() sabre.Value {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.last = nil
+	if m.pos > len(m.input) {
+		return sabre.Nil{}
+	}
+
+	loc := m.re.FindStringSubmatchIndex(m.input[m.pos:])
+	if loc == nil {
+		m.pos = len(m.input) + 1
+		return sabre.Nil{}
+	}
+
+	for i := range loc {
+		if loc[i] >= 0 {
+			loc[i] += m.pos
+		}
+	}
+
+	m.last = loc
+	if loc[1] > loc[0] {
+		m.pos = loc[1]
+	} else {
+		m.pos = loc[1] + 1 // avoid matching the same empty match again.
+	}
+
+	return matchResult(m.input, loc)
+}
+
+// Groups returns the groups of the last match (same as re-groups).
+func (m *Matcher) Groups() (sabre.Value, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.last == nil {
+		return nil, errors.New("no match found")
+	}
+
+	return matchResult(m.input, m.last), nil
+}
+
+// matchResult converts the match locations returned by the regexp package
+// into a string (if there are no groups) or a vector of the match followed
+// by the groups. Groups that did not participate in the match are nil.
+func matchResult(s string, loc []int) sabre.Value {
+	if loc == nil {
+		return sabre.Nil{}
+	}
+
+	if len(loc) == 2 {
+		return sabre.String(s[loc[0]:loc[1]])
+	}
+
+	groups := make([]sabre.Value, len(loc)/2)
+	for i := range groups {
+		start, end := loc[2*i], loc[2*i+1]
+		if start < 0 {
+			groups[i] = sabre.Nil{}
+		} else {
+			groups[i] = sabre.String(s[start:end])
+		}
+	}
+
+	return sabre.Vector{Values: groups}
+}
+
+func regexArgs(vals []sabre.Value) (*regexp.Regexp, string, error) {
+	re, isRegex := vals[0].(sabre.Regex)
+	if !isRegex {
+		return nil, "", fmt.Errorf("first argument must be a regex, not '%s'",
+			reflect.TypeOf(vals[0]))
+	}
+
+	s, isString := vals[1].(sabre.String)
+	if !isString {
+		return nil, "", fmt.Errorf("second argument must be a string, not '%s'",
+			reflect.TypeOf(vals[1]))
+	}
+
+	return re.Regexp, string(s), nil
+}
+
+func toMatcher(v sabre.Value) (*Matcher, error) {
+	m, isMatcher := v.(*Matcher)
+	if !isMatcher {
+		return nil, fmt.Errorf("argument must be a matcher, not '%s'", reflect.TypeOf(v))
+	}
+
+	return m, nil
+}
@@ -0,0 +1,101 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/spy16/sabre"
+	"github.com/spy16/sabre/core"
+)
+
+func TestRegex(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		name    string
+		src     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "FindNoGroups",
+			src:  `(re-find #"\d+" "abc 123 456")`,
+			want: `"123"`,
+		},
+		{
+			name: "FindGroups",
+			src:  `(re-find #"(\w+)@(\w+)?" "mail: bob@")`,
+			want: `["bob@" "bob" nil]`,
+		},
+		{
+			name: "FindNoMatch",
+			src:  `(re-find #"\d+" "abc")`,
+			want: `nil`,
+		},
+		{
+			name: "Matches",
+			src:  `[(re-matches #"\d+" "123") (re-matches #"\d+" "123a") (re-matches #"a|ab" "ab")]`,
+			want: `["123" nil "ab"]`,
+		},
+		{
+			name: "Seq",
+			src:  `(re-seq #"(\d)(\w)" "1a 2b 3")`,
+			want: `(["1a" "1" "a"] ["2b" "2" "b"])`,
+		},
+		{
+			name: "SeqNoMatch",
+			src:  `(re-seq #"\d" "abc")`,
+			want: `nil`,
+		},
+		{
+			name: "Matcher",
+			src: `(def m (re-matcher #"(\d)" "1 2"))
+[(re-find m) (re-groups m) (re-find m) (re-find m)]`,
+			want: `[["1" "1"] ["1" "1"] ["2" "2"] nil]`,
+		},
+		{
+			name: "MatcherEmptyMatches",
+			src: `(def m (re-matcher #"x*" "ab"))
+[(re-find m) (re-find m) (re-find m) (re-find m)]`,
+			want: `["" "" "" nil]`,
+		},
+		{
+			name:    "GroupsWithoutMatch",
+			src:     `(re-groups (re-matcher #"\d" "a"))`,
+			wantErr: true,
+		},
+		{
+			name: "Pattern",
+			src:  `[(re-pattern "a\"b") (regex? (re-pattern #"x"))]`,
+			want: `[#"a\"b" true]`,
+		},
+		{
+			name:    "InvalidPattern",
+			src:     `(re-pattern "a(")`,
+			wantErr: true,
+		},
+		{
+			name:    "NotRegex",
+			src:     `(re-find "a" "abc")`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range table {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			scope := sabre.NewScope(nil)
+			if err := core.BindAll(scope); err != nil {
+				t.Fatalf("BindAll() unexpected error: %v", err)
+			}
+
+			got, err := sabre.ReadEvalStr(scope, tt.src)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && got.String() != tt.want {
+				t.Errorf("got = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,410 @@
+package core
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/spy16/sabre"
+)
+
+// Subs returns the substring of s from start (inclusive) to end (exclusive)
+// or to the end of the string. Indices are in characters (runes), not bytes.
+// Usage: (subs s start) or (subs s start end)
+func Subs(vals []sabre.Value) (sabre.Value, error) {
+	if err := verifyArgCount([]int{2, 3}, vals); err != nil {
+		return nil, err
+	}
+
+	s, err := toString(vals[0])
+	if err != nil {
+		return nil, err
+	}
+	runes := []rune(s)
+
+	start, err := toIndex(vals[1])
+	if err != nil {
+		return nil, err
+	}
+
+	end := len(runes)
+	if len(vals) == 3 {
+		if end, err = toIndex(vals[2]); err != nil {
+			return nil, err
+		}
+	}
+
+	if start > end || end > len(runes) {
+		return nil, fmt.Errorf("string index out of range [%d:%d] with length %d",
+			start, end, len(runes))
+	}
+
+	return sabre.String(runes[start:end]), nil
+}
+
+// Split splits the string around the matches of the separator which can
+// be a string, a character or a regex. Limit, if given and positive, is the
+// maximum number of parts returned.
+// Usage: (string/split s sep) or (string/split s sep limit)
+func Split(vals []sabre.Value) (sabre.Value, error) {
+	if err := verifyArgCount([]int{2, 3}, vals); err != nil {
+		return nil, err
+	}
+
+	s, err := toString(vals[0])
+	if err != nil {
+		return nil, err
+	}
+
+	limit := -1
+	if len(vals) == 3 {
+		if limit, err = toIndex(vals[2]); err != nil {
+			return nil, err
+		}
+
+		if limit == 0 {
+			limit = -1
+		}
+	}
+
+	var parts []string
+	if re, isRegex := vals[1].(sabre.Regex); isRegex {
+		parts = re.Split(s, limit)
+	} else {
+		sep, err := toString(vals[1])
+		if err != nil {
+			return nil, err
+		}
+		parts = strings.SplitN(s, sep, limit)
+	}
+
+	res := make([]sabre.Value, len(parts))
+	for i, part := range parts {
+		res[i] = sabre.String(part)
+	}
+
+	return sabre.Vector{Values: res}, nil
+}
+
+// Join returns the string obtained by concatenating the items of the
+// collection (same as str) with the separator between them.
+// Usage: (string/join coll) or (string/join sep coll)
+func Join(vals []sabre.Value) (sabre.Value, error) {
+	if err := verifyArgCount([]int{1, 2}, vals); err != nil {
+		return nil, err
+	}
+
+	sep, coll := "", vals[len(vals)-1]
+	if len(vals) == 2 {
+		var err error
+		if sep, err = toString(vals[0]); err != nil {
+			return nil, err
+		}
+	}
+
+	items, err := toItems(coll)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := make([]string, len(items))
+	for i, item := range items {
+		parts[i] = string(stringFromVals([]sabre.Value{item}))
+	}
+
+	return sabre.String(strings.Join(parts, sep)), nil
+}
+
+// Chars returns the characters of the string as a vector. (string/join v)
+// converts the characters back to a string.
+// Usage: (string/chars s)
+func Chars(vals []sabre.Value) (sabre.Value, error) {
+	if err := verifyArgCount([]int{1}, vals); err != nil {
+		return nil, err
+	}
+
+	s, err := toString(vals[0])
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]sabre.Value, 0, utf8.RuneCountInString(s))
+	for _, r := range s {
+		res = append(res, sabre.Character(r))
+	}
+
+	return sabre.Vector{Values: res}, nil
+}
+
+// StringFn returns a function which applies the transformation to its only
+// string argument. It is used for trim, upper-case etc.
+func StringFn(transform func(s string) string) Fn {
+	return func(vals []sabre.Value) (sabre.Value, error) {
+		if err := verifyArgCount([]int{1}, vals); err != nil {
+			return nil, err
+		}
+
+		s, err := toString(vals[0])
+		if err != nil {
+			return nil, err
+		}
+
+		return sabre.String(transform(s)), nil
+	}
+}
+
+// StringPredicate returns a function which reports whether the predicate
+// holds for its 2 string (or character) arguments. It is used for
+// starts-with?, ends-with? and includes?.
+func StringPredicate(pred func(s, substr string) bool) Fn {
+	return func(vals []sabre.Value) (sabre.Value, error) {
+		if err := verifyArgCount([]int{2}, vals); err != nil {
+			return nil, err
+		}
+
+		s, err := toString(vals[0])
+		if err != nil {
+			return nil, err
+		}
+
+		substr, err := toString(vals[1])
+		if err != nil {
+			return nil, err
+		}
+
+		return sabre.Bool(pred(s, substr)), nil
+	}
+}
+
+// IndexOf returns the index (in characters) of the first occurrence of the
+// string or character in s at or after 'from'. Returns nil if not found.
+// Usage: (string/index-of s value) or (string/index-of s value from)
+func IndexOf(vals []sabre.Value) (sabre.Value, error) {
+	if err := verifyArgCount([]int{2, 3}, vals); err != nil {
+		return nil, err
+	}
+
+	s, err := toString(vals[0])
+	if err != nil {
+		return nil, err
+	}
+
+	substr, err := toString(vals[1])
+	if err != nil {
+		return nil, err
+	}
+
+	from := 0
+	if len(vals) == 3 {
+		if from, err = toIndex(vals[2]); err != nil {
+			return nil, err
+		}
+	}
+
+	runes := []rune(s)
+	if from > len(runes) {
+		return sabre.Nil{}, nil
+	}
+
+	idx := strings.Index(string(runes[from:]), substr)
+	if idx < 0 {
+		return sabre.Nil{}, nil
+	}
+
+	return sabre.Int64(from + utf8.RuneCountInString(string(runes[from:])[:idx])), nil
+}
+
+// Replace replaces all the matches in the string. Match can be a string or
+// a character in which case the replacement must be of the same type, or a
+// regex in which case the replacement can be a string ($1, ${name} etc.
+// are expanded to the groups) or a function which is called with the match
+// (See re-find) and returns the replacement.
+// Usage: (string/replace s match replacement)
+func Replace(scope sabre.Scope, args []sabre.Value) (sabre.Value, error) {
+	vals, err := evalValueList(scope, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := verifyArgCount([]int{3}, vals); err != nil {
+		return nil, err
+	}
+
+	s, err := toString(vals[0])
+	if err != nil {
+		return nil, err
+	}
+
+	re, isRegex := vals[1].(sabre.Regex)
+	if !isRegex {
+		match, err := toString(vals[1])
+		if err != nil {
+			return nil, err
+		}
+
+		repl, err := toString(vals[2])
+		if err != nil {
+			return nil, err
+		}
+
+		return sabre.String(strings.Replace(s, match, repl, -1)), nil
+	}
+
+	if fn, isInvokable := vals[2].(sabre.Invokable); isInvokable {
+		return replaceFunc(scope, re.Regexp, s, fn)
+	}
+
+	repl, err := toString(vals[2])
+	if err != nil {
+		return nil, err
+	}
+
+	return sabre.String(re.ReplaceAllString(s, repl)), nil
+}
+
+// IsBlank returns true if the argument is nil or a string containing only
+// whitespace characters.
+// Usage: (string/blank? s)
+func IsBlank(vals []sabre.Value) (sabre.Value, error) {
+	if err := verifyArgCount([]int{1}, vals); err != nil {
+		return nil, err
+	}
+
+	if vals[0] == (sabre.Nil{}) {
+		return sabre.Bool(true), nil
+	}
+
+	s, err := toString(vals[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return sabre.Bool(strings.TrimSpace(s) == ""), nil
+}
+
+// Format returns the string formatted using the Go fmt verbs. Numbers,
+// strings, booleans and characters are passed to fmt as the equivalent Go
+// values and other values as is (i.e., %s and %v use their LISP form).
+// Usage: (format "%s has %d items (%.2f%%)" name n pct)
+func Format(vals []sabre.Value) (sabre.Value, error) {
+	if len(vals) < 1 {
+		return nil, fmt.Errorf("call requires at-least 1 argument(s), got %d", len(vals))
+	}
+
+	format, err := toString(vals[0])
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]interface{}, len(vals)-1)
+	for i, v := range vals[1:] {
+		args[i] = formatArg(v)
+	}
+
+	return sabre.String(fmt.Sprintf(format, args...)), nil
+}
+
+func replaceFunc(scope sabre.Scope, re *regexp.Regexp, s string, fn sabre.Invokable) (sabre.Value, error) {
+	var sb strings.Builder
+
+	last := 0
+	for _, loc := range re.FindAllStringSubmatchIndex(s, -1) {
+		res, err := sabre.Apply(scope, fn, []sabre.Value{matchResult(s, loc)})
+		if err != nil {
+			return nil, err
+		}
+
+		repl, err := toString(res)
+		if err != nil {
+			return nil, err
+		}
+
+		sb.WriteString(s[last:loc[0]])
+		sb.WriteString(repl)
+		last = loc[1]
+	}
+	sb.WriteString(s[last:])
+
+	return sabre.String(sb.String()), nil
+}
+
+func formatArg(v sabre.Value) interface{} {
+	switch val := v.(type) {
+	case sabre.Nil:
+		return nil
+
+	case sabre.Bool:
+		return bool(val)
+
+	case sabre.Int64:
+		return int64(val)
+
+	case sabre.BigInt:
+		return val.Int
+
+	case sabre.Float64:
+		return float64(val)
+
+	case sabre.String:
+		return string(val)
+
+	case sabre.Character:
+		return rune(val)
+
+	default:
+		return v
+	}
+}
+
+// toString returns the string value of a String or a Character.
+func toString(v sabre.Value) (string, error) {
+	switch val := v.(type) {
+	case sabre.String:
+		return string(val), nil
+
+	case sabre.Character:
+		return string(val), nil
+
+	default:
+		return "", fmt.Errorf("expecting string, not '%s'", reflect.TypeOf(v))
+	}
+}
+
+func toIndex(v sabre.Value) (int, error) {
+	i, isInt := v.(sabre.Int64)
+	if !isInt || i < 0 {
+		return 0, fmt.Errorf("expecting non-negative integer, not '%s'", v)
+	}
+
+	return int(i), nil
+}
+
+func toItems(v sabre.Value) ([]sabre.Value, error) {
+	switch coll := v.(type) {
+	case sabre.Nil:
+		return nil, nil
+
+	case *sabre.List:
+		return coll.Values, nil
+
+	case sabre.Vector:
+		return coll.Values, nil
+
+	case sabre.Set:
+		return coll.Values, nil
+
+	case sabre.String:
+		items, _ := Chars([]sabre.Value{coll})
+		return items.(sabre.Vector).Values, nil
+
+	default:
+		return nil, fmt.Errorf("expecting a collection, not '%s'", reflect.TypeOf(v))
+	}
+}
+
+func trimLeft(s string) string { return strings.TrimLeftFunc(s, unicode.IsSpace) }
+
+func trimRight(s string) string { return strings.TrimRightFunc(s, unicode.IsSpace) }
@@ -0,0 +1,142 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/spy16/sabre"
+	"github.com/spy16/sabre/core"
+)
+
+func TestStrings(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		name    string
+		src     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Count",
+			src:  `[(count "héllo😀") (count [1 2]) (count {:a 1}) (count nil) (count "")]`,
+			want: `[6 2 1 0 0]`,
+		},
+		{
+			name:    "CountInvalid",
+			src:     `(count 10)`,
+			wantErr: true,
+		},
+		{
+			name: "Subs",
+			src:  `[(subs "héllo" 1) (subs "héllo" 1 3) (subs "abc" 3)]`,
+			want: `["éllo" "él" ""]`,
+		},
+		{
+			name:    "SubsOutOfRange",
+			src:     `(subs "abc" 2 5)`,
+			wantErr: true,
+		},
+		{
+			name:    "SubsNegative",
+			src:     `(subs "abc" -1)`,
+			wantErr: true,
+		},
+		{
+			name: "Split",
+			src:  `[(string/split "a,b,,c" ",") (string/split "a1b22c" #"\d+") (string/split "a b c" \space 2)]`,
+			want: `[["a" "b" "" "c"] ["a" "b" "c"] ["a" "b c"]]`,
+		},
+		{
+			name: "Join",
+			src:  `[(string/join [1 "a" \b]) (string/join ", " ["x" :y]) (string/join "-" nil)]`,
+			want: `["1ab" "x, :y" ""]`,
+		},
+		{
+			name:    "JoinInvalid",
+			src:     `(string/join ", " 1)`,
+			wantErr: true,
+		},
+		{
+			name: "Chars",
+			src:  `[(string/chars "añ😀") (string/join (string/chars "añ😀"))]`,
+			want: `[[\a \ñ \😀] "añ😀"]`,
+		},
+		{
+			name: "Trim",
+			src:  `[(string/trim "　 a b \n") (string/triml "  a ") (string/trimr "  a ")]`,
+			want: `["a b" "a " "  a"]`,
+		},
+		{
+			name: "Case",
+			src:  `[(string/upper-case "héllo ñ") (string/lower-case "ÀB")]`,
+			want: `["HÉLLO Ñ" "àb"]`,
+		},
+		{
+			name: "Predicates",
+			src: `[(string/starts-with? "héllo" "hé") (string/ends-with? "héllo" \o)
+ (string/includes? "héllo" "ll") (string/includes? "héllo" "x")]`,
+			want: `[true true true false]`,
+		},
+		{
+			name:    "PredicateInvalid",
+			src:     `(string/includes? "a" 1)`,
+			wantErr: true,
+		},
+		{
+			name: "IndexOf",
+			src:  `[(string/index-of "héllo hé" "l") (string/index-of "héllo hé" "hé" 1) (string/index-of "abc" "x") (string/index-of "abc" "a" 10)]`,
+			want: `[2 6 nil nil]`,
+		},
+		{
+			name: "Replace",
+			src: `[(string/replace "a.b.c" "." "/") (string/replace "añb" \ñ \n)
+ (string/replace "k1=v1 k2=v2" #"(\w+)=(\w+)" "$2:$1")
+ (string/replace "a1b22" #"\d+" (fn* [m] (str "<" m ">")))]`,
+			want: `["a/b/c" "anb" "v1:k1 v2:k2" "a<1>b<22>"]`,
+		},
+		{
+			name:    "ReplaceInvalid",
+			src:     `(string/replace "abc" "a" 1)`,
+			wantErr: true,
+		},
+		{
+			name:    "ReplaceFnError",
+			src:     `(string/replace "a1" #"\d" (fn* [m] 1))`,
+			wantErr: true,
+		},
+		{
+			name: "Blank",
+			src:  `[(string/blank? nil) (string/blank? " \t\n") (string/blank? " a ")]`,
+			want: `[true true false]`,
+		},
+		{
+			name: "Format",
+			src:  `(format "%s=%d %.2f %t %c %q %v %5s|" "n" 42 3.14159 true \λ "q" [1 :a] nil)`,
+			want: `"n=42 3.14 true λ \"q\" [1 :a] %!s(<nil>)|"`,
+		},
+		{
+			name:    "FormatNoArgs",
+			src:     `(format)`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range table {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			scope := sabre.NewScope(nil)
+			if err := core.BindAll(scope); err != nil {
+				t.Fatalf("BindAll() unexpected error: %v", err)
+			}
+
+			got, err := sabre.ReadEvalStr(scope, tt.src)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && got.String() != tt.want {
+				t.Errorf("got = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
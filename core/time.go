@@ -0,0 +1,325 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/spy16/sabre"
+)
+
+// durationUnits maps the unit keywords accepted by time/duration to the
+// durations they represent.
+var durationUnits = map[sabre.Keyword]time.Duration{
+	"nanos":   time.Nanosecond,
+	"micros":  time.Microsecond,
+	"millis":  time.Millisecond,
+	"seconds": time.Second,
+	"minutes": time.Minute,
+	"hours":   time.Hour,
+	"days":    24 * time.Hour,
+}
+
+type clockKey struct{}
+
+// WithClock returns a context which makes time/now evaluated with it (See
+// sabre.WithContext) return the time returned by 'now' instead of the
+// current time. Useful for deterministic tests and simulations.
+func WithClock(ctx context.Context, now func() time.Time) context.Context {
+	return context.WithValue(ctx, clockKey{}, now)
+}
+
+// Now returns the current time (or the time returned by the clock set
+// using WithClock).
+// Usage: (time/now)
+func Now(scope sabre.Scope, args []sabre.Value) (sabre.Value, error) {
+	if err := verifyArgCount(nil, args); err != nil {
+		return nil, err
+	}
+
+	if now, ok := sabre.Context(scope).Value(clockKey{}).(func() time.Time); ok {
+		return sabre.Time{Time: now()}, nil
+	}
+
+	return sabre.Time{Time: time.Now()}, nil
+}
+
+// ParseTime parses the string using the Go layout (e.g., "2006-01-02 15:04").
+// Times without zone information are parsed in the given zone (UTC by
+// default).
+// Usage: (time/parse layout s) or (time/parse layout s zone)
+func ParseTime(vals []sabre.Value) (sabre.Value, error) {
+	if err := verifyArgCount([]int{2, 3}, vals); err != nil {
+		return nil, err
+	}
+
+	layout, err := toString(vals[0])
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := toString(vals[1])
+	if err != nil {
+		return nil, err
+	}
+
+	loc := time.UTC
+	if len(vals) == 3 {
+		if loc, err = toLocation(vals[2]); err != nil {
+			return nil, err
+		}
+	}
+
+	t, err := time.ParseInLocation(layout, s, loc)
+	if err != nil {
+		return nil, err
+	}
+
+	return sabre.Time{Time: t}, nil
+}
+
+// FormatTime formats the time using the Go layout.
+// Usage: (time/format t "2006-01-02")
+func FormatTime(vals []sabre.Value) (sabre.Value, error) {
+	if err := verifyArgCount([]int{2}, vals); err != nil {
+		return nil, err
+	}
+
+	t, err := toTime(vals[0])
+	if err != nil {
+		return nil, err
+	}
+
+	layout, err := toString(vals[1])
+	if err != nil {
+		return nil, err
+	}
+
+	return sabre.String(t.Format(layout)), nil
+}
+
+// MakeDuration returns a duration from a Go duration string (e.g., "1h30m")
+// or from an amount and a unit (:nanos, :micros, :millis, :seconds,
+// :minutes, :hours or :days).
+// Usage: (time/duration "1h30m") or (time/duration 90 :minutes)
+func MakeDuration(vals []sabre.Value) (sabre.Value, error) {
+	if err := verifyArgCount([]int{1, 2}, vals); err != nil {
+		return nil, err
+	}
+
+	if len(vals) == 1 {
+		s, err := toString(vals[0])
+		if err != nil {
+			return nil, err
+		}
+
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			return nil, err
+		}
+		return sabre.Duration(d), nil
+	}
+
+	unit, found := durationUnits[toKeyword(vals[1])]
+	if !found {
+		return nil, fmt.Errorf("invalid duration unit '%s'", vals[1])
+	}
+
+	switch n := vals[0].(type) {
+	case sabre.Int64:
+		return sabre.Duration(time.Duration(n) * unit), nil
+
+	case sabre.Float64:
+		return sabre.Duration(float64(n) * float64(unit)), nil
+
+	default:
+		return nil, fmt.Errorf("expecting number, not '%s'", reflect.TypeOf(vals[0]))
+	}
+}
+
+// Plus adds the durations to the time or to the first duration.
+// Usage: (time/plus t d...) or (time/plus d d...)
+func Plus(vals []sabre.Value) (sabre.Value, error) {
+	if len(vals) < 1 {
+		return nil, fmt.Errorf("call requires at-least 1 argument(s), got %d", len(vals))
+	}
+
+	total, err := sumDurations(vals[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := vals[0].(type) {
+	case sabre.Time:
+		return sabre.Time{Time: v.Add(total)}, nil
+
+	case sabre.Duration:
+		return v + sabre.Duration(total), nil
+
+	default:
+		return nil, fmt.Errorf("expecting time or duration, not '%s'", reflect.TypeOf(vals[0]))
+	}
+}
+
+// Minus subtracts the durations from the time or from the first duration.
+// If both arguments are times, returns the duration between them.
+// Usage: (time/minus t d...), (time/minus d d...) or (time/minus t1 t2)
+func Minus(vals []sabre.Value) (sabre.Value, error) {
+	if len(vals) < 1 {
+		return nil, fmt.Errorf("call requires at-least 1 argument(s), got %d", len(vals))
+	}
+
+	if t1, isTime := vals[0].(sabre.Time); isTime && len(vals) == 2 {
+		if t2, isTime := vals[1].(sabre.Time); isTime {
+			return sabre.Duration(t1.Sub(t2.Time)), nil
+		}
+	}
+
+	total, err := sumDurations(vals[1:])
+	if err != nil {
+		return nil, err
+	}
+
+	switch v := vals[0].(type) {
+	case sabre.Time:
+		return sabre.Time{Time: v.Add(-total)}, nil
+
+	case sabre.Duration:
+		return v - sabre.Duration(total), nil
+
+	default:
+		return nil, fmt.Errorf("expecting time or duration, not '%s'", reflect.TypeOf(vals[0]))
+	}
+}
+
+// TimeComparator returns a function which compares 2 times using 'cmp'. It
+// is used for before?, after? and same?.
+func TimeComparator(cmp func(t1, t2 time.Time) bool) Fn {
+	return func(vals []sabre.Value) (sabre.Value, error) {
+		if err := verifyArgCount([]int{2}, vals); err != nil {
+			return nil, err
+		}
+
+		t1, err := toTime(vals[0])
+		if err != nil {
+			return nil, err
+		}
+
+		t2, err := toTime(vals[1])
+		if err != nil {
+			return nil, err
+		}
+
+		return sabre.Bool(cmp(t1, t2)), nil
+	}
+}
+
+// InZone returns the same instant in the given IANA time zone (e.g.,
+// "Asia/Kolkata", "UTC" or "Local").
+// Usage: (time/in-zone t "America/New_York")
+func InZone(vals []sabre.Value) (sabre.Value, error) {
+	if err := verifyArgCount([]int{2}, vals); err != nil {
+		return nil, err
+	}
+
+	t, err := toTime(vals[0])
+	if err != nil {
+		return nil, err
+	}
+
+	loc, err := toLocation(vals[1])
+	if err != nil {
+		return nil, err
+	}
+
+	return sabre.Time{Time: t.In(loc)}, nil
+}
+
+// TimeField returns a field of the time in its zone. Supported fields are
+// :year, :month, :day, :hour, :minute, :second, :nanosecond, :year-day (all
+// integers), :weekday (keyword such as :monday) and :zone (zone name).
+// Usage: (time/field t :hour)
+func TimeField(vals []sabre.Value) (sabre.Value, error) {
+	if err := verifyArgCount([]int{2}, vals); err != nil {
+		return nil, err
+	}
+
+	t, err := toTime(vals[0])
+	if err != nil {
+		return nil, err
+	}
+
+	switch field := toKeyword(vals[1]); field {
+	case "year":
+		return sabre.Int64(t.Year()), nil
+
+	case "month":
+		return sabre.Int64(t.Month()), nil
+
+	case "day":
+		return sabre.Int64(t.Day()), nil
+
+	case "hour":
+		return sabre.Int64(t.Hour()), nil
+
+	case "minute":
+		return sabre.Int64(t.Minute()), nil
+
+	case "second":
+		return sabre.Int64(t.Second()), nil
+
+	case "nanosecond":
+		return sabre.Int64(t.Nanosecond()), nil
+
+	case "year-day":
+		return sabre.Int64(t.YearDay()), nil
+
+	case "weekday":
+		return sabre.Keyword(strings.ToLower(t.Weekday().String())), nil
+
+	case "zone":
+		name, _ := t.Zone()
+		return sabre.String(name), nil
+
+	default:
+		return nil, fmt.Errorf("invalid time field '%s'", vals[1])
+	}
+}
+
+func sumDurations(vals []sabre.Value) (time.Duration, error) {
+	var total time.Duration
+	for _, v := range vals {
+		d, isDuration := v.(sabre.Duration)
+		if !isDuration {
+			return 0, fmt.Errorf("expecting duration, not '%s'", reflect.TypeOf(v))
+		}
+		total += time.Duration(d)
+	}
+
+	return total, nil
+}
+
+func toTime(v sabre.Value) (time.Time, error) {
+	t, isTime := v.(sabre.Time)
+	if !isTime {
+		return time.Time{}, fmt.Errorf("expecting time, not '%s'", reflect.TypeOf(v))
+	}
+
+	return t.Time, nil
+}
+
+func toLocation(v sabre.Value) (*time.Location, error) {
+	name, err := toString(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return time.LoadLocation(name)
+}
+
+func toKeyword(v sabre.Value) sabre.Keyword {
+	kw, _ := v.(sabre.Keyword)
+	return kw
+}
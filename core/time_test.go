@@ -0,0 +1,111 @@
+package core_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/spy16/sabre"
+	"github.com/spy16/sabre/core"
+)
+
+func TestTime(t *testing.T) {
+	t.Parallel()
+
+	clock := func() time.Time { return time.Date(2020, 1, 18, 10, 30, 0, 0, time.UTC) }
+
+	table := []struct {
+		name    string
+		src     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Now",
+			src:  `(time/now)`,
+			want: `#inst "2020-01-18T10:30:00Z"`,
+		},
+		{
+			name:    "NowWithArgs",
+			src:     `(time/now 1)`,
+			wantErr: true,
+		},
+		{
+			name: "ParseFormat",
+			src: `[(time/parse "2006-01-02 15:04" "2020-03-04 05:06")
+ (time/format (time/parse "2006-01-02 15:04" "2020-03-04 05:06" "Asia/Kolkata") time/RFC3339)]`,
+			want: `[#inst "2020-03-04T05:06:00Z" "2020-03-04T05:06:00+05:30"]`,
+		},
+		{
+			name:    "ParseInvalid",
+			src:     `(time/parse "2006-01-02" "04/03/2020")`,
+			wantErr: true,
+		},
+		{
+			name: "Duration",
+			src:  `[(time/duration "1h30m") (time/duration 90 :seconds) (time/duration 1.5 :days) (duration? #duration "1s")]`,
+			want: `[#duration "1h30m0s" #duration "1m30s" #duration "36h0m0s" true]`,
+		},
+		{
+			name:    "DurationInvalidUnit",
+			src:     `(time/duration 1 :weeks)`,
+			wantErr: true,
+		},
+		{
+			name: "Arithmetic",
+			src: `(def t (time/now))
+[(time/plus t #duration "1h" #duration "30m") (time/minus t #duration "24h")
+ (time/minus (time/plus t #duration "2h") t) (time/plus #duration "1h" #duration "1m")
+ (time/minus #duration "1h" #duration "1m")]`,
+			want: `[#inst "2020-01-18T12:00:00Z" #inst "2020-01-17T10:30:00Z" #duration "2h0m0s" #duration "1h1m0s" #duration "59m0s"]`,
+		},
+		{
+			name:    "PlusInvalid",
+			src:     `(time/plus (time/now) 10)`,
+			wantErr: true,
+		},
+		{
+			name: "Compare",
+			src: `(def t (time/now))
+[(time/before? t (time/plus t #duration "1s")) (time/after? t (time/plus t #duration "1s"))
+ (time/same? t (time/in-zone t "America/New_York"))]`,
+			want: `[true false true]`,
+		},
+		{
+			name: "InZone",
+			src: `(def ny (time/in-zone (time/now) "America/New_York"))
+[(time/field ny :hour) (time/field ny :zone) (time/field ny :weekday) (time/field ny :year-day)]`,
+			want: `[5 "EST" :saturday 18]`,
+		},
+		{
+			name:    "InZoneInvalid",
+			src:     `(time/in-zone (time/now) "Nowhere/Unknown")`,
+			wantErr: true,
+		},
+		{
+			name:    "FieldInvalid",
+			src:     `(time/field (time/now) :century)`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range table {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			scope := sabre.NewScope(nil)
+			if err := core.BindAll(scope); err != nil {
+				t.Fatalf("BindAll() unexpected error: %v", err)
+			}
+
+			ctx := core.WithClock(context.Background(), clock)
+			got, err := sabre.ReadEvalStr(sabre.WithContext(ctx, scope), tt.src)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && got.String() != tt.want {
+				t.Errorf("got = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
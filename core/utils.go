@@ -39,21 +39,20 @@ func toSymbolList(vals []sabre.Value) ([]sabre.Symbol, error) {
 }
 
 func stringFromVals(vals []sabre.Value) sabre.String {
-	argc := len(vals)
-	switch argc {
-	case 0:
-		return sabre.String("")
-
-	case 1:
-		return sabre.String(strings.Trim(vals[0].String(), "\""))
-
-	default:
-		var sb strings.Builder
-		for _, v := range vals {
-			sb.WriteString(strings.Trim(v.String(), "\""))
+	var sb strings.Builder
+	for _, v := range vals {
+		switch val := v.(type) {
+		case sabre.String:
+			sb.WriteString(string(val))
+
+		case sabre.Character:
+			sb.WriteRune(rune(val))
+
+		default:
+			sb.WriteString(v.String())
 		}
-		return sabre.String(sb.String())
 	}
+	return sabre.String(sb.String())
 }
 
 func isTruthy(v sabre.Value) bool {
@@ -94,3 +93,11 @@ func verifyArgCount(arities []int, args []sabre.Value) error {
 
 	return nil
 }
+
+func makeList(vals ...sabre.Value) *sabre.List {
+	return &sabre.List{Values: vals}
+}
+
+func symbol(name string) sabre.Symbol {
+	return sabre.Symbol{Value: name}
+}
@@ -43,8 +43,8 @@ func MakeString(vals []sabre.Value) (sabre.Value, error) {
 	return stringFromVals(vals), nil
 }
 
-// makeContainer can make a composite type like list, set and vector from
-// given args.
+// makeContainer can make a composite type like list, set, vector and map
+// from given args.
 func makeContainer(targetType sabre.Value) Fn {
 	return func(vals []sabre.Value) (sabre.Value, error) {
 		switch targetType.(type) {
@@ -56,6 +56,9 @@ func makeContainer(targetType sabre.Value) Fn {
 
 		case sabre.Set:
 			return sabre.Set{Values: vals}, nil
+
+		case *sabre.HashMap:
+			return sabre.NewHashMap(vals...)
 		}
 
 		return nil, fmt.Errorf("cannot make container of type '%s'", reflect.TypeOf(targetType))
@@ -81,3 +84,23 @@ func (t Type) String() string {
 func (t Type) Invoke(scope sabre.Scope, args ...sabre.Value) (sabre.Value, error) {
 	return sabre.ValueOf(reflect.New(t.rt).Interface()), nil
 }
+
+// Count returns the number of items in the collection or the number of
+// characters (not bytes) in the string. Returns 0 for nil.
+// Usage: (count coll)
+func Count(vals []sabre.Value) (sabre.Value, error) {
+	if err := verifyArgCount([]int{1}, vals); err != nil {
+		return nil, err
+	}
+
+	switch v := vals[0].(type) {
+	case sabre.Nil:
+		return sabre.Int64(0), nil
+
+	case interface{ Size() int }:
+		return sabre.Int64(v.Size()), nil
+
+	default:
+		return nil, fmt.Errorf("count not supported on '%s'", reflect.TypeOf(v))
+	}
+}
@@ -0,0 +1,105 @@
+package edn
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/spy16/sabre"
+)
+
+// ToGo converts the value into plain Go values. Values are converted as
+// follows:
+//
+//	nil                  ->  nil
+//	boolean              ->  bool
+//	integer              ->  int64 (*big.Int if N suffixed or out of range)
+//	float                ->  float64
+//	string               ->  string
+//	character            ->  rune
+//	keyword, symbol      ->  string (name without the ':' for keywords)
+//	list, vector, set    ->  []interface{}
+//	map                  ->  map[interface{}]interface{}
+//	#inst                ->  time.Time
+//	#uuid                ->  [16]byte
+//
+// Values of other tags are returned as is. Returns error if a map has keys
+// that are not comparable in Go (e.g., vectors).
+func ToGo(v sabre.Value) (interface{}, error) {
+	switch val := v.(type) {
+	case sabre.Nil:
+		return nil, nil
+
+	case sabre.Bool:
+		return bool(val), nil
+
+	case sabre.Int64:
+		return int64(val), nil
+
+	case sabre.BigInt:
+		return val.Int, nil
+
+	case sabre.Float64:
+		return float64(val), nil
+
+	case sabre.String:
+		return string(val), nil
+
+	case sabre.Character:
+		return rune(val), nil
+
+	case sabre.Keyword:
+		return string(val), nil
+
+	case sabre.Symbol:
+		return val.Value, nil
+
+	case *sabre.List:
+		return toGoSlice(val.Values)
+
+	case sabre.Vector:
+		return toGoSlice(val.Values)
+
+	case sabre.Set:
+		return toGoSlice(val.Values)
+
+	case *sabre.HashMap:
+		m := make(map[interface{}]interface{}, val.Size())
+		for _, key := range val.Keys() {
+			k, err := ToGo(key)
+			if err != nil {
+				return nil, err
+			}
+
+			if k != nil && !reflect.TypeOf(k).Comparable() {
+				return nil, fmt.Errorf("edn: map key %s cannot be used as Go map key", key)
+			}
+
+			item, _ := val.Get(key)
+			if m[k], err = ToGo(item); err != nil {
+				return nil, err
+			}
+		}
+		return m, nil
+
+	case sabre.Time:
+		return val.Time, nil
+
+	case sabre.UUID:
+		return [16]byte(val), nil
+
+	default:
+		return v, nil
+	}
+}
+
+func toGoSlice(vals []sabre.Value) ([]interface{}, error) {
+	res := make([]interface{}, len(vals))
+	for i, v := range vals {
+		item, err := ToGo(v)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = item
+	}
+	return res, nil
+}
@@ -0,0 +1,551 @@
+// Package edn implements reading and writing of data in the extensible data
+// notation (https://github.com/edn-format/edn). Unlike the sabre reader,
+// only the syntax defined by the EDN spec is accepted (e.g., no quoting,
+// metadata or anonymous functions) which makes it suitable for loading data
+// from untrusted sources. Nothing read is ever evaluated.
+package edn
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/spy16/sabre"
+)
+
+// Error is returned for malformed EDN data.
+type Error struct {
+	Line, Column int
+	Message      string
+}
+
+func (err Error) Error() string {
+	return fmt.Sprintf("edn: %d:%d: %s", err.Line, err.Column, err.Message)
+}
+
+// Parse parses the data which must contain exactly one EDN element.
+func Parse(data []byte) (sabre.Value, error) {
+	dec := NewDecoder(bytes.NewReader(data))
+
+	v, err := dec.Decode()
+	if err != nil {
+		if err == io.EOF {
+			return nil, dec.errorf("no element found")
+		}
+		return nil, err
+	}
+
+	if _, err := dec.Decode(); err != io.EOF {
+		if err != nil {
+			return nil, err
+		}
+		return nil, dec.errorf("unexpected element after the first one")
+	}
+
+	return v, nil
+}
+
+// Unmarshal parses the data which must contain exactly one EDN element and
+// returns it as plain Go values (See ToGo).
+func Unmarshal(data []byte) (interface{}, error) {
+	v, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return ToGo(v)
+}
+
+// NewDecoder returns a decoder that reads EDN elements from r. Only the
+// #inst and #uuid tags are supported by default. Handlers for other tags
+// can be added using SetTag.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{
+		rs:   bufio.NewReader(r),
+		line: 1,
+		tags: map[string]sabre.TagHandler{
+			"inst": instTag,
+			"uuid": uuidTag,
+		},
+	}
+}
+
+// Decoder reads EDN elements from an input stream.
+type Decoder struct {
+	rs        *bufio.Reader
+	line, col int
+	lastCol   int
+	tags      map[string]sabre.TagHandler
+}
+
+// SetTag sets the handler for the elements with the given tag. If the
+// handler is nil, handler for the tag is removed.
+func (dec *Decoder) SetTag(tag string, handler sabre.TagHandler) {
+	if handler == nil {
+		delete(dec.tags, tag)
+		return
+	}
+
+	dec.tags[tag] = handler
+}
+
+// Decode reads the next element from the stream. Returns io.EOF if there
+// are no more elements.
+func (dec *Decoder) Decode() (sabre.Value, error) {
+	v, err := dec.next()
+	if err == errDiscarded {
+		return dec.Decode()
+	}
+	return v, err
+}
+
+var errDiscarded = errors.New("discarded")
+
+// closeDelim is returned as error by next when a closing delimiter is
+// found.
+type closeDelim rune
+
+func (cd closeDelim) Error() string { return fmt.Sprintf("unmatched delimiter '%c'", rune(cd)) }
+
+// next reads the next element. Returns errDiscarded for #_ elements and
+// closeDelim for closing delimiters.
+func (dec *Decoder) next() (sabre.Value, error) {
+	if err := dec.skipSpaces(); err != nil {
+		return nil, err
+	}
+
+	r, err := dec.read()
+	if err != nil {
+		return nil, err
+	}
+
+	switch r {
+	case ')', ']', '}':
+		return nil, closeDelim(r)
+
+	case '(':
+		vals, err := dec.readElements(')')
+		return &sabre.List{Values: vals}, err
+
+	case '[':
+		vals, err := dec.readElements(']')
+		return sabre.Vector{Values: vals}, err
+
+	case '{':
+		return dec.readMap()
+
+	case '"':
+		return dec.readString()
+
+	case '\\':
+		return dec.readChar()
+
+	case '#':
+		return dec.readDispatch()
+
+	default:
+		dec.unread(r)
+		return dec.readAtom()
+	}
+}
+
+func (dec *Decoder) readDispatch() (sabre.Value, error) {
+	r, err := dec.read()
+	if err != nil {
+		return nil, dec.errorf("EOF while reading dispatch form")
+	}
+
+	switch {
+	case r == '{':
+		vals, err := dec.readElements('}')
+		if err != nil {
+			return nil, err
+		}
+
+		if dup, found := findDuplicate(vals); found {
+			return nil, dec.errorf("duplicate element in set: %s", dup)
+		}
+		return sabre.Set{Values: vals}, nil
+
+	case r == '_':
+		if _, err := dec.element("discarded element"); err != nil {
+			return nil, err
+		}
+		return nil, errDiscarded
+
+	case unicode.IsLetter(r):
+		dec.unread(r)
+		tag, err := dec.readToken()
+		if err != nil {
+			return nil, err
+		}
+
+		if !isSymbol(tag) {
+			return nil, dec.errorf("invalid tag '#%s'", tag)
+		}
+
+		handler, found := dec.tags[tag]
+		if !found {
+			return nil, dec.errorf("no handler for tag '#%s'", tag)
+		}
+
+		v, err := dec.element("tagged element")
+		if err != nil {
+			return nil, err
+		}
+
+		res, err := handler(v)
+		if err != nil {
+			return nil, dec.errorf("%v", err)
+		}
+		return res, nil
+
+	default:
+		return nil, dec.errorf("invalid dispatch form '#%c'", r)
+	}
+}
+
+// element reads the element following a prefix (e.g., tag or #_).
+func (dec *Decoder) element(what string) (sabre.Value, error) {
+	v, err := dec.Decode()
+	if err != nil {
+		if err == io.EOF {
+			return nil, dec.errorf("EOF while reading %s", what)
+		} else if _, ok := err.(closeDelim); ok {
+			return nil, dec.errorf("missing %s", what)
+		}
+		return nil, err
+	}
+
+	return v, nil
+}
+
+func (dec *Decoder) readElements(end rune) ([]sabre.Value, error) {
+	var vals []sabre.Value
+
+	for {
+		v, err := dec.next()
+		if err != nil {
+			if cd, ok := err.(closeDelim); ok && rune(cd) == end {
+				return vals, nil
+			} else if ok {
+				return nil, dec.errorf("%v", err)
+			} else if err == errDiscarded {
+				continue
+			} else if err == io.EOF {
+				return nil, dec.errorf("EOF while reading collection")
+			}
+			return nil, err
+		}
+
+		vals = append(vals, v)
+	}
+}
+
+func (dec *Decoder) readMap() (sabre.Value, error) {
+	vals, err := dec.readElements('}')
+	if err != nil {
+		return nil, err
+	}
+
+	hm, err := sabre.NewHashMap(vals...)
+	if err != nil {
+		return nil, dec.errorf("%v", err)
+	}
+
+	if hm.Size()*2 != len(vals) {
+		return nil, dec.errorf("duplicate key in map")
+	}
+
+	return hm, nil
+}
+
+func (dec *Decoder) readString() (sabre.Value, error) {
+	var sb strings.Builder
+
+	for {
+		r, err := dec.read()
+		if err != nil {
+			return nil, dec.errorf("EOF while reading string")
+		}
+
+		switch r {
+		case '"':
+			return sabre.String(sb.String()), nil
+
+		case '\\':
+			r2, err := dec.read()
+			if err != nil {
+				return nil, dec.errorf("EOF while reading string")
+			}
+
+			if r2 == 'u' {
+				r2, err = dec.readUnicode()
+				if err != nil {
+					return nil, err
+				}
+			} else if esc, found := stringEscapes[r2]; found {
+				r2 = esc
+			} else {
+				return nil, dec.errorf("invalid escape sequence '\\%c'", r2)
+			}
+			sb.WriteRune(r2)
+
+		default:
+			sb.WriteRune(r)
+		}
+	}
+}
+
+func (dec *Decoder) readUnicode() (rune, error) {
+	var digits []rune
+	for i := 0; i < 4; i++ {
+		r, err := dec.read()
+		if err != nil {
+			return -1, dec.errorf("EOF while reading unicode escape")
+		}
+		digits = append(digits, r)
+	}
+
+	code, err := strconv.ParseUint(string(digits), 16, 32)
+	if err != nil {
+		return -1, dec.errorf("invalid unicode escape '\\u%s'", string(digits))
+	}
+
+	return rune(code), nil
+}
+
+func (dec *Decoder) readChar() (sabre.Value, error) {
+	r, err := dec.read()
+	if err != nil || isSpace(r) {
+		return nil, dec.errorf("invalid character literal")
+	}
+
+	rest, err := dec.readToken()
+	if err != nil {
+		return nil, err
+	}
+
+	if rest == "" {
+		return sabre.Character(r), nil
+	}
+
+	token := string(r) + rest
+	if c, found := charNames[token]; found {
+		return sabre.Character(c), nil
+	}
+
+	if r == 'u' && len(rest) == 4 {
+		code, err := strconv.ParseUint(rest, 16, 32)
+		if err == nil {
+			return sabre.Character(rune(code)), nil
+		}
+	}
+
+	return nil, dec.errorf("invalid character literal '\\%s'", token)
+}
+
+func (dec *Decoder) readAtom() (sabre.Value, error) {
+	token, err := dec.readToken()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case "nil":
+		return sabre.Nil{}, nil
+
+	case "true":
+		return sabre.Bool(true), nil
+
+	case "false":
+		return sabre.Bool(false), nil
+	}
+
+	if isNumber(token) {
+		return dec.parseNumber(token)
+	}
+
+	if strings.HasPrefix(token, ":") {
+		name := token[1:]
+		if !isSymbol(name) || name == "/" || strings.HasPrefix(name, ":") {
+			return nil, dec.tokenErrorf(token, "invalid keyword")
+		}
+		return sabre.Keyword(name), nil
+	}
+
+	if !isSymbol(token) {
+		return nil, dec.tokenErrorf(token, "invalid symbol")
+	}
+
+	return sabre.Symbol{Value: token}, nil
+}
+
+func (dec *Decoder) parseNumber(token string) (sabre.Value, error) {
+	if !isFloat(token) {
+		s := strings.TrimSuffix(token, "N")
+		if !intPattern(s) {
+			return nil, dec.tokenErrorf(token, "invalid number")
+		}
+
+		i, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || strings.HasSuffix(token, "N") {
+			bi, _ := new(big.Int).SetString(s, 10)
+			return sabre.BigInt{Int: bi}, nil
+		}
+		return sabre.Int64(i), nil
+	}
+
+	if strings.HasSuffix(token, "M") {
+		// there is no exact decimal type to decode into and rounding to
+		// the nearest float would silently lose precision.
+		return nil, dec.tokenErrorf(token, "exact decimal numbers are not supported")
+	}
+
+	if !floatPattern(token) {
+		return nil, dec.tokenErrorf(token, "invalid number")
+	}
+
+	f, err := strconv.ParseFloat(token, 64)
+	if err != nil {
+		return nil, dec.tokenErrorf(token, "invalid number")
+	}
+	return sabre.Float64(f), nil
+}
+
+// findDuplicate returns the first value that is equal to an earlier one.
+// Values are compared the same way as the keys of a sabre.HashMap.
+func findDuplicate(vals []sabre.Value) (sabre.Value, bool) {
+	kvs := make([]sabre.Value, 0, 2*len(vals))
+	for _, v := range vals {
+		kvs = append(kvs, v, sabre.Nil{})
+	}
+
+	if hm, _ := sabre.NewHashMap(kvs...); hm.Size() == len(vals) {
+		return nil, false
+	}
+
+	for i := range vals {
+		if hm, _ := sabre.NewHashMap(kvs[:2*i+2]...); hm.Size() <= i {
+			return vals[i], true
+		}
+	}
+
+	return nil, false
+}
+
+// readToken reads until a delimiter or EOF.
+func (dec *Decoder) readToken() (string, error) {
+	var sb strings.Builder
+
+	for {
+		r, err := dec.read()
+		if err != nil {
+			if err == io.EOF {
+				break
+			}
+			return "", err
+		}
+
+		if isDelimiter(r) {
+			dec.unread(r)
+			break
+		}
+		sb.WriteRune(r)
+	}
+
+	return sb.String(), nil
+}
+
+func (dec *Decoder) skipSpaces() error {
+	for {
+		r, err := dec.read()
+		if err != nil {
+			return err
+		}
+
+		if r == ';' {
+			for r != '\n' {
+				if r, err = dec.read(); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		if !isSpace(r) {
+			dec.unread(r)
+			return nil
+		}
+	}
+}
+
+func (dec *Decoder) read() (rune, error) {
+	r, _, err := dec.rs.ReadRune()
+	if err != nil {
+		return -1, err
+	}
+
+	if r == '\n' {
+		dec.line++
+		dec.lastCol, dec.col = dec.col, 0
+	} else {
+		dec.col++
+	}
+
+	return r, nil
+}
+
+// unread returns the rune last read to the stream.
+func (dec *Decoder) unread(r rune) {
+	_ = dec.rs.UnreadRune()
+	if r == '\n' {
+		dec.line--
+		dec.col = dec.lastCol
+	} else {
+		dec.col--
+	}
+}
+
+func (dec *Decoder) errorf(format string, args ...interface{}) error {
+	return Error{
+		Line:    dec.line,
+		Column:  dec.col,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+// tokenErrorf returns an error positioned at the start of the token that
+// was just read.
+func (dec *Decoder) tokenErrorf(token, msg string) error {
+	return Error{
+		Line:    dec.line,
+		Column:  dec.col - utf8.RuneCountInString(token) + 1,
+		Message: fmt.Sprintf("%s '%s'", msg, token),
+	}
+}
+
+func instTag(form sabre.Value) (sabre.Value, error) {
+	s, isString := form.(sabre.String)
+	if !isString {
+		return nil, errors.New("#inst requires a string")
+	}
+
+	return sabre.ParseInst(string(s))
+}
+
+func uuidTag(form sabre.Value) (sabre.Value, error) {
+	s, isString := form.(sabre.String)
+	if !isString {
+		return nil, errors.New("#uuid requires a string")
+	}
+
+	return sabre.ParseUUID(string(s))
+}
@@ -0,0 +1,281 @@
+package edn_test
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spy16/sabre"
+	"github.com/spy16/sabre/edn"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{name: "Nil", src: "nil", want: "nil"},
+		{name: "Bool", src: " true ", want: "true"},
+		{name: "Integer", src: "-42", want: "-42"},
+		{name: "IntegerPlus", src: "+7", want: "7"},
+		{name: "BigIntSuffix", src: "42N", want: "42N"},
+		{name: "BigInt", src: "-9223372036854775809", want: "-9223372036854775809N"},
+		{name: "Float", src: "1.5e3", want: "1500.0"},
+		{name: "String", src: `"a\tb\"cé"`, want: `"a\tb\"cé"`},
+		{name: "Character", src: `[\a \newline \A]`, want: `[\a \newline \A]`},
+		{name: "Keyword", src: ":ns/name", want: ":ns/name"},
+		{name: "Symbol", src: "my.ns/foo-bar?", want: "my.ns/foo-bar?"},
+		{name: "Slash", src: "/", want: "/"},
+		{name: "List", src: "(1 ,2 ,, 3)", want: "(1 2 3)"},
+		{name: "Nested", src: "[{:a #{1}} ()]", want: "[{:a #{1}} ()]"},
+		{name: "Map", src: `{:a 1 "b" [2]}`, want: `{:a 1, "b" [2]}`},
+		{name: "Comment", src: "; comment\n[1 ; inner\n 2]", want: "[1 2]"},
+		{name: "Discard", src: "[1 #_ 2 #_ #_ 3 4 5]", want: "[1 5]"},
+		{name: "Inst", src: `#inst "2020-01-18T10:20:30Z"`, want: `#inst "2020-01-18T10:20:30Z"`},
+		{name: "UUID", src: `#uuid "F81D4FAE-7DEC-11D0-A765-00A0C91E6BF6"`, want: `#uuid "f81d4fae-7dec-11d0-a765-00a0c91e6bf6"`},
+	}
+
+	for _, tt := range table {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := edn.Parse([]byte(tt.src))
+			if err != nil {
+				t.Fatalf("Parse() unexpected error: %v", err)
+			}
+
+			got, err := edn.Marshal(v)
+			if err != nil {
+				t.Fatalf("Marshal() unexpected error: %v", err)
+			}
+
+			if string(got) != tt.want {
+				t.Errorf("Marshal() got = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		name string
+		src  string
+	}{
+		{name: "Empty", src: "  "},
+		{name: "MultipleElements", src: "1 2"},
+		{name: "Quote", src: "'a"},
+		{name: "SyntaxQuote", src: "`a"},
+		{name: "Unquote", src: "~a"},
+		{name: "Deref", src: "@a"},
+		{name: "Meta", src: "^:a []"},
+		{name: "AnonFn", src: "#(+ 1 %)"},
+		{name: "Regex", src: `#"a+"`},
+		{name: "ReaderConditional", src: "#?(:clj 1)"},
+		{name: "AutoNSKeyword", src: "::kw"},
+		{name: "HexNumber", src: "0xFF"},
+		{name: "RadixNumber", src: "2r101"},
+		{name: "LeadingZero", src: "0123"},
+		{name: "InvalidSymbol", src: "a/b/c"},
+		{name: "InvalidEscape", src: `"\a"`},
+		{name: "UnknownCharName", src: `\formfeed`},
+		{name: "UnterminatedString", src: `"abc`},
+		{name: "UnterminatedVector", src: "[1 2"},
+		{name: "UnmatchedDelimiter", src: "]"},
+		{name: "OddMap", src: "{:a}"},
+		{name: "DuplicateKey", src: "{:a 1 :a 2}"},
+		{name: "DuplicateElement", src: "#{1 1}"},
+		{name: "DuplicateCollection", src: "#{{:a 1 :b 2} {:b 2 :a 1}}"},
+		{name: "DecimalSuffix", src: "2.5M"},
+		{name: "UnknownTag", src: "#foo 1"},
+		{name: "InvalidInst", src: `#inst 10`},
+		{name: "InvalidUUID", src: `#uuid "not-a-uuid"`},
+	}
+
+	for _, tt := range table {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := edn.Parse([]byte(tt.src))
+			if err == nil {
+				t.Errorf("Parse() expected error, got %v", v)
+			}
+		})
+	}
+}
+
+func TestParse_ErrorPosition(t *testing.T) {
+	t.Parallel()
+
+	_, err := edn.Parse([]byte("[1\n  ~a]"))
+
+	var ednErr edn.Error
+	if !errors.As(err, &ednErr) {
+		t.Fatalf("Parse() expected edn.Error, got %v", err)
+	}
+
+	if ednErr.Line != 2 || ednErr.Column != 3 {
+		t.Errorf("Parse() error position = %d:%d, want 2:3", ednErr.Line, ednErr.Column)
+	}
+}
+
+func TestUnmarshal(t *testing.T) {
+	t.Parallel()
+
+	got, err := edn.Unmarshal([]byte(`{:name "sabre" :tags [foo \x] :port 8080 :ratio 0.5 :debug nil
+	  :at #inst "2020-01-18T00:00:00Z"}`))
+	if err != nil {
+		t.Fatalf("Unmarshal() unexpected error: %v", err)
+	}
+
+	want := map[interface{}]interface{}{
+		"name":  "sabre",
+		"tags":  []interface{}{"foo", 'x'},
+		"port":  int64(8080),
+		"ratio": 0.5,
+		"debug": nil,
+		"at":    time.Date(2020, 1, 18, 0, 0, 0, 0, time.UTC),
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Unmarshal() got = %#v, want %#v", got, want)
+	}
+
+	if _, err := edn.Unmarshal([]byte(`{[1] 2}`)); err == nil {
+		t.Errorf("Unmarshal() expected error for vector key")
+	}
+}
+
+func TestDecoder(t *testing.T) {
+	t.Parallel()
+
+	dec := edn.NewDecoder(strings.NewReader(`#point [1 2] #_ 3 :done`))
+	dec.SetTag("point", func(form sabre.Value) (sabre.Value, error) {
+		vec, ok := form.(sabre.Vector)
+		if !ok || len(vec.Values) != 2 {
+			return nil, errors.New("#point expects a vector of 2 numbers")
+		}
+		return &sabre.List{Values: vec.Values}, nil
+	})
+
+	var got []string
+	for {
+		v, err := dec.Decode()
+		if err != nil {
+			if err.Error() != "EOF" {
+				t.Fatalf("Decode() unexpected error: %v", err)
+			}
+			break
+		}
+		got = append(got, v.String())
+	}
+
+	want := []string{"(1 2)", ":done"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Decode() got = %v, want %v", got, want)
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		name    string
+		v       sabre.Value
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "String",
+			v:    sabre.String("a\x01\f\"b"),
+			want: `"a\u0001\f\"b"`,
+		},
+		{
+			name: "Character",
+			v:    sabre.Vector{Values: []sabre.Value{sabre.Character('\f'), sabre.Character(' ')}},
+			want: `[\u000c \space]`,
+		},
+		{
+			name: "Float",
+			v:    sabre.Float64(3),
+			want: "3.0",
+		},
+		{
+			name: "Map",
+			v:    mustHashMap(sabre.Keyword("a"), &sabre.List{}, sabre.String("b"), sabre.Set{}),
+			want: `{:a (), "b" #{}}`,
+		},
+		{
+			name:    "NaN",
+			v:       sabre.Float64(zero() / zero()),
+			wantErr: true,
+		},
+		{
+			name:    "InvalidKeyword",
+			v:       sabre.Keyword("a b"),
+			wantErr: true,
+		},
+		{
+			name:    "Function",
+			v:       sabre.MultiFn{},
+			wantErr: true,
+		},
+		{
+			name:    "NestedFunction",
+			v:       sabre.Vector{Values: []sabre.Value{sabre.Int64(1), sabre.MultiFn{}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range table {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := edn.Marshal(tt.v)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Marshal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if string(got) != tt.want {
+				t.Errorf("Marshal() got = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEncoder_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	src := `[nil true -1 2.5 "s\n" \newline :k/w sym (1) #{"x"} {:a {:b [#uuid "f81d4fae-7dec-11d0-a765-00a0c91e6bf6"]}}]`
+	v, err := edn.Parse([]byte(src))
+	if err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := edn.NewEncoder(&buf).Encode(v); err != nil {
+		t.Fatalf("Encode() unexpected error: %v", err)
+	}
+
+	got, err := edn.Parse(buf.Bytes())
+	if err != nil {
+		t.Fatalf("Parse() failed to read encoded value %q: %v", buf.String(), err)
+	}
+
+	if !reflect.DeepEqual(got, v) {
+		t.Errorf("round trip got = %v, want %v", got, v)
+	}
+}
+
+func zero() float64 { return 0 }
+
+func mustHashMap(kvs ...sabre.Value) *sabre.HashMap {
+	hm, err := sabre.NewHashMap(kvs...)
+	if err != nil {
+		panic(err)
+	}
+	return hm
+}
@@ -0,0 +1,201 @@
+package edn
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strconv"
+	"unicode"
+
+	"github.com/spy16/sabre"
+)
+
+// Marshal returns the EDN representation of the value. Values that have no
+// EDN representation (e.g., functions, atoms) result in an error.
+func Marshal(v sabre.Value) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := write(&buf, v); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// NewEncoder returns an encoder that writes EDN elements to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w}
+}
+
+// Encoder writes EDN elements to an output stream.
+type Encoder struct {
+	w io.Writer
+}
+
+// Encode writes the EDN representation of the value followed by a newline.
+func (enc *Encoder) Encode(v sabre.Value) error {
+	data, err := Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	_, err = enc.w.Write(append(data, '\n'))
+	return err
+}
+
+func write(buf *bytes.Buffer, v sabre.Value) error {
+	switch val := v.(type) {
+	case sabre.Nil, sabre.Bool, sabre.Int64, sabre.BigInt:
+		buf.WriteString(val.String())
+
+	case sabre.Float64:
+		if math.IsInf(float64(val), 0) || math.IsNaN(float64(val)) {
+			return fmt.Errorf("edn: unsupported float value: %v", float64(val))
+		}
+		buf.WriteString(val.String())
+
+	case sabre.String:
+		writeString(buf, string(val))
+
+	case sabre.Character:
+		return writeChar(buf, rune(val))
+
+	case sabre.Keyword:
+		if !isSymbol(string(val)) || val == "/" {
+			return fmt.Errorf("edn: invalid keyword %q", string(val))
+		}
+		buf.WriteString(val.String())
+
+	case sabre.Symbol:
+		if !isSymbol(val.Value) {
+			return fmt.Errorf("edn: invalid symbol %q", val.Value)
+		}
+		buf.WriteString(val.Value)
+
+	case *sabre.List:
+		return writeElements(buf, "(", ")", val.Values)
+
+	case sabre.Vector:
+		return writeElements(buf, "[", "]", val.Values)
+
+	case sabre.Set:
+		return writeElements(buf, "#{", "}", val.Values)
+
+	case *sabre.HashMap:
+		return writeMap(buf, val)
+
+	case sabre.Tagged:
+		tag, form := val.Tag()
+		if !isSymbol(tag) || !unicode.IsLetter([]rune(tag)[0]) {
+			return fmt.Errorf("edn: invalid tag %q", tag)
+		}
+
+		buf.WriteString("#" + tag + " ")
+		return write(buf, form)
+
+	default:
+		return fmt.Errorf("edn: cannot encode value of type '%s'", reflect.TypeOf(v))
+	}
+
+	return nil
+}
+
+func writeElements(buf *bytes.Buffer, open, close string, vals []sabre.Value) error {
+	buf.WriteString(open)
+	for i, v := range vals {
+		if i > 0 {
+			buf.WriteString(" ")
+		}
+
+		if err := write(buf, v); err != nil {
+			return err
+		}
+	}
+	buf.WriteString(close)
+	return nil
+}
+
+func writeMap(buf *bytes.Buffer, hm *sabre.HashMap) error {
+	buf.WriteString("{")
+	for i, key := range hm.Keys() {
+		if i > 0 {
+			buf.WriteString(", ")
+		}
+
+		if err := write(buf, key); err != nil {
+			return err
+		}
+		buf.WriteString(" ")
+
+		val, _ := hm.Get(key)
+		if err := write(buf, val); err != nil {
+			return err
+		}
+	}
+	buf.WriteString("}")
+	return nil
+}
+
+func writeString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"', '\\':
+			buf.WriteByte('\\')
+			buf.WriteRune(r)
+
+		case '\n':
+			buf.WriteString(`\n`)
+
+		case '\t':
+			buf.WriteString(`\t`)
+
+		case '\r':
+			buf.WriteString(`\r`)
+
+		case '\b':
+			buf.WriteString(`\b`)
+
+		case '\f':
+			buf.WriteString(`\f`)
+
+		default:
+			if r < ' ' || r == 0x7f {
+				fmt.Fprintf(buf, `\u%04x`, r)
+			} else {
+				buf.WriteRune(r)
+			}
+		}
+	}
+	buf.WriteByte('"')
+}
+
+func writeChar(buf *bytes.Buffer, r rune) error {
+	for name, c := range charNames {
+		if c == r {
+			buf.WriteString(`\` + name)
+			return nil
+		}
+	}
+
+	switch {
+	case unicode.IsPrint(r):
+		buf.WriteString(`\` + string(r))
+
+	case r <= 0xffff:
+		buf.WriteString(`\u` + leftPad(strconv.FormatInt(int64(r), 16), 4))
+
+	default:
+		return fmt.Errorf("edn: cannot encode character %U", r)
+	}
+
+	return nil
+}
+
+func leftPad(s string, n int) string {
+	for len(s) < n {
+		s = "0" + s
+	}
+	return s
+}
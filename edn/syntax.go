@@ -0,0 +1,100 @@
+package edn
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+var (
+	intPattern   = regexp.MustCompile(`^[+-]?(0|[1-9][0-9]*)$`).MatchString
+	floatPattern = regexp.MustCompile(`^[+-]?(0|[1-9][0-9]*)(\.[0-9]*)?([eE][+-]?[0-9]+)?$`).MatchString
+
+	stringEscapes = map[rune]rune{
+		't':  '\t',
+		'r':  '\r',
+		'n':  '\n',
+		'b':  '\b',
+		'f':  '\f',
+		'\\': '\\',
+		'"':  '"',
+	}
+
+	charNames = map[string]rune{
+		"newline": '\n',
+		"return":  '\r',
+		"space":   ' ',
+		"tab":     '\t',
+	}
+)
+
+func isSpace(r rune) bool {
+	return unicode.IsSpace(r) || r == ','
+}
+
+func isDelimiter(r rune) bool {
+	return isSpace(r) || strings.ContainsRune(`()[]{}";`, r)
+}
+
+// isNumber returns true if the token must be read as a number (i.e., it
+// starts with a digit or a sign followed by a digit).
+func isNumber(token string) bool {
+	if token == "" {
+		return false
+	}
+
+	if token[0] == '+' || token[0] == '-' {
+		token = token[1:]
+	}
+
+	return token != "" && token[0] >= '0' && token[0] <= '9'
+}
+
+func isFloat(token string) bool {
+	return strings.ContainsAny(token, ".eE") || strings.HasSuffix(token, "M")
+}
+
+// isSymbol returns true if the string is a valid symbol (and also keyword
+// after the ':').
+func isSymbol(s string) bool {
+	if s == "/" {
+		return true
+	}
+
+	parts := strings.Split(s, "/")
+	if len(parts) > 2 {
+		return false
+	}
+
+	for _, part := range parts {
+		if !isSymbolPart(part) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isSymbolPart(s string) bool {
+	runes := []rune(s)
+	if len(runes) == 0 {
+		return false
+	}
+
+	first := runes[0]
+	if unicode.IsDigit(first) || first == ':' || first == '#' {
+		return false
+	}
+
+	if (first == '-' || first == '+' || first == '.') && len(runes) > 1 && unicode.IsDigit(runes[1]) {
+		return false
+	}
+
+	for _, r := range runes {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && !strings.ContainsRune(".*+!-_?$%&=<>:#", r) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,408 @@
+package format
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Kind represents the type of a node in the syntax tree.
+type Kind int
+
+// Kinds of nodes in the syntax tree.
+const (
+	// Root is the top-level node containing all the nodes of the source.
+	Root Kind = iota
+
+	// Whitespace is a run of space characters including newlines.
+	Whitespace
+
+	// Comma is a single ',' which is treated as whitespace by the reader.
+	Comma
+
+	// Comment is a line comment starting with ';' (excluding the newline).
+	Comment
+
+	// Atom is a symbol, keyword, number, character, nil or boolean.
+	Atom
+
+	// String is a string literal or a dispatch form with string syntax
+	// (e.g., #"regex").
+	String
+
+	// List is a form enclosed in '(' and ')'.
+	List
+
+	// Vector is a form enclosed in '[' and ']'.
+	Vector
+
+	// Set is a form enclosed in '#{' and '}'.
+	Set
+
+	// Map is a form enclosed in '{' and '}'.
+	Map
+
+	// AnonFn is a form enclosed in '#(' and ')'.
+	AnonFn
+
+	// Prefix is a reader macro which applies to the form following it (e.g.,
+	// quote, deref, tagged literals, discard etc.).
+	Prefix
+)
+
+var closers = map[Kind]string{
+	List:   ")",
+	Vector: "]",
+	Set:    "}",
+	Map:    "}",
+	AnonFn: ")",
+}
+
+// Node represents a node in the concrete syntax tree. The tree retains all
+// the characters of the source including whitespace, commas and comments.
+type Node struct {
+	Kind Kind
+
+	// Text is the source text for leaf nodes, the opening delimiter for
+	// containers (e.g., "(", "#{") and the prefix for Prefix nodes (e.g.,
+	// "'", "#_", "#inst").
+	Text string
+
+	// Children are the nodes within a container (or Root) in source order.
+	// For Prefix nodes, children contain any whitespace or comments followed
+	// by the form the prefix applies to.
+	Children []*Node
+
+	// Line and Column are the 1-based position of the node in the source.
+	Line, Column int
+}
+
+// Close returns the closing delimiter of the node if it is a container.
+func (n *Node) Close() string {
+	return closers[n.Kind]
+}
+
+// IsTrivia returns true if the node has no meaning to the reader (i.e.,
+// whitespace, comma or comment).
+func (n *Node) IsTrivia() bool {
+	return n.Kind == Whitespace || n.Kind == Comma || n.Kind == Comment
+}
+
+// String returns the source text of the node. For the tree returned by
+// Parse, String() of the root returns the exact source.
+func (n *Node) String() string {
+	var sb strings.Builder
+	n.write(&sb)
+	return sb.String()
+}
+
+func (n *Node) write(sb *strings.Builder) {
+	sb.WriteString(n.Text)
+	for _, child := range n.Children {
+		child.write(sb)
+	}
+	sb.WriteString(n.Close())
+}
+
+// Parse parses the source into a concrete syntax tree. Unlike the reader,
+// the tree retains comments, whitespace and commas, and no values are
+// constructed which makes it suitable for tools like formatters that need
+// to reproduce the source.
+func Parse(src []byte) (*Node, error) {
+	p := &parser{src: string(src), line: 1, col: 1}
+
+	root := &Node{Kind: Root, Line: 1, Column: 1}
+	for !p.eof() {
+		if p.peek() == ')' || p.peek() == ']' || p.peek() == '}' {
+			return nil, p.errorf("unmatched delimiter '%c'", p.peek())
+		}
+
+		node, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		root.Children = append(root.Children, node)
+	}
+
+	return root, nil
+}
+
+// Error is returned by Parse for malformed source.
+type Error struct {
+	Line, Column int
+	Message      string
+}
+
+func (err Error) Error() string {
+	return fmt.Sprintf("%d:%d: %s", err.Line, err.Column, err.Message)
+}
+
+type parser struct {
+	src       string
+	pos       int
+	line, col int
+}
+
+// next parses the node at the current position.
+func (p *parser) next() (*Node, error) {
+	r := p.peek()
+	node := &Node{Line: p.line, Column: p.col}
+
+	switch {
+	case isSpace(r):
+		node.Kind = Whitespace
+		node.Text = p.consumeWhile(isSpace)
+
+	case r == ',':
+		node.Kind = Comma
+		node.Text = p.consume(1)
+
+	case r == ';':
+		node.Kind = Comment
+		node.Text = p.consumeWhile(func(r rune) bool { return r != '\n' })
+
+	case r == '"':
+		return p.str(node, "")
+
+	case r == '\\':
+		node.Kind = Atom
+		node.Text = p.consume(1)
+		if p.eof() {
+			return nil, p.errorf("EOF while reading character")
+		}
+		node.Text += p.consume(1) + p.consumeWhile(isTokenRune)
+
+	case r == '(':
+		return p.container(node, List, p.consume(1))
+
+	case r == '[':
+		return p.container(node, Vector, p.consume(1))
+
+	case r == '{':
+		return p.container(node, Map, p.consume(1))
+
+	case r == ')' || r == ']' || r == '}':
+		return nil, p.errorf("unmatched delimiter '%c'", r)
+
+	case r == '\'' || r == '`' || r == '~' || r == '@' || r == '^':
+		return p.prefix(node, p.consume(1))
+
+	case r == '#':
+		return p.dispatch(node)
+
+	default:
+		node.Kind = Atom
+		node.Text = p.consumeWhile(isTokenRune)
+	}
+
+	return node, nil
+}
+
+func (p *parser) dispatch(node *Node) (*Node, error) {
+	p.consume(1)
+	if p.eof() {
+		return nil, p.errorf("EOF while reading dispatch form")
+	}
+
+	switch r := p.peek(); {
+	case r == '{':
+		return p.container(node, Set, "#"+p.consume(1))
+
+	case r == '(':
+		return p.container(node, AnonFn, "#"+p.consume(1))
+
+	case r == '"':
+		return p.str(node, "#")
+
+	case r == '_' || r == '\'':
+		return p.prefix(node, "#"+p.consume(1))
+
+	case r == '?':
+		prefix := "#" + p.consume(1)
+		if !p.eof() && p.peek() == '@' {
+			prefix += p.consume(1)
+		}
+		return p.prefix(node, prefix)
+
+	case isTokenRune(r):
+		tag := "#" + p.consumeWhile(isTokenRune)
+		if !p.eof() && p.peek() == '"' {
+			// e.g., #r"raw string", #f"interpolated {name}"
+			return p.str(node, tag)
+		}
+		return p.prefix(node, tag)
+
+	default:
+		return nil, p.errorf("unknown dispatch form '#%c'", r)
+	}
+}
+
+func (p *parser) container(node *Node, kind Kind, open string) (*Node, error) {
+	node.Kind = kind
+	node.Text = open
+
+	closer := closers[kind]
+	for {
+		if p.eof() {
+			return nil, Error{
+				Line:    node.Line,
+				Column:  node.Column,
+				Message: fmt.Sprintf("EOF while reading '%s'", open),
+			}
+		}
+
+		switch r := p.peek(); {
+		case string(r) == closer:
+			p.consume(1)
+			return node, nil
+
+		case r == ')' || r == ']' || r == '}':
+			return nil, p.errorf("unmatched delimiter '%c'", r)
+		}
+
+		child, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		node.Children = append(node.Children, child)
+	}
+}
+
+func (p *parser) prefix(node *Node, prefix string) (*Node, error) {
+	node.Kind = Prefix
+	node.Text = prefix
+
+	for {
+		if p.eof() {
+			return nil, p.errorf("EOF while reading form after '%s'", prefix)
+		}
+
+		if r := p.peek(); r == ')' || r == ']' || r == '}' {
+			return nil, p.errorf("missing form after '%s'", prefix)
+		}
+
+		child, err := p.next()
+		if err != nil {
+			return nil, err
+		}
+		node.Children = append(node.Children, child)
+
+		if !child.IsTrivia() {
+			return node, nil
+		}
+	}
+}
+
+func (p *parser) str(node *Node, prefix string) (*Node, error) {
+	node.Kind = String
+
+	start := p.pos
+	p.consume(1)
+	for {
+		if p.eof() {
+			return nil, Error{
+				Line:    node.Line,
+				Column:  node.Column,
+				Message: "EOF while reading string",
+			}
+		}
+
+		switch p.consume(1) {
+		case "\\":
+			if prefix != "#r" && !p.eof() {
+				p.consume(1)
+			}
+
+		case "{":
+			if prefix == "#f" {
+				if err := p.interpolation(); err != nil {
+					return nil, err
+				}
+			}
+
+		case "\"":
+			node.Text = prefix + p.src[start:p.pos]
+			return node, nil
+		}
+	}
+}
+
+// interpolation consumes the {form} in #f"..." strings so that strings or
+// braces in the form do not end the string. "{{" is a literal brace.
+func (p *parser) interpolation() error {
+	if !p.eof() && p.peek() == '{' {
+		p.consume(1)
+		return nil
+	}
+
+	for {
+		if p.eof() {
+			return p.errorf("EOF while reading interpolated form")
+		}
+
+		if p.peek() == '}' {
+			p.consume(1)
+			return nil
+		}
+
+		if _, err := p.next(); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *parser) consumeWhile(pred func(r rune) bool) string {
+	start := p.pos
+	for !p.eof() && pred(p.peek()) {
+		p.consume(1)
+	}
+	return p.src[start:p.pos]
+}
+
+// consume advances by 'n' runes and returns the consumed text.
+func (p *parser) consume(n int) string {
+	start := p.pos
+	for i := 0; i < n && !p.eof(); i++ {
+		r, size := utf8.DecodeRuneInString(p.src[p.pos:])
+		p.pos += size
+		if r == '\n' {
+			p.line++
+			p.col = 1
+		} else {
+			p.col++
+		}
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *parser) peek() rune {
+	r, _ := utf8.DecodeRuneInString(p.src[p.pos:])
+	return r
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.src)
+}
+
+func (p *parser) errorf(format string, args ...interface{}) error {
+	return Error{
+		Line:    p.line,
+		Column:  p.col,
+		Message: fmt.Sprintf(format, args...),
+	}
+}
+
+func isSpace(r rune) bool {
+	return unicode.IsSpace(r)
+}
+
+// isTokenRune returns true if the rune can be part of a symbol, keyword or
+// a number (i.e., anything except whitespace and delimiters).
+func isTokenRune(r rune) bool {
+	if isSpace(r) {
+		return false
+	}
+
+	return !strings.ContainsRune(`,;"()[]{}`, r)
+}
@@ -0,0 +1,163 @@
+package format_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/spy16/sabre/format"
+)
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		name      string
+		src       string
+		wantKinds []format.Kind
+		wantErr   bool
+	}{
+		{
+			name:      "Empty",
+			src:       "",
+			wantKinds: nil,
+		},
+		{
+			name: "Atoms",
+			src:  "hello :key 1.5e3 \\newline \\( nil",
+			wantKinds: []format.Kind{
+				format.Atom, format.Whitespace, format.Atom, format.Whitespace,
+				format.Atom, format.Whitespace, format.Atom, format.Whitespace,
+				format.Atom, format.Whitespace, format.Atom,
+			},
+		},
+		{
+			name: "TriviaAndStrings",
+			src:  "\"a \\\" ; b\",; comment\n#\"regex\"",
+			wantKinds: []format.Kind{
+				format.String, format.Comma, format.Comment, format.Whitespace,
+				format.String,
+			},
+		},
+		{
+			name: "RawAndInterpolatedStrings",
+			src:  `#r"C:\dir\" #f"a {(str "}")} {{b}}"`,
+			wantKinds: []format.Kind{
+				format.String, format.Whitespace, format.String,
+			},
+		},
+		{
+			name: "Containers",
+			src:  "(a [b] #{c} {:d e} #(f %))",
+			wantKinds: []format.Kind{
+				format.List,
+			},
+		},
+		{
+			name: "Prefixes",
+			src:  "'a `(b ~c ~@d) @e #_ f #inst \"2020\" #?(:go 1) ^:meta g",
+			wantKinds: []format.Kind{
+				format.Prefix, format.Whitespace, format.Prefix, format.Whitespace,
+				format.Prefix, format.Whitespace, format.Prefix, format.Whitespace,
+				format.Prefix, format.Whitespace, format.Prefix, format.Whitespace,
+				format.Prefix, format.Whitespace, format.Atom,
+			},
+		},
+		{
+			name:    "UnclosedList",
+			src:     "(a [b]",
+			wantErr: true,
+		},
+		{
+			name:    "MismatchedDelimiter",
+			src:     "(a]",
+			wantErr: true,
+		},
+		{
+			name:    "UnmatchedDelimiter",
+			src:     "a)",
+			wantErr: true,
+		},
+		{
+			name:    "UnclosedString",
+			src:     `"hello`,
+			wantErr: true,
+		},
+		{
+			name:    "PrefixWithoutForm",
+			src:     "(a ')",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range table {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			root, err := format.Parse([]byte(tt.src))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+
+			var kinds []format.Kind
+			for _, child := range root.Children {
+				kinds = append(kinds, child.Kind)
+			}
+
+			if !reflect.DeepEqual(kinds, tt.wantKinds) {
+				t.Errorf("Parse() kinds = %v, want %v", kinds, tt.wantKinds)
+			}
+
+			if got := root.String(); got != tt.src {
+				t.Errorf("String() = %q, want %q", got, tt.src)
+			}
+		})
+	}
+}
+
+func TestParse_Lossless(t *testing.T) {
+	t.Parallel()
+
+	files, err := filepath.Glob("../examples/*.lisp")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no example files found: %v", err)
+	}
+
+	for _, file := range files {
+		src, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatalf("ReadFile() unexpected error: %v", err)
+		}
+
+		root, err := format.Parse(src)
+		if err != nil {
+			t.Fatalf("Parse(%s) unexpected error: %v", file, err)
+		}
+
+		if got := root.String(); got != string(src) {
+			t.Errorf("Parse(%s) is not lossless", file)
+		}
+	}
+}
+
+func TestParse_Position(t *testing.T) {
+	t.Parallel()
+
+	root, err := format.Parse([]byte("(a\n  [π b])"))
+	if err != nil {
+		t.Fatalf("Parse() unexpected error: %v", err)
+	}
+
+	vec := root.Children[0].Children[2]
+	if vec.Kind != format.Vector || vec.Line != 2 || vec.Column != 3 {
+		t.Errorf("Parse() vector at %d:%d, want 2:3", vec.Line, vec.Column)
+	}
+
+	b := vec.Children[2]
+	if b.Text != "b" || b.Column != 6 {
+		t.Errorf("Parse() symbol '%s' at column %d, want 'b' at 6", b.Text, b.Column)
+	}
+}
@@ -0,0 +1,283 @@
+// Package format implements a lossless concrete syntax tree for sabre source
+// and a formatter using a standard indentation style.
+package format
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// blockForms are the forms whose body is indented by 2 spaces instead of
+// being aligned with the first argument. Forms starting with "def" or
+// "with-" are also treated as block forms.
+var blockForms = map[string]bool{
+	"fn*":         true,
+	"λ":           true,
+	"fn":          true,
+	"let*":        true,
+	"let":         true,
+	"do":          true,
+	"if":          true,
+	"if-not":      true,
+	"if-let":      true,
+	"when":        true,
+	"when-not":    true,
+	"when-let":    true,
+	"go":          true,
+	"future":      true,
+	"delay":       true,
+	"loop":        true,
+	"binding":     true,
+	"cond":        true,
+	"case":        true,
+	"try":         true,
+	"catch":       true,
+	"finally":     true,
+	"doseq":       true,
+	"dotimes":     true,
+	"for":         true,
+	"ns":          true,
+	"extend-type": true,
+}
+
+// Source formats the source and returns the result. Comments are retained
+// and line breaks between forms are kept as written while indentation and
+// spacing within lines are normalized.
+func Source(src []byte) ([]byte, error) {
+	root, err := Parse(src)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := Fprint(&buf, root); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Fprint writes the formatted source of the syntax tree to 'w'.
+func Fprint(w io.Writer, root *Node) error {
+	p := &printer{}
+
+	if root.Kind == Root {
+		p.printElements(root, nil)
+		if p.buf.Len() > 0 {
+			p.buf.WriteString("\n")
+		}
+	} else {
+		p.print(root)
+	}
+
+	_, err := w.Write(p.buf.Bytes())
+	return err
+}
+
+type printer struct {
+	buf    bytes.Buffer
+	lineNo int
+	col    int
+}
+
+type position struct {
+	line, col int
+}
+
+func (p *printer) print(n *Node) {
+	switch n.Kind {
+	case List, Vector, Set, Map, AnonFn:
+		openCol := p.col
+		p.write(n.Text)
+		p.printElements(n, func(idx int, elems []position) int {
+			return indentOf(n, openCol, idx, elems)
+		})
+		p.write(n.Close())
+
+	case Prefix:
+		p.printPrefix(n)
+
+	case Comment:
+		p.write(strings.TrimRightFunc(n.Text, isSpace))
+
+	default:
+		p.write(n.Text)
+	}
+}
+
+// printElements prints the children of the container (or the root). Line
+// breaks between elements are retained (at most one blank line) and the
+// elements starting a new line are indented using the indent function. In
+// case of root, indent is nil.
+func (p *printer) printElements(n *Node, indent func(idx int, elems []position) int) {
+	var elems []position // positions at which the elements are printed.
+	newlines, spaces := 0, ""
+	afterComment := false
+
+	for _, child := range n.Children {
+		switch child.Kind {
+		case Whitespace:
+			newlines += strings.Count(child.Text, "\n")
+			spaces = child.Text
+			continue
+
+		case Comma:
+			p.write(",")
+			spaces = ""
+			continue
+		}
+
+		switch {
+		case len(elems) == 0 && !afterComment:
+			// no space between the opening delimiter and the first element
+			// and no blank lines at the beginning of the source.
+
+		case newlines > 0 || afterComment:
+			col := 0
+			if indent != nil {
+				col = indent(len(elems), elems)
+			}
+			p.newline(newlines, col)
+
+		case child.Kind == Comment && spaces != "":
+			// retain the alignment of comments at the end of lines.
+			p.write(spaces)
+
+		default:
+			p.write(" ")
+		}
+
+		elems = append(elems, position{line: p.lineNo, col: p.col})
+		p.print(child)
+
+		afterComment = child.Kind == Comment
+		newlines, spaces = 0, ""
+	}
+
+	if afterComment && indent != nil {
+		p.newline(1, indent(len(elems), elems))
+	}
+}
+
+func (p *printer) printPrefix(n *Node) {
+	startCol := p.col
+	p.write(n.Text)
+
+	hadSpace, afterComment := false, false
+	for _, child := range n.Children {
+		switch child.Kind {
+		case Whitespace, Comma:
+			hadSpace = true
+			continue
+
+		case Comment:
+			p.write(" ")
+			p.print(child)
+			afterComment = true
+			continue
+		}
+
+		if afterComment {
+			p.newline(1, startCol+utf8.RuneCountInString(n.Text))
+		} else if hadSpace && isTag(n.Text) {
+			p.write(" ")
+		}
+		p.print(child)
+	}
+}
+
+// newline writes the line break (followed by a blank line if count > 1)
+// and indents the next line to 'col'.
+func (p *printer) newline(count, col int) {
+	p.buf.WriteString("\n")
+	if count > 1 {
+		p.buf.WriteString("\n")
+		p.lineNo++
+	}
+	p.lineNo++
+	p.buf.WriteString(strings.Repeat(" ", col))
+	p.col = col
+}
+
+func (p *printer) write(s string) {
+	p.buf.WriteString(s)
+
+	if idx := strings.LastIndexByte(s, '\n'); idx >= 0 {
+		p.lineNo += strings.Count(s, "\n")
+		p.col = utf8.RuneCountInString(s[idx+1:])
+	} else {
+		p.col += utf8.RuneCountInString(s)
+	}
+}
+
+// indentOf returns the column at which the element at 'idx' of the
+// container starting at 'openCol' must be printed when it starts on a new
+// line. 'elems' are the positions of the elements printed so far.
+func indentOf(n *Node, openCol, idx int, elems []position) int {
+	// column of the first element.
+	base := openCol + utf8.RuneCountInString(n.Text)
+
+	if n.Kind != List && n.Kind != AnonFn {
+		return base
+	}
+
+	head := firstElement(n)
+	if head == nil || head.Kind != Atom || isLiteral(head.Text) {
+		// data lists or lists with non-symbol heads are aligned with the
+		// first element.
+		return base
+	}
+
+	if isBlockForm(head.Text) {
+		return base + 1
+	}
+
+	if idx > 1 && elems[1].line == elems[0].line {
+		// first argument was on the same line as the head.
+		return elems[1].col
+	}
+
+	return base
+}
+
+func firstElement(n *Node) *Node {
+	for _, child := range n.Children {
+		if !child.IsTrivia() {
+			return child
+		}
+	}
+	return nil
+}
+
+func isBlockForm(sym string) bool {
+	if idx := strings.LastIndexByte(sym, '/'); idx >= 0 && idx < len(sym)-1 {
+		sym = sym[idx+1:]
+	}
+
+	return blockForms[sym] ||
+		strings.HasPrefix(sym, "def") ||
+		strings.HasPrefix(sym, "with-")
+}
+
+// isLiteral returns true if the atom is not a symbol.
+func isLiteral(text string) bool {
+	r, _ := utf8.DecodeRuneInString(text)
+	if r == ':' || r == '\\' || (r >= '0' && r <= '9') {
+		return true
+	}
+
+	if (r == '-' || r == '+') && len(text) > 1 && text[1] >= '0' && text[1] <= '9' {
+		return true
+	}
+
+	return text == "nil" || text == "true" || text == "false"
+}
+
+// isTag returns true if the prefix is a tag (e.g., #inst) which must be
+// separated from the form by a space.
+func isTag(prefix string) bool {
+	return len(prefix) > 1 && prefix[0] == '#' && isTokenRune(rune(prefix[1])) &&
+		prefix[1] != '_' && prefix[1] != '?' && prefix[1] != '\''
+}
@@ -0,0 +1,155 @@
+package format_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/spy16/sabre/format"
+)
+
+func TestSource(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		name    string
+		src     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Empty",
+			src:  "\n\n",
+			want: "",
+		},
+		{
+			name: "Spacing",
+			src:  "\n\n(  def   a ,1 )  \n\n\n\n( b  )",
+			want: "(def a, 1)\n\n(b)\n",
+		},
+		{
+			name: "ClosingDelimiters",
+			src:  "(def a\n  [1 2\n  ]\n)",
+			want: "(def a\n  [1 2])\n",
+		},
+		{
+			name: "BlockForms",
+			src: `(def f (fn* [a]
+(let* [b a
+c b]
+(do
+a
+b))))`,
+			want: `(def f (fn* [a]
+         (let* [b a
+                c b]
+           (do
+             a
+             b))))
+`,
+		},
+		{
+			name: "AlignedArguments",
+			src: `(assoc m :a 1
+:b 2)
+(assoc
+m :a 1)
+(ns/f a
+b)`,
+			want: `(assoc m :a 1
+       :b 2)
+(assoc
+ m :a 1)
+(ns/f a
+      b)
+`,
+		},
+		{
+			name: "DataLists",
+			src:  "'(1\n2)\n(:k\nm)",
+			want: "'(1\n  2)\n(:k\n m)\n",
+		},
+		{
+			name: "Collections",
+			src:  "[ 1\n2] #{ :a\n:b } {:a 1\n:b [x\ny]}",
+			want: "[1\n 2] #{:a\n      :b} {:a 1\n           :b [x\n               y]}\n",
+		},
+		{
+			name: "Comments",
+			src: `; header
+
+(def a 1)      ; aligned
+(def b 2)      ; aligned
+(f x ; x
+y ; y
+)`,
+			want: `; header
+
+(def a 1)      ; aligned
+(def b 2)      ; aligned
+(f x ; x
+   y ; y
+   )
+`,
+		},
+		{
+			name: "MultilineString",
+			src:  "(f \"a\n  b\"\n   c)",
+			want: "(f \"a\n  b\"\n   c)\n",
+		},
+		{
+			name: "Prefixes",
+			src:  "' a\n#_ (b)\n#inst   \"2020\"\n`(~ a ~@ b)",
+			want: "'a\n#_(b)\n#inst \"2020\"\n`(~a ~@b)\n",
+		},
+		{
+			name:    "Invalid",
+			src:     "(a",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range table {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := format.Source([]byte(tt.src))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Source() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if string(got) != tt.want {
+				t.Errorf("Source() got = \n%s\nwant = \n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSource_Idempotent(t *testing.T) {
+	t.Parallel()
+
+	files, err := filepath.Glob("../examples/*.lisp")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("no example files found: %v", err)
+	}
+
+	for _, file := range files {
+		src, err := ioutil.ReadFile(file)
+		if err != nil {
+			t.Fatalf("ReadFile() unexpected error: %v", err)
+		}
+
+		once, err := format.Source(src)
+		if err != nil {
+			t.Fatalf("Source(%s) unexpected error: %v", file, err)
+		}
+
+		twice, err := format.Source(once)
+		if err != nil {
+			t.Fatalf("Source(%s) unexpected error: %v", file, err)
+		}
+
+		if string(once) != string(twice) {
+			t.Errorf("Source(%s) is not idempotent:\n%s\n---\n%s", file, once, twice)
+		}
+	}
+}
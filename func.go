@@ -10,6 +10,12 @@ type MultiFn struct {
 	Name    string
 	IsMacro bool
 	Methods []Fn
+
+	// scope is the scope in which the function was defined. If set, method
+	// bodies are evaluated in a child of this scope instead of the scope of
+	// invocation.
+	scope Scope
+	meta  *HashMap
 }
 
 // Eval returns the multiFn definition itself.
@@ -23,13 +29,8 @@ func (multiFn MultiFn) String() string {
 
 // Invoke dispatches the call to a method based on number of arguments.
 func (multiFn MultiFn) Invoke(scope Scope, args ...Value) (Value, error) {
-	fn, err := multiFn.selectMethod(args)
-	if err != nil {
-		return nil, err
-	}
-
 	if multiFn.IsMacro {
-		v, err := fn.Invoke(scope, args)
+		v, err := multiFn.expand(scope, args)
 		if err != nil {
 			return nil, err
 		}
@@ -37,12 +38,36 @@ func (multiFn MultiFn) Invoke(scope Scope, args ...Value) (Value, error) {
 		return v.Eval(scope)
 	}
 
+	fn, err := multiFn.selectMethod(args)
+	if err != nil {
+		return nil, err
+	}
+
 	argVals, err := evalValueList(scope, args)
 	if err != nil {
 		return nil, err
 	}
 
-	return fn.Invoke(scope, argVals)
+	return fn.invoke(multiFn.bodyScope(scope), scope, argVals)
+}
+
+// expand invokes the macro with unevaluated args and returns the resulting
+// form.
+func (multiFn MultiFn) expand(scope Scope, args []Value) (Value, error) {
+	fn, err := multiFn.selectMethod(args)
+	if err != nil {
+		return nil, err
+	}
+
+	return fn.invoke(multiFn.bodyScope(scope), scope, args)
+}
+
+func (multiFn MultiFn) bodyScope(scope Scope) Scope {
+	if multiFn.scope != nil {
+		return multiFn.scope
+	}
+
+	return scope
 }
 
 func (multiFn MultiFn) selectMethod(args []Value) (Fn, error) {
@@ -70,11 +95,39 @@ type Fn struct {
 
 // Invoke executes the function with given arguments.
 func (fn Fn) Invoke(scope Scope, args []Value) (Value, error) {
+	return fn.invoke(scope, nil, args)
+}
+
+// invoke executes the function body in a new child scope of 'scope'. If
+// the call site scope 'caller' is different from 'scope', it is recorded in
+// the new scope. Returns the error of the context if the evaluation has been
+// cancelled so that long-running or recursive functions can be interrupted.
+func (fn Fn) invoke(scope, caller Scope, args []Value) (Value, error) {
+	if cf, isCompiled := fn.Func.(compiledFunc); isCompiled {
+		if caller == nil {
+			caller = scope
+		}
+		return cf.call(caller, args)
+	}
+
 	if fn.Func != nil {
 		return fn.Func.Invoke(scope, args...)
 	}
 
+	if caller == nil {
+		caller = scope
+	}
+
+	ctx := Context(caller)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	fnScope := NewScope(scope)
+	fnScope.ctx = ctx
+	if caller != scope {
+		fnScope.caller = caller
+	}
 
 	for idx := range fn.Args {
 		var argVal Value
@@ -168,6 +221,46 @@ func toArgNames(vals []Value) ([]string, error) {
 	return names, nil
 }
 
+// Apply invokes 'fn' with argument values that are already evaluated (e.g.,
+// values received by a Go function). Since Invokable implementations
+// evaluate their arguments, values which are not self-evaluating are quoted
+// to prevent them from being evaluated twice.
+func Apply(scope Scope, fn Invokable, args []Value) (Value, error) {
+	return applyInPlace(scope, fn, append([]Value(nil), args...))
+}
+
+// applyInPlace is same as Apply but quotes the values in 'args' in place.
+func applyInPlace(scope Scope, fn Invokable, args []Value) (Value, error) {
+	for i, arg := range args {
+		args[i] = quoteValue(arg)
+	}
+
+	return fn.Invoke(scope, args...)
+}
+
+func quoteValue(v Value) Value {
+	switch v.(type) {
+	case Symbol, *List, Vector, Set, *HashMap, Module:
+		return &List{
+			Values: []Value{Symbol{Value: "quote"}, v},
+			special: func(_ Scope) (Value, error) {
+				return v, nil
+			},
+		}
+
+	default:
+		return v
+	}
+}
+
+// compiledFunc is implemented by Fn.Func of the functions created by
+// compiled programs. Such functions are called with evaluated args and the
+// scope of the call site.
+type compiledFunc interface {
+	Invokable
+	call(caller Scope, args []Value) (Value, error)
+}
+
 // GoFunc implements Invokable using a Go function value.
 type GoFunc func(scope Scope, args []Value) (Value, error)
 
@@ -187,3 +187,53 @@ func TestFn_Invoke(t *testing.T) {
 		})
 	}
 }
+
+func TestMultiFn_Invoke_Scope(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		name    string
+		src     string
+		want    sabre.Value
+		wantErr bool
+	}{
+		{
+			// previously, body was evaluated in the scope of invocation and
+			// 'x' could not be resolved.
+			name: "ClosesOverDefinitionScope",
+			src:  `(def make-f (fn* [x] (fn* [] x))) ((make-f 10))`,
+			want: sabre.Int64(10),
+		},
+		{
+			// previously, local bindings of the call site were visible to
+			// the body.
+			name:    "CallSiteLocalsNotVisible",
+			src:     `(def f (fn* [] y)) (let* [y 1] (f))`,
+			wantErr: true,
+		},
+		{
+			name: "GlobalsResolvedAtInvocation",
+			src:  `(def f (fn* [] z)) (def z 5) (f)`,
+			want: sabre.Int64(5),
+		},
+		{
+			name: "ArgsShadowDefinitionScope",
+			src:  `(def x 1) (def f (fn* [x] x)) (f 2)`,
+			want: sabre.Int64(2),
+		},
+	}
+
+	for _, tt := range table {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sabre.ReadEvalStr(sabre.NewScope(nil), tt.src)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadEvalStr() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReadEvalStr() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
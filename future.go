@@ -0,0 +1,352 @@
+package sabre
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrCancelled is returned when dereferencing a cancelled future.
+	ErrCancelled = errors.New("future cancelled")
+
+	// ErrDelayCycle is returned when a delay is forced within its own
+	// evaluation.
+	ErrDelayCycle = errors.New("delay dereferenced during its own evaluation")
+)
+
+// Pending represents reference types whose value may not be available
+// immediately (e.g., Future, Promise, Delay).
+type Pending interface {
+	Derefable
+
+	// Realized returns true if the value is available.
+	Realized() bool
+}
+
+// BlockingDerefable represents reference types which block on deref until
+// the value is available and support dereferencing with a timeout.
+type BlockingDerefable interface {
+	Derefable
+
+	// DerefTimeout blocks until the value is available or the timeout
+	// expires. 'timeoutVal' is returned if the timeout expires.
+	DerefTimeout(timeout time.Duration, timeoutVal Value) (Value, error)
+}
+
+// NewFuture evaluates the form in a new goroutine and returns a Future for
+// the result. Evaluation happens in a scope that carries a context derived
+// from the context of 'scope', which is cancelled when the future is
+// cancelled.
+func NewFuture(scope Scope, form Value) *Future {
+	ctx, cancel := context.WithCancel(Context(scope))
+
+	f := &Future{
+		ctx:    ctx,
+		cancel: cancel,
+		done:   make(chan struct{}),
+	}
+
+	go func() {
+		defer cancel()
+
+		var v Value
+		err := ctx.Err()
+		if err == nil {
+			v, err = form.Eval(WithContext(ctx, scope))
+		}
+
+		f.mu.Lock()
+		f.val, f.err, f.completed = v, err, true
+		f.mu.Unlock()
+		close(f.done)
+	}()
+
+	return f
+}
+
+// Future represents the result of an evaluation happening in a separate
+// goroutine.
+type Future struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	done   chan struct{}
+
+	mu          sync.Mutex
+	val         Value
+	err         error
+	completed   bool
+	isCancelled bool
+}
+
+// Eval returns the future itself.
+func (f *Future) Eval(_ Scope) (Value, error) { return f, nil }
+
+func (f *Future) String() string {
+	if !f.Realized() {
+		return "Future{pending}"
+	}
+
+	v, err := f.Deref()
+	if err != nil {
+		return fmt.Sprintf("Future{error=%v}", err)
+	}
+
+	return fmt.Sprintf("Future{%v}", v)
+}
+
+// Deref blocks until the evaluation completes and returns the result.
+func (f *Future) Deref() (Value, error) {
+	select {
+	case <-f.done:
+		return f.result()
+
+	case <-f.ctx.Done():
+		return f.cancelled()
+	}
+}
+
+// DerefTimeout blocks until the evaluation completes or the timeout expires.
+func (f *Future) DerefTimeout(timeout time.Duration, timeoutVal Value) (Value, error) {
+	select {
+	case <-f.done:
+		return f.result()
+
+	case <-f.ctx.Done():
+		return f.cancelled()
+
+	case <-time.After(timeout):
+		return timeoutVal, nil
+	}
+}
+
+// Realized returns true if the evaluation has completed or the future has
+// been cancelled.
+func (f *Future) Realized() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	return f.completed || f.isCancelled
+}
+
+// Cancel cancels the context of the evaluation. Returns false if the future
+// has already completed or has been cancelled.
+func (f *Future) Cancel() bool {
+	f.mu.Lock()
+	if f.completed || f.isCancelled {
+		f.mu.Unlock()
+		return false
+	}
+	f.isCancelled = true
+	f.mu.Unlock()
+
+	f.cancel()
+	return true
+}
+
+func (f *Future) result() (Value, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.isCancelled {
+		return nil, ErrCancelled
+	}
+
+	return f.val, f.err
+}
+
+// cancelled is used once the context of the future is done. Since context is
+// also cancelled after the evaluation completes, result is returned if the
+// evaluation has completed.
+func (f *Future) cancelled() (Value, error) {
+	select {
+	case <-f.done:
+		return f.result()
+
+	default:
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		if f.isCancelled {
+			return nil, ErrCancelled
+		}
+
+		return nil, f.ctx.Err()
+	}
+}
+
+// NewPromise returns a Promise which can be delivered exactly once. Blocked
+// deref of the promise returns an error once 'ctx' is cancelled.
+func NewPromise(ctx context.Context) *Promise {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	return &Promise{
+		ctx:  ctx,
+		done: make(chan struct{}),
+	}
+}
+
+// Promise represents a value that will be delivered in future.
+type Promise struct {
+	ctx  context.Context
+	once sync.Once
+	done chan struct{}
+	val  Value
+}
+
+// Eval returns the promise itself.
+func (p *Promise) Eval(_ Scope) (Value, error) { return p, nil }
+
+func (p *Promise) String() string {
+	if !p.Realized() {
+		return "Promise{pending}"
+	}
+
+	return fmt.Sprintf("Promise{%v}", p.val)
+}
+
+// Deliver sets the value of the promise and unblocks all waiting readers.
+// Returns false if the promise was already delivered.
+func (p *Promise) Deliver(v Value) bool {
+	delivered := false
+	p.once.Do(func() {
+		p.val = v
+		close(p.done)
+		delivered = true
+	})
+
+	return delivered
+}
+
+// Deref blocks until the promise is delivered and returns the value.
+func (p *Promise) Deref() (Value, error) {
+	select {
+	case <-p.done:
+		return p.val, nil
+
+	case <-p.ctx.Done():
+		return nil, p.ctx.Err()
+	}
+}
+
+// DerefTimeout blocks until the promise is delivered or the timeout expires.
+func (p *Promise) DerefTimeout(timeout time.Duration, timeoutVal Value) (Value, error) {
+	select {
+	case <-p.done:
+		return p.val, nil
+
+	case <-p.ctx.Done():
+		return nil, p.ctx.Err()
+
+	case <-time.After(timeout):
+		return timeoutVal, nil
+	}
+}
+
+// Realized returns true if the promise has been delivered.
+func (p *Promise) Realized() bool {
+	select {
+	case <-p.done:
+		return true
+
+	default:
+		return false
+	}
+}
+
+// NewDelay returns a Delay which evaluates the form against the scope only
+// when dereferenced for the first time.
+func NewDelay(scope Scope, form Value) *Delay {
+	return &Delay{scope: scope, form: form}
+}
+
+// Delay represents a lazily evaluated form. Form is evaluated at-most once
+// and the result (or error) is cached.
+type Delay struct {
+	scope Scope
+	form  Value
+
+	once     sync.Once
+	realized bool
+	mu       sync.Mutex
+	val      Value
+	err      error
+}
+
+// Eval returns the delay itself.
+func (d *Delay) Eval(_ Scope) (Value, error) { return d, nil }
+
+func (d *Delay) String() string {
+	if !d.Realized() {
+		return "Delay{pending}"
+	}
+
+	v, err := d.Deref()
+	if err != nil {
+		return fmt.Sprintf("Delay{error=%v}", err)
+	}
+
+	return fmt.Sprintf("Delay{%v}", v)
+}
+
+// Deref evaluates the form if not already evaluated and returns the result.
+// Use Force to detect dereferencing of the delay within its own evaluation.
+func (d *Delay) Deref() (Value, error) {
+	return d.Force(nil)
+}
+
+// Force is same as Deref but returns an error instead of blocking forever if
+// the delay is dereferenced within its own evaluation. 'scope' is the scope
+// of the deref and is used to find the delays being evaluated along the
+// chain of calls.
+func (d *Delay) Force(scope Scope) (Value, error) {
+	forcing, _ := Context(scope).Value(delayKey{}).(*delayFrame)
+	for df := forcing; df != nil; df = df.next {
+		if df.delay == d {
+			return nil, ErrDelayCycle
+		}
+	}
+
+	d.once.Do(func() {
+		evalScope := d.scope
+		if evalScope == nil {
+			evalScope = NewScope(nil)
+		}
+
+		frame := &delayFrame{delay: d, next: forcing}
+		evalCtx := context.WithValue(Context(evalScope), delayKey{}, frame)
+
+		v, err := d.form.Eval(WithContext(evalCtx, evalScope))
+
+		d.mu.Lock()
+		d.val, d.err, d.realized = v, err, true
+		d.scope, d.form = nil, nil
+		d.mu.Unlock()
+	})
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.val, d.err
+}
+
+// Realized returns true if the form has been evaluated.
+func (d *Delay) Realized() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.realized
+}
+
+// delayFrame records a delay being evaluated. Frames are chained through the
+// context of the evaluation (See Delay.Force).
+type delayFrame struct {
+	delay *Delay
+	next  *delayFrame
+}
+
+type delayKey struct{}
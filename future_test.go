@@ -0,0 +1,257 @@
+package sabre_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/spy16/sabre"
+)
+
+var (
+	_ sabre.Pending           = (*sabre.Future)(nil)
+	_ sabre.Pending           = (*sabre.Promise)(nil)
+	_ sabre.Pending           = (*sabre.Delay)(nil)
+	_ sabre.BlockingDerefable = (*sabre.Future)(nil)
+	_ sabre.BlockingDerefable = (*sabre.Promise)(nil)
+)
+
+func TestFuture(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Deref", func(t *testing.T) {
+		f := sabre.NewFuture(sabre.NewScope(nil), sabre.Int64(10))
+
+		got, err := f.Deref()
+		if err != nil || got != sabre.Int64(10) {
+			t.Errorf("Deref() got = (%v, %v), want (10, nil)", got, err)
+		}
+
+		if !f.Realized() {
+			t.Errorf("Realized() expected to be true after Deref()")
+		}
+	})
+
+	t.Run("DerefTimeout", func(t *testing.T) {
+		scope := sabre.NewScope(nil)
+		f := sabre.NewFuture(scope, blockingForm(scope))
+		defer f.Cancel()
+
+		got, err := f.DerefTimeout(time.Millisecond, sabre.Keyword("timeout"))
+		if err != nil || got != sabre.Keyword("timeout") {
+			t.Errorf("DerefTimeout() got = (%v, %v), want (:timeout, nil)", got, err)
+		}
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		scope := sabre.NewScope(nil)
+		f := sabre.NewFuture(scope, blockingForm(scope))
+
+		if !f.Cancel() {
+			t.Errorf("Cancel() expected to return true")
+		}
+
+		if _, err := f.Deref(); err != sabre.ErrCancelled {
+			t.Errorf("Deref() error = %v, want ErrCancelled", err)
+		}
+	})
+
+	t.Run("ContextCancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		scope := sabre.WithContext(ctx, sabre.NewScope(nil))
+
+		f := sabre.NewFuture(scope, blockingForm(scope))
+		cancel()
+
+		if _, err := f.Deref(); err != context.Canceled {
+			t.Errorf("Deref() error = %v, want context.Canceled", err)
+		}
+	})
+}
+
+func TestPromise(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Deliver", func(t *testing.T) {
+		p := sabre.NewPromise(context.Background())
+		if p.Realized() {
+			t.Errorf("Realized() expected to be false before Deliver()")
+		}
+
+		go p.Deliver(sabre.Int64(1))
+
+		got, err := p.Deref()
+		if err != nil || got != sabre.Int64(1) {
+			t.Errorf("Deref() got = (%v, %v), want (1, nil)", got, err)
+		}
+
+		if p.Deliver(sabre.Int64(2)) {
+			t.Errorf("Deliver() expected to return false for delivered promise")
+		}
+	})
+
+	t.Run("DerefTimeout", func(t *testing.T) {
+		p := sabre.NewPromise(nil)
+
+		got, err := p.DerefTimeout(time.Millisecond, sabre.Nil{})
+		if err != nil || got != (sabre.Nil{}) {
+			t.Errorf("DerefTimeout() got = (%v, %v), want (nil, nil)", got, err)
+		}
+	})
+
+	t.Run("ContextCancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		p := sabre.NewPromise(ctx)
+		cancel()
+
+		if _, err := p.Deref(); err != context.Canceled {
+			t.Errorf("Deref() error = %v, want context.Canceled", err)
+		}
+	})
+}
+
+func TestDelay(t *testing.T) {
+	t.Parallel()
+
+	var count int32
+	scope := sabre.NewScope(nil)
+	_ = scope.BindGo("count", func() int64 {
+		return int64(atomic.AddInt32(&count, 1))
+	})
+
+	d := sabre.NewDelay(scope, &sabre.List{Values: []sabre.Value{sabre.Symbol{Value: "count"}}})
+	if d.Realized() {
+		t.Errorf("Realized() expected to be false before Deref()")
+	}
+
+	for i := 0; i < 3; i++ {
+		got, err := d.Deref()
+		if err != nil || got != sabre.Int64(1) {
+			t.Errorf("Deref() got = (%v, %v), want (1, nil)", got, err)
+		}
+	}
+
+	if !d.Realized() || atomic.LoadInt32(&count) != 1 {
+		t.Errorf("expected delay to be realized exactly once, count=%d", count)
+	}
+
+	var self *sabre.Delay
+	_ = scope.Bind("force-self", sabre.GoFunc(func(scope sabre.Scope, _ []sabre.Value) (sabre.Value, error) {
+		return self.Force(scope)
+	}))
+
+	self = sabre.NewDelay(scope, &sabre.List{Values: []sabre.Value{sabre.Symbol{Value: "force-self"}}})
+	if _, err := self.Deref(); !errors.Is(err, sabre.ErrDelayCycle) {
+		t.Errorf("Deref() error = %v, want ErrDelayCycle", err)
+	}
+}
+
+func TestContext(t *testing.T) {
+	t.Parallel()
+
+	if got := sabre.Context(sabre.NewScope(nil)); got != context.Background() {
+		t.Errorf("Context() got = %v, want background context", got)
+	}
+
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+
+	root := sabre.NewScope(nil)
+	_ = root.Bind("get-ctx", sabre.GoFunc(func(scope sabre.Scope, _ []sabre.Value) (sabre.Value, error) {
+		return sabre.ValueOf(sabre.Context(scope).Value(ctxKey{})), nil
+	}))
+
+	if _, err := sabre.ReadEvalStr(root, `(def f (fn* [] (get-ctx)))`); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := sabre.ReadEvalStr(sabre.WithContext(ctx, sabre.NewScope(root)), `(f)`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(got, sabre.String("value")) {
+		t.Errorf("Context() in function body got = %v, want \"value\"", got)
+	}
+}
+
+func TestContext_Cancelled(t *testing.T) {
+	t.Parallel()
+
+	t.Run("Future", func(t *testing.T) {
+		scope, ticks := burnScope(t)
+
+		form, err := sabre.NewReader(strings.NewReader(`(burn 0)`)).One()
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		f := sabre.NewFuture(scope, form)
+		for atomic.LoadInt64(ticks) == 0 {
+			time.Sleep(time.Millisecond)
+		}
+
+		if !f.Cancel() {
+			t.Fatalf("Cancel() expected to return true")
+		}
+
+		time.Sleep(10 * time.Millisecond)
+		before := atomic.LoadInt64(ticks)
+		time.Sleep(20 * time.Millisecond)
+
+		if after := atomic.LoadInt64(ticks); after != before {
+			t.Errorf("evaluation continued after Cancel(): ticks %d -> %d", before, after)
+		}
+	})
+
+	t.Run("ParentContext", func(t *testing.T) {
+		scope, _ := burnScope(t)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		_, err := sabre.ReadEvalStr(sabre.WithContext(ctx, scope), `(burn 0)`)
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("ReadEvalStr() error = %v, want context.DeadlineExceeded", err)
+		}
+	})
+}
+
+// blockingForm returns a form which blocks until the context of evaluation
+// is cancelled.
+func blockingForm(scope sabre.Scope) sabre.Value {
+	ch := sabre.NewChan(0)
+
+	return &sabre.List{
+		Values: []sabre.Value{
+			sabre.GoFunc(func(scope sabre.Scope, _ []sabre.Value) (sabre.Value, error) {
+				return ch.Take(sabre.Context(scope))
+			}),
+		},
+	}
+}
+
+// burnScope returns a scope with 'burn' bound to a recursive function which
+// runs practically forever and counts its invocations in 'ticks'.
+func burnScope(t *testing.T) (sabre.Scope, *int64) {
+	ticks := new(int64)
+
+	scope := sabre.NewScope(nil)
+	_ = scope.BindGo("inc", func(i sabre.Int64) sabre.Int64 { return i + 1 })
+	_ = scope.BindGo("deeper?", func(i sabre.Int64) bool { return i < 40 })
+	_ = scope.Bind("tick", sabre.GoFunc(func(_ sabre.Scope, _ []sabre.Value) (sabre.Value, error) {
+		atomic.AddInt64(ticks, 1)
+		return sabre.Nil{}, nil
+	}))
+
+	src := `(def burn (fn* [n] (tick) (if (deeper? n) (do (burn (inc n)) (burn (inc n))))))`
+	if _, err := sabre.ReadEvalStr(scope, src); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	return scope, ticks
+}
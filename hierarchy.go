@@ -0,0 +1,173 @@
+package sabre
+
+import (
+	"fmt"
+	"sync"
+)
+
+// NewHierarchy returns an empty hierarchy.
+func NewHierarchy() *Hierarchy {
+	return &Hierarchy{parents: &HashMap{}}
+}
+
+// Hierarchy represents parent-child relationships between values (usually
+// keywords) established using derive. Multimethods use the hierarchy to
+// find methods for dispatch values which are derived from the dispatch
+// values of the methods. Values are compared the same way as the keys of a
+// HashMap (i.e., by value). All operations on a Hierarchy are safe for use
+// from multiple goroutines.
+type Hierarchy struct {
+	mu      sync.RWMutex
+	parents *HashMap // child -> Vector of parents
+}
+
+// Derive establishes a parent-child relationship between the values. An
+// error is returned if the relationship would introduce a cycle.
+func (h *Hierarchy) Derive(child, parent Value) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if equalKeys(child, parent) {
+		return fmt.Errorf("'%s' can not be derived from itself", child)
+	}
+
+	if h.isDerived(parent, child) {
+		return fmt.Errorf("cyclic derivation: '%s' already derives from '%s'", parent, child)
+	}
+
+	if h.isDerived(child, parent) {
+		return nil
+	}
+
+	if h.parents == nil {
+		h.parents = &HashMap{}
+	}
+
+	parents := append(append([]Value(nil), h.parentsOf(child)...), parent)
+	h.parents.put(child, Vector{Values: parents})
+	return nil
+}
+
+// Underive removes the parent-child relationship between the values if
+// present.
+func (h *Hierarchy) Underive(child, parent Value) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	var parents []Value
+	for _, p := range h.parentsOf(child) {
+		if !equalKeys(p, parent) {
+			parents = append(parents, p)
+		}
+	}
+
+	if len(parents) == 0 {
+		h.parents = h.parents.Dissoc(child)
+	} else {
+		h.parents.put(child, Vector{Values: parents})
+	}
+}
+
+// IsA returns true if the child is equal to the parent or is derived from
+// the parent directly or indirectly. Vectors are compared element-wise.
+// Hierarchy can be nil in which case only equality is checked.
+func (h *Hierarchy) IsA(child, parent Value) bool {
+	if h != nil {
+		h.mu.RLock()
+		defer h.mu.RUnlock()
+	}
+
+	return h.isA(child, parent)
+}
+
+// Parents returns the values from which the value is derived directly.
+func (h *Hierarchy) Parents(v Value) []Value {
+	if h == nil {
+		return nil
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	return append([]Value(nil), h.parentsOf(v)...)
+}
+
+// Ancestors returns the values from which the value is derived directly or
+// indirectly. Parents appear before their ancestors.
+func (h *Hierarchy) Ancestors(v Value) []Value {
+	if h == nil {
+		return nil
+	}
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	var ancestors []Value
+	seen := &HashMap{}
+	queue := h.parentsOf(v)
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+
+		if seen.find(p) >= 0 {
+			continue
+		}
+
+		seen.put(p, Nil{})
+		ancestors = append(ancestors, p)
+		queue = append(queue[:len(queue):len(queue)], h.parentsOf(p)...)
+	}
+
+	return ancestors
+}
+
+func (h *Hierarchy) isA(child, parent Value) bool {
+	if equalKeys(child, parent) {
+		return true
+	}
+
+	cv, isChildVec := child.(Vector)
+	pv, isParentVec := parent.(Vector)
+	if isChildVec && isParentVec {
+		if len(cv.Values) != len(pv.Values) {
+			return false
+		}
+
+		for i := range cv.Values {
+			if !h.isA(cv.Values[i], pv.Values[i]) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return h.isDerived(child, parent)
+}
+
+// isDerived returns true if the child is derived from the parent directly
+// or indirectly. Must be called with the lock held.
+func (h *Hierarchy) isDerived(child, parent Value) bool {
+	if h == nil {
+		return false
+	}
+
+	for _, p := range h.parentsOf(child) {
+		if equalKeys(p, parent) || h.isDerived(p, parent) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parentsOf returns the values from which the value is derived directly.
+// Must be called with the lock held.
+func (h *Hierarchy) parentsOf(v Value) []Value {
+	parents, found := h.parents.Get(v)
+	if !found {
+		return nil
+	}
+
+	return parents.(Vector).Values
+}
@@ -0,0 +1,373 @@
+package sabre
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// ParseJSON reads the JSON value in data. Objects are read as HashMap with
+// keyword keys if keywordKeys is true and with string keys otherwise, arrays
+// as Vector, numbers as Int64 (BigInt if the number does not fit in int64)
+// or Float64 and null as Nil.
+func ParseJSON(data []byte, keywordKeys bool) (Value, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	v, err := readJSON(dec, keywordKeys)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, errors.New("invalid JSON: unexpected data after top-level value")
+	}
+
+	return v, nil
+}
+
+// ToJSON returns the JSON representation of the value. Lists, vectors and
+// sets are written as arrays, maps as objects, keywords, symbols and
+// characters as strings and tagged values (e.g., #inst) as their forms.
+// Returns error if the value cannot be represented in JSON (e.g., functions).
+func ToJSON(v Value) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := writeJSON(&buf, v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// MarshalJSON returns the JSON representation of the list as an array.
+func (lf *List) MarshalJSON() ([]byte, error) { return ToJSON(lf) }
+
+// MarshalJSON returns the JSON representation of the vector as an array.
+func (v Vector) MarshalJSON() ([]byte, error) { return ToJSON(v) }
+
+// MarshalJSON returns the JSON representation of the set as an array.
+func (set Set) MarshalJSON() ([]byte, error) { return ToJSON(set) }
+
+// MarshalJSON returns the JSON representation of the map as an object.
+// Strings, keywords and symbols are used as keys by their names and other
+// scalar keys (numbers, booleans, characters) by their printed form. Returns
+// error if two keys have the same representation (e.g., :a and "a").
+func (hm *HashMap) MarshalJSON() ([]byte, error) { return ToJSON(hm) }
+
+// MarshalJSON returns JSON null.
+func (n Nil) MarshalJSON() ([]byte, error) { return []byte("null"), nil }
+
+// MarshalJSON returns the name of the symbol as JSON string.
+func (sym Symbol) MarshalJSON() ([]byte, error) { return json.Marshal(sym.Value) }
+
+// MarshalJSON returns the number as JSON number.
+func (bi BigInt) MarshalJSON() ([]byte, error) { return []byte(bi.Int.String()), nil }
+
+// MarshalJSON returns the character as JSON string.
+func (char Character) MarshalJSON() ([]byte, error) { return json.Marshal(string(char)) }
+
+// UnmarshalJSON reads a JSON array into the vector. See ParseJSON for the
+// conversion of elements.
+func (v *Vector) UnmarshalJSON(data []byte) error {
+	vals, err := parseJSONArray(data, "Vector")
+	if err != nil {
+		return err
+	}
+
+	v.Values = vals
+	return nil
+}
+
+// UnmarshalJSON reads a JSON array into the list. See ParseJSON for the
+// conversion of elements.
+func (lf *List) UnmarshalJSON(data []byte) error {
+	vals, err := parseJSONArray(data, "List")
+	if err != nil {
+		return err
+	}
+
+	lf.Values = vals
+	return nil
+}
+
+// UnmarshalJSON reads a JSON array into the set. Duplicate elements are
+// retained only once. See ParseJSON for the conversion of elements.
+func (set *Set) UnmarshalJSON(data []byte) error {
+	vals, err := parseJSONArray(data, "Set")
+	if err != nil {
+		return err
+	}
+
+	set.Values = uniq(vals)
+	return nil
+}
+
+// UnmarshalJSON reads a JSON object into the map. Keys are read as strings.
+// Use ParseJSON to read keys as keywords.
+func (hm *HashMap) UnmarshalJSON(data []byte) error {
+	val, err := ParseJSON(data, false)
+	if err != nil {
+		return err
+	}
+
+	m, isMap := val.(*HashMap)
+	if !isMap {
+		return fmt.Errorf("cannot unmarshal JSON %s into HashMap", jsonKind(val))
+	}
+
+	hm.entries, hm.index = m.entries, m.index
+	return nil
+}
+
+// parseJSONArray reads the elements of the JSON array in data. 'target' is
+// the name of the type being unmarshalled into for error messages.
+func parseJSONArray(data []byte, target string) ([]Value, error) {
+	val, err := ParseJSON(data, false)
+	if err != nil {
+		return nil, err
+	}
+
+	vec, isVector := val.(Vector)
+	if !isVector {
+		return nil, fmt.Errorf("cannot unmarshal JSON %s into %s", jsonKind(val), target)
+	}
+
+	return vec.Values, nil
+}
+
+func readJSON(dec *json.Decoder, keywordKeys bool) (Value, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		if err == io.EOF {
+			return nil, errors.New("invalid JSON: unexpected end of input")
+		}
+		return nil, err
+	}
+
+	switch t := tok.(type) {
+	case nil:
+		return Nil{}, nil
+
+	case bool:
+		return Bool(t), nil
+
+	case string:
+		return String(t), nil
+
+	case json.Number:
+		return parseJSONNumber(t)
+
+	case json.Delim:
+		if t == '[' {
+			vals := []Value{}
+			for dec.More() {
+				v, err := readJSON(dec, keywordKeys)
+				if err != nil {
+					return nil, err
+				}
+				vals = append(vals, v)
+			}
+			_, err := dec.Token()
+			return Vector{Values: vals}, err
+		}
+
+		hm := &HashMap{}
+		for dec.More() {
+			keyTok, err := dec.Token()
+			if err != nil {
+				return nil, err
+			}
+
+			var key Value = String(keyTok.(string))
+			if keywordKeys {
+				key = Keyword(keyTok.(string))
+			}
+
+			val, err := readJSON(dec, keywordKeys)
+			if err != nil {
+				return nil, err
+			}
+			hm.put(key, val)
+		}
+		_, err := dec.Token()
+		return hm, err
+	}
+
+	return nil, fmt.Errorf("invalid JSON: unexpected token %v", tok)
+}
+
+func parseJSONNumber(num json.Number) (Value, error) {
+	s := string(num)
+	if strings.ContainsAny(s, ".eE") {
+		f, err := num.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("invalid JSON number '%s'", s)
+		}
+		return Float64(f), nil
+	}
+
+	if i, err := num.Int64(); err == nil {
+		return Int64(i), nil
+	}
+
+	bi, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid JSON number '%s'", s)
+	}
+	return BigInt{Int: bi}, nil
+}
+
+func writeJSON(buf *bytes.Buffer, v Value) error {
+	switch val := v.(type) {
+	case Nil:
+		buf.WriteString("null")
+
+	case Bool, Int64:
+		buf.WriteString(val.String())
+
+	case BigInt:
+		buf.WriteString(val.Int.String())
+
+	case Float64:
+		f := float64(val)
+		if math.IsInf(f, 0) || math.IsNaN(f) {
+			return fmt.Errorf("cannot convert %v to JSON", f)
+		}
+		buf.WriteString(strconv.FormatFloat(f, 'g', -1, 64))
+
+	case String, Keyword, Symbol, Character:
+		return writeJSONString(buf, jsonName(val))
+
+	case *List:
+		return writeJSONArray(buf, val.Values)
+
+	case Vector:
+		return writeJSONArray(buf, val.Values)
+
+	case Set:
+		return writeJSONArray(buf, val.Values)
+
+	case *HashMap:
+		seen := map[string]bool{}
+
+		buf.WriteByte('{')
+		for i, e := range val.items() {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+
+			key, err := jsonKey(e.key)
+			if err != nil {
+				return err
+			}
+
+			if seen[key] {
+				return fmt.Errorf("cannot convert map to JSON: more than one key is written as '%s'", key)
+			}
+			seen[key] = true
+
+			if err := writeJSONString(buf, key); err != nil {
+				return err
+			}
+			buf.WriteByte(':')
+
+			if err := writeJSON(buf, e.val); err != nil {
+				return err
+			}
+		}
+		buf.WriteByte('}')
+
+	case Tagged:
+		_, form := val.Tag()
+		return writeJSON(buf, form)
+
+	case json.Marshaler:
+		data, err := val.MarshalJSON()
+		if err != nil {
+			return err
+		}
+		buf.Write(data)
+
+	default:
+		return fmt.Errorf("cannot convert value of type '%s' to JSON", reflect.TypeOf(v))
+	}
+
+	return nil
+}
+
+func writeJSONArray(buf *bytes.Buffer, vals []Value) error {
+	buf.WriteByte('[')
+	for i, v := range vals {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+
+		if err := writeJSON(buf, v); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	return nil
+}
+
+func writeJSONString(buf *bytes.Buffer, s string) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	buf.Write(data)
+	return nil
+}
+
+// jsonKey returns the string used as object key for the map key.
+func jsonKey(key Value) (string, error) {
+	switch k := key.(type) {
+	case String, Keyword, Symbol, Character:
+		return jsonName(k), nil
+
+	case Int64, Float64, Bool:
+		return k.String(), nil
+
+	case BigInt:
+		return k.Int.String(), nil
+
+	default:
+		return "", fmt.Errorf("cannot use value of type '%s' as JSON object key", reflect.TypeOf(key))
+	}
+}
+
+func jsonName(v Value) string {
+	switch val := v.(type) {
+	case String:
+		return string(val)
+
+	case Keyword:
+		return string(val)
+
+	case Symbol:
+		return val.Value
+
+	case Character:
+		return string(val)
+	}
+	return v.String()
+}
+
+func jsonKind(v Value) string {
+	switch v.(type) {
+	case *HashMap:
+		return "object"
+
+	case Vector:
+		return "array"
+
+	default:
+		return "value"
+	}
+}
@@ -0,0 +1,213 @@
+package sabre_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/spy16/sabre"
+)
+
+func TestParseJSON(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		name        string
+		src         string
+		keywordKeys bool
+		want        string
+		wantErr     bool
+	}{
+		{
+			name: "Scalars",
+			src:  `[null, true, "a\"b", 1, -2.5, 1e3]`,
+			want: `[nil true "a\"b" 1 -2.5 1000.0]`,
+		},
+		{
+			name: "BigInt",
+			src:  `[9223372036854775807, 9223372036854775808]`,
+			want: `[9223372036854775807 9223372036854775808N]`,
+		},
+		{
+			name: "StringKeys",
+			src:  `{"b": {"c": []}, "a": 1}`,
+			want: `{"b" {"c" []}, "a" 1}`,
+		},
+		{
+			name:        "KeywordKeys",
+			src:         `{"b": {"c": []}, "a": 1}`,
+			keywordKeys: true,
+			want:        `{:b {:c []}, :a 1}`,
+		},
+		{
+			name:    "Invalid",
+			src:     `{"a": }`,
+			wantErr: true,
+		},
+		{
+			name:    "Empty",
+			src:     ``,
+			wantErr: true,
+		},
+		{
+			name:    "TrailingData",
+			src:     `1 2`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range table {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sabre.ParseJSON([]byte(tt.src), tt.keywordKeys)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && got.String() != tt.want {
+				t.Errorf("ParseJSON() got = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestToJSON(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		name    string
+		v       sabre.Value
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Scalars",
+			v: &sabre.List{Values: []sabre.Value{
+				sabre.Nil{}, sabre.Bool(false), sabre.Int64(1), sabre.Float64(2),
+				sabre.String("s"), sabre.Keyword("k"), sabre.Symbol{Value: "sym"}, sabre.Character('c'),
+			}},
+			want: `[null,false,1,2,"s","k","sym","c"]`,
+		},
+		{
+			name: "Map",
+			v: mustHashMap(
+				sabre.Keyword("a"), sabre.Set{Values: []sabre.Value{sabre.Int64(1)}},
+				sabre.Int64(2), mustHashMap(),
+				sabre.String("t"), sabre.Time{Time: time.Date(2020, 1, 18, 0, 0, 0, 0, time.UTC)},
+			),
+			want: `{"a":[1],"2":{},"t":"2020-01-18T00:00:00Z"}`,
+		},
+		{
+			name: "BigInt",
+			v:    mustBigInt("123456789012345678901234567890"),
+			want: `123456789012345678901234567890`,
+		},
+		{
+			name:    "InvalidKey",
+			v:       mustHashMap(sabre.Vector{}, sabre.Int64(1)),
+			wantErr: true,
+		},
+		{
+			name:    "CollidingKeys",
+			v:       mustHashMap(sabre.Keyword("a"), sabre.Int64(1), sabre.String("a"), sabre.Int64(2)),
+			wantErr: true,
+		},
+		{
+			name:    "InvalidValue",
+			v:       sabre.Vector{Values: []sabre.Value{sabre.Regex{Regexp: regexp.MustCompile("a")}}},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range table {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sabre.ToJSON(tt.v)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ToJSON() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if string(got) != tt.want {
+				t.Errorf("ToJSON() got = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHashMap_JSON(t *testing.T) {
+	t.Parallel()
+
+	var payload struct {
+		Data  *sabre.HashMap `json:"data"`
+		Items sabre.Vector   `json:"items"`
+	}
+
+	src := `{"data":{"id":1,"tags":["x"]},"items":[1.5,null]}`
+	if err := json.Unmarshal([]byte(src), &payload); err != nil {
+		t.Fatalf("Unmarshal() unexpected error: %v", err)
+	}
+
+	want := mustHashMap(sabre.String("id"), sabre.Int64(1),
+		sabre.String("tags"), sabre.Vector{Values: []sabre.Value{sabre.String("x")}})
+	if !reflect.DeepEqual(payload.Data, want) {
+		t.Errorf("Unmarshal() got = %v, want %v", payload.Data, want)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("Marshal() unexpected error: %v", err)
+	}
+
+	if string(data) != src {
+		t.Errorf("Marshal() got = %s, want %s", data, src)
+	}
+
+	if err := json.Unmarshal([]byte(`{"data":[1]}`), &payload); err == nil {
+		t.Errorf("Unmarshal() expected error for array into HashMap")
+	}
+}
+
+func TestList_Set_JSON(t *testing.T) {
+	t.Parallel()
+
+	var payload struct {
+		Args *sabre.List `json:"args"`
+		Tags sabre.Set   `json:"tags"`
+	}
+
+	src := `{"args":[1,"a",[true]],"tags":["x","y","x"]}`
+	if err := json.Unmarshal([]byte(src), &payload); err != nil {
+		t.Fatalf("Unmarshal() unexpected error: %v", err)
+	}
+
+	wantArgs := &sabre.List{Values: []sabre.Value{
+		sabre.Int64(1), sabre.String("a"), sabre.Vector{Values: []sabre.Value{sabre.Bool(true)}},
+	}}
+	if !reflect.DeepEqual(payload.Args, wantArgs) {
+		t.Errorf("Unmarshal() got = %v, want %v", payload.Args, wantArgs)
+	}
+
+	wantTags := sabre.Set{Values: []sabre.Value{sabre.String("x"), sabre.String("y")}}
+	if !reflect.DeepEqual(payload.Tags, wantTags) {
+		t.Errorf("Unmarshal() got = %v, want %v", payload.Tags, wantTags)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatalf("Marshal() unexpected error: %v", err)
+	}
+
+	if want := `{"args":[1,"a",[true]],"tags":["x","y"]}`; string(data) != want {
+		t.Errorf("Marshal() got = %s, want %s", data, want)
+	}
+
+	if err := json.Unmarshal([]byte(`{"args":{"a":1}}`), &payload); err == nil {
+		t.Errorf("Unmarshal() expected error for object into List")
+	}
+
+	if err := json.Unmarshal([]byte(`{"tags":1}`), &payload); err == nil {
+		t.Errorf("Unmarshal() expected error for number into Set")
+	}
+}
@@ -0,0 +1,136 @@
+package sabre
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Meta represents a value which can carry metadata. Metadata is a map of
+// additional information about the value (e.g., docstring, type hints) and
+// does not affect evaluation of the value.
+type Meta interface {
+	Value
+
+	// Meta returns the metadata of the value or nil if there is none.
+	Meta() *HashMap
+}
+
+// WithMeta represents a value which supports attaching metadata.
+type WithMeta interface {
+	Meta
+
+	// WithMeta returns a copy of the value with the given metadata.
+	WithMeta(meta *HashMap) Value
+}
+
+// Meta returns the metadata of the symbol.
+func (sym Symbol) Meta() *HashMap { return sym.meta }
+
+// WithMeta returns a copy of the symbol with the given metadata.
+func (sym Symbol) WithMeta(meta *HashMap) Value {
+	sym.meta = meta
+	return sym
+}
+
+// Meta returns the metadata of the list.
+func (lf *List) Meta() *HashMap { return lf.meta }
+
+// WithMeta returns a copy of the list with the given metadata.
+func (lf *List) WithMeta(meta *HashMap) Value {
+	res := *lf
+	res.meta = meta
+	return &res
+}
+
+// Meta returns the metadata of the vector.
+func (vf Vector) Meta() *HashMap { return vf.meta }
+
+// WithMeta returns a copy of the vector with the given metadata.
+func (vf Vector) WithMeta(meta *HashMap) Value {
+	vf.meta = meta
+	return vf
+}
+
+// Meta returns the metadata of the set.
+func (set Set) Meta() *HashMap { return set.meta }
+
+// WithMeta returns a copy of the set with the given metadata.
+func (set Set) WithMeta(meta *HashMap) Value {
+	set.meta = meta
+	return set
+}
+
+// Meta returns the metadata of the map.
+func (hm *HashMap) Meta() *HashMap { return hm.meta }
+
+// WithMeta returns a copy of the map with the given metadata.
+func (hm *HashMap) WithMeta(meta *HashMap) Value {
+	res := hm.withEntries(hm.kvs())
+	res.meta = meta
+	return res
+}
+
+// Meta returns the metadata of the function.
+func (multiFn MultiFn) Meta() *HashMap { return multiFn.meta }
+
+// WithMeta returns a copy of the function with the given metadata.
+func (multiFn MultiFn) WithMeta(meta *HashMap) Value {
+	multiFn.meta = meta
+	return multiFn
+}
+
+// readMeta reads the ^meta form. Metadata can be a map, a keyword
+// (^:private is same as ^{:private true}) or a symbol or string (^Type is
+// same as ^{:tag Type}). The metadata is merged with the existing metadata
+// of the form.
+func readMeta(rd *Reader, _ rune) (Value, error) {
+	m, err := readNextForm(rd, "metadata")
+	if err != nil {
+		return nil, err
+	}
+
+	meta, err := toMeta(m)
+	if err != nil {
+		return nil, err
+	}
+
+	form, err := readNextForm(rd, "form after metadata")
+	if err != nil {
+		return nil, err
+	}
+
+	target, ok := form.(WithMeta)
+	if !ok {
+		return nil, fmt.Errorf("metadata can not be applied to '%s'",
+			reflect.TypeOf(form))
+	}
+
+	return target.WithMeta(mergeMeta(target.Meta(), meta)), nil
+}
+
+func toMeta(v Value) (*HashMap, error) {
+	switch m := v.(type) {
+	case *HashMap:
+		return m, nil
+
+	case Keyword:
+		return NewHashMap(m, Bool(true))
+
+	case Symbol, String:
+		return NewHashMap(Keyword("tag"), m)
+
+	default:
+		return nil, fmt.Errorf("metadata must be symbol, keyword, string or map, not '%s'",
+			reflect.TypeOf(v))
+	}
+}
+
+// mergeMeta returns a map containing entries of both the maps. Entries of
+// 'm2' take precedence.
+func mergeMeta(m1, m2 *HashMap) *HashMap {
+	if m1.Size() == 0 {
+		return m2
+	}
+
+	return m1.withEntries(append(m1.kvs(), m2.kvs()...))
+}
@@ -0,0 +1,201 @@
+package sabre
+
+import (
+	"fmt"
+	"sync"
+)
+
+// NewMultiMethod returns a multimethod which dispatches calls using the
+// result of invoking 'dispatch' with the args. Dispatch values are matched
+// using the hierarchy (See Hierarchy.IsA) which can be nil. The method for
+// the dispatch value :default is used when no other method matches.
+func NewMultiMethod(name string, dispatch Invokable, h *Hierarchy) *MultiMethod {
+	return &MultiMethod{
+		Name:      name,
+		Dispatch:  dispatch,
+		Default:   Keyword("default"),
+		Hierarchy: h,
+	}
+}
+
+// MultiMethod represents a function which dispatches calls to one of its
+// methods based on the value returned by the dispatch function for the args
+// (e.g., (defmulti handle :type) dispatches on the :type of the first arg).
+// Unlike MultiFn, which selects a method based on the number of args, any
+// value can be used for dispatch. All operations on a MultiMethod are safe
+// for use from multiple goroutines.
+type MultiMethod struct {
+	Name      string
+	Dispatch  Invokable
+	Default   Value
+	Hierarchy *Hierarchy
+
+	mu          sync.RWMutex
+	methods     []method
+	preferTable *HashMap // dispatch value -> Vector of values it is preferred to
+}
+
+type method struct {
+	dispatchVal Value
+	fn          Invokable
+}
+
+// Eval returns the multimethod itself.
+func (mm *MultiMethod) Eval(_ Scope) (Value, error) { return mm, nil }
+
+func (mm *MultiMethod) String() string {
+	return fmt.Sprintf("MultiMethod{name=%s}", mm.Name)
+}
+
+// AddMethod sets the function as the method for the dispatch value. Existing
+// method for the dispatch value is replaced.
+func (mm *MultiMethod) AddMethod(dispatchVal Value, fn Invokable) {
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+
+	for i, m := range mm.methods {
+		if equalKeys(m.dispatchVal, dispatchVal) {
+			mm.methods[i].fn = fn
+			return
+		}
+	}
+
+	mm.methods = append(mm.methods, method{dispatchVal: dispatchVal, fn: fn})
+}
+
+// RemoveMethod removes the method for the dispatch value if present.
+func (mm *MultiMethod) RemoveMethod(dispatchVal Value) {
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+
+	var methods []method
+	for _, m := range mm.methods {
+		if !equalKeys(m.dispatchVal, dispatchVal) {
+			methods = append(methods, m)
+		}
+	}
+	mm.methods = methods
+}
+
+// PreferMethod causes the method for dispatch value 'x' to be selected
+// over the method for 'y' when both match a dispatch value and neither
+// dispatch value is derived from the other.
+func (mm *MultiMethod) PreferMethod(x, y Value) error {
+	mm.mu.Lock()
+	defer mm.mu.Unlock()
+
+	if mm.prefers(y, x) {
+		return fmt.Errorf("preference conflict in multimethod '%s': '%s' is already preferred to '%s'",
+			mm.Name, y, x)
+	}
+
+	if mm.preferTable == nil {
+		mm.preferTable = &HashMap{}
+	}
+
+	preferred := append(append([]Value(nil), mm.preferredTo(x)...), y)
+	mm.preferTable.put(x, Vector{Values: preferred})
+	return nil
+}
+
+// Invoke evaluates the args, computes the dispatch value and invokes the
+// method selected for the dispatch value.
+func (mm *MultiMethod) Invoke(scope Scope, args ...Value) (Value, error) {
+	vals, err := evalValueList(scope, args)
+	if err != nil {
+		return nil, err
+	}
+
+	dispatchVal, err := Apply(scope, mm.Dispatch, vals)
+	if err != nil {
+		return nil, err
+	}
+
+	fn, err := mm.selectMethod(dispatchVal)
+	if err != nil {
+		return nil, err
+	}
+
+	return applyInPlace(scope, fn, vals)
+}
+
+// selectMethod returns the method for the dispatch value. A method with an
+// equal dispatch value is preferred. Otherwise, of all the methods whose
+// dispatch value the given value derives from, the one which dominates (is
+// derived from or preferred to) all others is selected.
+func (mm *MultiMethod) selectMethod(dispatchVal Value) (Invokable, error) {
+	mm.mu.RLock()
+	defer mm.mu.RUnlock()
+
+	var best *method
+	for i, m := range mm.methods {
+		if equalKeys(m.dispatchVal, dispatchVal) {
+			return m.fn, nil
+		}
+
+		if !mm.Hierarchy.IsA(dispatchVal, m.dispatchVal) {
+			continue
+		}
+
+		if best == nil || mm.dominates(m.dispatchVal, best.dispatchVal) {
+			best = &mm.methods[i]
+		}
+
+		if !mm.dominates(best.dispatchVal, m.dispatchVal) {
+			return nil, fmt.Errorf("multiple methods in multimethod '%s' match dispatch value '%s': '%s' and '%s', and neither is preferred",
+				mm.Name, dispatchVal, best.dispatchVal, m.dispatchVal)
+		}
+	}
+
+	if best != nil {
+		return best.fn, nil
+	}
+
+	for _, m := range mm.methods {
+		if equalKeys(m.dispatchVal, mm.Default) {
+			return m.fn, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no method in multimethod '%s' for dispatch value '%s'",
+		mm.Name, dispatchVal)
+}
+
+func (mm *MultiMethod) dominates(x, y Value) bool {
+	return mm.prefers(x, y) || mm.Hierarchy.IsA(x, y)
+}
+
+// prefers returns true if 'x' or any of its ancestors is preferred to 'y' or
+// any of its ancestors. Must be called with the lock held.
+func (mm *MultiMethod) prefers(x, y Value) bool {
+	for _, v := range mm.preferredTo(x) {
+		if equalKeys(v, y) {
+			return true
+		}
+	}
+
+	for _, p := range mm.Hierarchy.Parents(y) {
+		if mm.prefers(x, p) {
+			return true
+		}
+	}
+
+	for _, p := range mm.Hierarchy.Parents(x) {
+		if mm.prefers(p, y) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// preferredTo returns the values to which 'x' is preferred directly. Must be
+// called with the lock held.
+func (mm *MultiMethod) preferredTo(x Value) []Value {
+	preferred, found := mm.preferTable.Get(x)
+	if !found {
+		return nil
+	}
+
+	return preferred.(Vector).Values
+}
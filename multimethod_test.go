@@ -0,0 +1,96 @@
+package sabre_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/spy16/sabre"
+)
+
+func TestMultiMethod(t *testing.T) {
+	t.Parallel()
+
+	h := sabre.NewHierarchy()
+	if err := h.Derive(sabre.Keyword("circle"), sabre.Keyword("shape")); err != nil {
+		t.Fatalf("Derive() unexpected error: %v", err)
+	}
+
+	mm := sabre.NewMultiMethod("area", sabre.Keyword("kind"), h)
+	mm.AddMethod(sabre.Keyword("square"), sabre.ValueOf(func(m *sabre.HashMap) string {
+		return "square"
+	}).(sabre.Invokable))
+	mm.AddMethod(sabre.Keyword("shape"), sabre.ValueOf(func(m *sabre.HashMap) string {
+		return "shape"
+	}).(sabre.Invokable))
+
+	scope := sabre.NewScope(nil)
+	_ = scope.Bind("area", mm)
+
+	table := []struct {
+		src     string
+		want    sabre.Value
+		wantErr bool
+	}{
+		{src: `(area {:kind :square})`, want: sabre.String("square")},
+		{src: `(area {:kind :circle})`, want: sabre.String("shape")},
+		{src: `(area {:kind :line})`, wantErr: true},
+	}
+
+	for _, tt := range table {
+		got, err := sabre.ReadEvalStr(scope, tt.src)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ReadEvalStr(%s) error = %v, wantErr %v", tt.src, err, tt.wantErr)
+			continue
+		}
+
+		if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ReadEvalStr(%s) got = %v, want %v", tt.src, got, tt.want)
+		}
+	}
+}
+
+func TestHierarchy(t *testing.T) {
+	t.Parallel()
+
+	a, b, c := sabre.Keyword("a"), sabre.Keyword("b"), sabre.Keyword("c")
+
+	h := sabre.NewHierarchy()
+	_ = h.Derive(b, a)
+	_ = h.Derive(c, b)
+
+	if !h.IsA(c, a) || h.IsA(a, c) {
+		t.Errorf("IsA() reports wrong relationship between %s and %s", c, a)
+	}
+
+	if err := h.Derive(a, c); err == nil {
+		t.Errorf("Derive() expected error for cyclic derivation")
+	}
+
+	if err := h.Derive(a, a); err == nil {
+		t.Errorf("Derive() expected error for deriving from itself")
+	}
+
+	if got := h.Ancestors(c); !reflect.DeepEqual(got, []sabre.Value{b, a}) {
+		t.Errorf("Ancestors() got = %v, want [%s %s]", got, b, a)
+	}
+
+	h.Underive(c, b)
+	if h.IsA(c, a) {
+		t.Errorf("IsA() got = true after Underive()")
+	}
+
+	m1, _ := sabre.NewHashMap(a, sabre.Int64(1), b, sabre.Int64(2))
+	m2, _ := sabre.NewHashMap(b, sabre.Int64(2), a, sabre.Int64(1))
+	if err := h.Derive(m1, a); err != nil {
+		t.Fatalf("Derive() unexpected error: %v", err)
+	}
+
+	if !h.IsA(m2, a) || !reflect.DeepEqual(h.Parents(m2), []sabre.Value{a}) {
+		t.Errorf("IsA() must compare maps by value")
+	}
+
+	var empty *sabre.Hierarchy
+	if !empty.IsA(a, a) || empty.IsA(b, a) {
+		t.Errorf("IsA() on nil hierarchy must check only equality")
+	}
+}
@@ -0,0 +1,143 @@
+package sabre
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// Display returns the human readable representation of the value. Unlike
+// String(), strings and characters (including the ones within collections)
+// are printed as is without quotes or escape sequences. Output of Display
+// is not meant to be read back by the Reader.
+func Display(v Value) string {
+	switch val := v.(type) {
+	case String:
+		return string(val)
+
+	case Character:
+		return string(rune(val))
+
+	case *HashMap:
+		parts := make([]string, val.Size())
+		for i, e := range val.items() {
+			parts[i] = Display(e.key) + " " + Display(e.val)
+		}
+		return "{" + strings.Join(parts, ", ") + "}"
+	}
+
+	open, close, items, isContainer := containerParts(v)
+	if !isContainer {
+		return v.String()
+	}
+
+	parts := make([]string, len(items))
+	for i, item := range items {
+		parts[i] = Display(item)
+	}
+
+	return open + strings.Join(parts, " ") + close
+}
+
+// Pretty returns the readable representation of the value (i.e., same as
+// String()) formatted to fit within 'width' columns where possible. Nested
+// collections which do not fit are printed with one element per line unless
+// they contain only atoms, in which case as many elements as possible are
+// printed on each line. Maps are printed with one entry per line.
+func Pretty(v Value, width int) string {
+	var sb strings.Builder
+	pretty(&sb, v, 0, width)
+	return sb.String()
+}
+
+func pretty(sb *strings.Builder, v Value, col, width int) {
+	flat := v.String()
+
+	open, close, items, isContainer := containerParts(v)
+	if !isContainer || len(items) == 0 || col+utf8.RuneCountInString(flat) <= width {
+		sb.WriteString(flat)
+		return
+	}
+
+	sb.WriteString(open)
+	indent := col + utf8.RuneCountInString(open)
+
+	if hm, isMap := v.(*HashMap); isMap {
+		// one entry per line with the value following the key.
+		for i, e := range hm.items() {
+			if i > 0 {
+				sb.WriteString(",\n")
+				sb.WriteString(strings.Repeat(" ", indent))
+			}
+
+			key := e.key.String()
+			sb.WriteString(key + " ")
+			pretty(sb, e.val, indent+utf8.RuneCountInString(key)+1, width)
+		}
+		sb.WriteString(close)
+		return
+	}
+
+	if !hasContainers(items) {
+		// fill the lines when there are no nested collections.
+		col = indent
+		for i, item := range items {
+			s := item.String()
+			n := utf8.RuneCountInString(s)
+
+			if i > 0 {
+				if col+1+n > width {
+					sb.WriteString("\n")
+					sb.WriteString(strings.Repeat(" ", indent))
+					col = indent
+				} else {
+					sb.WriteString(" ")
+					col++
+				}
+			}
+
+			sb.WriteString(s)
+			col += n
+		}
+		sb.WriteString(close)
+		return
+	}
+
+	for i, item := range items {
+		if i > 0 {
+			sb.WriteString("\n")
+			sb.WriteString(strings.Repeat(" ", indent))
+		}
+		pretty(sb, item, indent, width)
+	}
+	sb.WriteString(close)
+}
+
+func hasContainers(items []Value) bool {
+	for _, item := range items {
+		if _, _, _, isContainer := containerParts(item); isContainer {
+			return true
+		}
+	}
+	return false
+}
+
+// containerParts returns the delimiters and the items of the collection
+// types that are printed element by element. Items of a map are its keys
+// and values.
+func containerParts(v Value) (open, close string, items []Value, ok bool) {
+	switch val := v.(type) {
+	case *List:
+		return "(", ")", val.Values, true
+
+	case Vector:
+		return "[", "]", val.Values, true
+
+	case Set:
+		return "#{", "}", val.Values, true
+
+	case *HashMap:
+		return "{", "}", val.kvs(), true
+	}
+
+	return "", "", nil, false
+}
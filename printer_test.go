@@ -0,0 +1,188 @@
+package sabre_test
+
+import (
+	"math/big"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/spy16/sabre"
+)
+
+func TestDisplay(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		name  string
+		value sabre.Value
+		want  string
+	}{
+		{
+			name:  "String",
+			value: sabre.String("say \"hi\"\n"),
+			want:  "say \"hi\"\n",
+		},
+		{
+			name:  "Character",
+			value: sabre.Character('\n'),
+			want:  "\n",
+		},
+		{
+			name:  "Number",
+			value: sabre.Float64(2),
+			want:  "2.0",
+		},
+		{
+			name: "Nested",
+			value: sabre.Vector{Values: []sabre.Value{
+				sabre.String("a"),
+				&sabre.List{Values: []sabre.Value{sabre.Character('b'), sabre.Keyword("c")}},
+				sabre.Set{Values: []sabre.Value{sabre.String("d")}},
+			}},
+			want: "[a (b :c) #{d}]",
+		},
+	}
+
+	for _, tt := range table {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sabre.Display(tt.value); got != tt.want {
+				t.Errorf("Display() got = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPretty(t *testing.T) {
+	t.Parallel()
+
+	nested := sabre.Vector{Values: []sabre.Value{
+		sabre.Keyword("alpha"),
+		&sabre.List{Values: []sabre.Value{
+			sabre.String("beta"), sabre.String("gamma"), sabre.String("delta"),
+		}},
+		sabre.Set{Values: []sabre.Value{sabre.Int64(1)}},
+	}}
+
+	table := []struct {
+		name  string
+		value sabre.Value
+		width int
+		want  string
+	}{
+		{
+			name:  "Fits",
+			value: nested,
+			width: 80,
+			want:  `[:alpha ("beta" "gamma" "delta") #{1}]`,
+		},
+		{
+			name:  "OuterBroken",
+			value: nested,
+			width: 30,
+			want: `[:alpha
+ ("beta" "gamma" "delta")
+ #{1}]`,
+		},
+		{
+			name:  "AllBroken",
+			value: nested,
+			width: 10,
+			want: `[:alpha
+ ("beta"
+  "gamma"
+  "delta")
+ #{1}]`,
+		},
+		{
+			name: "Fill",
+			value: &sabre.List{Values: []sabre.Value{
+				sabre.Int64(100), sabre.Int64(200), sabre.Int64(300),
+				sabre.Int64(400), sabre.Int64(500),
+			}},
+			width: 12,
+			want: `(100 200 300
+ 400 500)`,
+		},
+		{
+			name:  "Atom",
+			value: sabre.String("a long string that does not fit"),
+			width: 10,
+			want:  `"a long string that does not fit"`,
+		},
+	}
+
+	for _, tt := range table {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sabre.Pretty(tt.value, tt.width); got != tt.want {
+				t.Errorf("Pretty() got = \n%s\nwant = \n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrint_RoundTrip(t *testing.T) {
+	t.Parallel()
+
+	values := []sabre.Value{
+		sabre.Nil{},
+		sabre.Bool(true),
+		sabre.Int64(-42),
+		sabre.BigInt{Int: new(big.Int).Lsh(big.NewInt(1), 100)},
+		sabre.Float64(3.1412),
+		sabre.Float64(10),
+		sabre.Float64(1e-9),
+		sabre.Float64(-2.5e300),
+		sabre.Float64(0.1 + 0.2),
+		sabre.String(""),
+		sabre.String("tab\tquote\" backslash\\ newline\n bell\a"),
+		sabre.String("unicode λ ¥"),
+		sabre.String("form\ffeed escape\x1b del\x7f"),
+		sabre.Character('a'),
+		sabre.Character('λ'),
+		sabre.Character(' '),
+		sabre.Character('\n'),
+		sabre.Character('\t'),
+		sabre.Character(0),
+		sabre.Character('\\'),
+		sabre.Keyword("key"),
+		sabre.Regex{Regexp: regexp.MustCompile(`\d+\s\"x\"`)},
+		sabre.Time{Time: time.Date(2020, 1, 18, 10, 20, 30, 500, time.UTC)},
+		sabre.Duration(90*time.Minute + 500*time.Millisecond),
+		sabre.UUID{0xf8, 0x1d, 0x4f, 0xae, 0x7d, 0xec, 0x11, 0xd0, 0xa7, 0x65, 0x00, 0xa0, 0xc9, 0x1e, 0x6b, 0xf6},
+	}
+
+	for _, v := range values {
+		src := v.String()
+
+		got, err := sabre.NewReader(strings.NewReader(src)).One()
+		if err != nil {
+			t.Errorf("One(%s) unexpected error: %v", src, err)
+			continue
+		}
+
+		if !reflect.DeepEqual(got, v) {
+			t.Errorf("One(%s) got = %#v, want %#v", src, got, v)
+		}
+	}
+
+	vec := sabre.Vector{Values: values}
+	got, err := sabre.NewReader(strings.NewReader(vec.String())).One()
+	if err != nil {
+		t.Fatalf("One() unexpected error: %v", err)
+	}
+
+	items := got.(sabre.Vector).Values
+	if len(items) != len(values) {
+		t.Fatalf("One() got %d items, want %d", len(items), len(values))
+	}
+
+	for i, v := range items {
+		if !reflect.DeepEqual(v, values[i]) {
+			t.Errorf("One() item %d got = %#v, want %#v", i, v, values[i])
+		}
+	}
+}
@@ -0,0 +1,560 @@
+package sabre
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Compile performs macro expansion, special form analysis and symbol
+// resolution checks on the form and returns a Program which can be executed
+// any number of times without repeating the analysis. Symbols are resolved
+// against the given scope and must either be bound in the scope or be defined
+// by the form using def. Macros bound in the scope are expanded once during
+// compilation.
+func Compile(scope Scope, form Value, opts ...CompileOption) (*Program, error) {
+	prog := &Program{}
+	for _, opt := range opts {
+		opt(prog)
+	}
+
+	w := newWalker(scope, true)
+
+	expanded, err := w.walk(form, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(w.errs) > 0 {
+		return nil, w.errs[0]
+	}
+
+	switch prog.backend {
+	case Closure:
+		prog.form = expanded
+		prog.code, prog.nslots, err = compileClosure(scope, expanded)
+
+	case Bytecode:
+		prog.form = expanded
+		prog.proto, err = compileBytecode(scope, expanded)
+
+	default:
+		prog.form, err = analyze(scope, expanded)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return prog, nil
+}
+
+// ReadCompile consumes data from reader 'r' till EOF, parses into forms and
+// compiles all the forms obtained into a Program.
+func ReadCompile(scope Scope, r io.Reader, opts ...CompileOption) (*Program, error) {
+	mod, err := NewReader(r).All()
+	if err != nil {
+		return nil, err
+	}
+
+	return Compile(scope, mod, opts...)
+}
+
+// Backend represents the strategy used for executing a compiled Program.
+type Backend int
+
+// Backends supported by Compile.
+const (
+	// TreeWalk executes the program by evaluating the analyzed forms. This
+	// is the strategy used by Eval.
+	TreeWalk Backend = iota
+
+	// Closure compiles the program into Go closures. Local bindings are
+	// accessed using slot indices instead of scope lookups.
+	Closure
+
+	// Bytecode compiles the program into bytecode which is executed by a
+	// stack based virtual machine. Calls in tail position do not grow the
+	// stack (See Program.Disassemble).
+	Bytecode
+)
+
+// CompileOption can be passed to Compile to customize the Program.
+type CompileOption func(prog *Program)
+
+// WithBackend sets the backend used for executing the Program.
+func WithBackend(backend Backend) CompileOption {
+	return func(prog *Program) {
+		prog.backend = backend
+	}
+}
+
+// Program represents a compiled form. A Program is immutable and can be run
+// concurrently from multiple goroutines.
+type Program struct {
+	backend Backend
+	form    Value
+
+	code   code
+	nslots int
+
+	proto *funcProto
+}
+
+// Run executes the program against the scope and returns the result. The
+// context is associated with the evaluation (See WithContext).
+func (p *Program) Run(ctx context.Context, scope Scope) (Value, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if scope == nil {
+		scope = NewScope(nil)
+	}
+	scope = WithContext(ctx, scope)
+
+	var v Value
+	var err error
+
+	switch {
+	case p.proto != nil:
+		v, err = (&vmMethod{proto: p.proto, global: scope}).call(nil, nil)
+
+	case p.code != nil:
+		v, err = p.code(&frame{
+			slots:  make([]Value, p.nslots),
+			global: scope,
+		})
+
+	default:
+		return evalAnalyzed(scope, p.form)
+	}
+
+	if err != nil {
+		if _, ok := err.(EvalError); ok {
+			return v, err
+		}
+
+		return v, EvalError{
+			Position: getPosition(p.form),
+			Form:     p.form,
+			Cause:    err,
+		}
+	}
+
+	return v, nil
+}
+
+func (p *Program) String() string {
+	return p.form.String()
+}
+
+// Disassemble writes a human readable listing of the bytecode instructions
+// of the program to 'w'. Program must be compiled using Bytecode backend.
+func (p *Program) Disassemble(w io.Writer) error {
+	if p.proto == nil {
+		return errors.New("program is not compiled to bytecode")
+	}
+
+	return disassemble(w, p.proto)
+}
+
+func newWalker(scope Scope, expand bool) *walker {
+	return &walker{
+		scope:  scope,
+		expand: expand,
+	}
+}
+
+// walker walks a form while keeping track of the local bindings introduced
+// by special forms. It reports symbols that cannot be resolved and expands
+// macros if enabled.
+type walker struct {
+	scope   Scope
+	expand  bool
+	globals map[string]struct{}
+	errs    []error
+}
+
+// walk returns the form with all macros expanded (if enabled). Errors that
+// don't prevent walking further are collected in w.errs.
+func (w *walker) walk(form Value, locals *localEnv) (Value, error) {
+	if w.globals == nil {
+		w.globals = map[string]struct{}{}
+		collectDefs(form, w.globals)
+	}
+
+	switch v := form.(type) {
+	case Symbol:
+		w.checkSymbol(v, locals)
+		return v, nil
+
+	case Module:
+		vals, err := w.walkList(v, locals)
+		return Module(vals), err
+
+	case Vector:
+		vals, err := w.walkList(v.Values, locals)
+		return Vector{Values: vals, Position: v.Position, meta: v.meta}, err
+
+	case Set:
+		vals, err := w.walkList(v.Values, locals)
+		return Set{Values: vals, Position: v.Position, meta: v.meta}, err
+
+	case *HashMap:
+		kvs, err := w.walkList(v.kvs(), locals)
+		return v.withEntries(kvs), err
+
+	case *List:
+		return w.walkInvocation(v, locals)
+
+	default:
+		return form, nil
+	}
+}
+
+func (w *walker) walkInvocation(lf *List, locals *localEnv) (Value, error) {
+	if lf.Size() == 0 {
+		return lf, nil
+	}
+
+	sym, isSymbol := lf.Values[0].(Symbol)
+	if isSymbol && !locals.has(sym.Value) {
+		if getSpecial(sym) != nil {
+			return w.walkSpecial(lf, sym.Value, locals)
+		}
+
+		if macro, isMacro := w.resolveMacro(sym.Value); isMacro {
+			expanded, err := macro.expand(w.scope, lf.Values[1:])
+			if err != nil {
+				return nil, EvalError{
+					Position: lf.Position,
+					Cause:    err,
+					Form:     lf,
+				}
+			}
+
+			expanded = positionExpansion(expanded, lf.Position)
+			collectDefs(expanded, w.globals)
+			return w.walk(expanded, locals)
+		}
+	}
+
+	vals, err := w.walkList(lf.Values, locals)
+	if err != nil {
+		return nil, err
+	}
+
+	return &List{Values: vals, Position: lf.Position}, nil
+}
+
+func (w *walker) walkSpecial(lf *List, name string, locals *localEnv) (Value, error) {
+	args := lf.Values[1:]
+	res := []Value{lf.Values[0]}
+
+	switch name {
+	case "quote":
+		return lf, nil
+
+	case "syntax-quote":
+		if len(args) > 0 {
+			w.walkUnquoted(args[0], locals)
+		}
+		return lf, nil
+
+	case "fn*", "λ":
+		walked, err := w.walkFn(args, locals)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, walked...)
+
+	case "let*":
+		walked, err := w.walkLet(args, locals)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, walked...)
+
+	case "def":
+		if len(args) == 0 {
+			return lf, nil
+		}
+
+		walked, err := w.walkList(args[1:], locals)
+		if err != nil {
+			return nil, err
+		}
+		res = append(append(res, args[0]), walked...)
+
+	default:
+		walked, err := w.walkList(args, locals)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, walked...)
+	}
+
+	return &List{Values: res, Position: lf.Position}, nil
+}
+
+// walkFn walks the args of (fn* name? [arg*] expr*) or (fn* name? ([arg*]
+// expr*)+) forms.
+func (w *walker) walkFn(args []Value, locals *localEnv) ([]Value, error) {
+	var res []Value
+
+	if len(args) > 0 {
+		if name, isName := args[0].(Symbol); isName {
+			res = append(res, name)
+			args = args[1:]
+		}
+	}
+
+	if len(args) == 0 {
+		return res, nil
+	}
+
+	if _, isList := args[0].(*List); !isList {
+		walked, err := w.walkMethod(args, locals)
+		return append(res, walked...), err
+	}
+
+	for _, arg := range args {
+		spec, isList := arg.(*List)
+		if !isList {
+			res = append(res, arg)
+			continue
+		}
+
+		walked, err := w.walkMethod(spec.Values, locals)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, &List{Values: walked, Position: spec.Position})
+	}
+
+	return res, nil
+}
+
+func (w *walker) walkMethod(spec []Value, locals *localEnv) ([]Value, error) {
+	if len(spec) == 0 {
+		return spec, nil
+	}
+
+	var names []string
+	if vec, isVector := spec[0].(Vector); isVector {
+		for _, arg := range vec.Values {
+			if sym, isSymbol := arg.(Symbol); isSymbol && sym.Value != "&" {
+				names = append(names, sym.Value)
+			}
+		}
+	}
+
+	body, err := w.walkList(spec[1:], locals.child(names...))
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]Value{spec[0]}, body...), nil
+}
+
+// walkLet walks the args of (let* [binding*] expr*) form.
+func (w *walker) walkLet(args []Value, locals *localEnv) ([]Value, error) {
+	if len(args) == 0 {
+		return args, nil
+	}
+
+	vec, isVector := args[0].(Vector)
+	if !isVector {
+		return w.walkList(args, locals)
+	}
+
+	letLocals := locals.child()
+	bindings := make([]Value, len(vec.Values))
+	for i := 0; i < len(vec.Values); i += 2 {
+		bindings[i] = vec.Values[i]
+		if i+1 >= len(vec.Values) {
+			break
+		}
+
+		expr, err := w.walk(vec.Values[i+1], letLocals)
+		if err != nil {
+			return nil, err
+		}
+		bindings[i+1] = expr
+
+		if sym, isSymbol := vec.Values[i].(Symbol); isSymbol {
+			letLocals = letLocals.child(sym.Value)
+		}
+	}
+
+	body, err := w.walkList(args[1:], letLocals)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]Value{Vector{Values: bindings, Position: vec.Position}}, body...), nil
+}
+
+// walkUnquoted checks the forms which are unquoted within a syntax-quote
+// form and will be evaluated.
+func (w *walker) walkUnquoted(form Value, locals *localEnv) {
+	var vals []Value
+
+	switch v := form.(type) {
+	case *List:
+		if isUnquote(v.Values) {
+			_, _ = w.walkList(v.Values[1:], locals)
+			return
+		}
+		vals = v.Values
+
+	case Vector:
+		vals = v.Values
+
+	case Set:
+		vals = v.Values
+
+	case *HashMap:
+		vals = v.kvs()
+	}
+
+	for _, v := range vals {
+		w.walkUnquoted(v, locals)
+	}
+}
+
+func (w *walker) walkList(forms []Value, locals *localEnv) ([]Value, error) {
+	if forms == nil {
+		return nil, nil
+	}
+
+	res := make([]Value, len(forms))
+	for i, form := range forms {
+		v, err := w.walk(form, locals)
+		if err != nil {
+			return nil, err
+		}
+		res[i] = v
+	}
+
+	return res, nil
+}
+
+func (w *walker) checkSymbol(sym Symbol, locals *localEnv) {
+	if locals.has(sym.Value) {
+		return
+	}
+
+	if _, found := w.globals[sym.Value]; found {
+		return
+	}
+
+	if w.scope != nil {
+		if _, err := w.scope.Resolve(sym.Value); err == nil {
+			return
+		}
+	}
+
+	w.errs = append(w.errs, EvalError{
+		Position: sym.Position,
+		Cause:    fmt.Errorf("unable to resolve symbol: %v", sym.Value),
+		Form:     sym,
+	})
+}
+
+func (w *walker) resolveMacro(name string) (MultiFn, bool) {
+	if !w.expand || w.scope == nil {
+		return MultiFn{}, false
+	}
+
+	v, err := w.scope.Resolve(name)
+	if err != nil {
+		return MultiFn{}, false
+	}
+
+	multiFn, isMultiFn := v.(MultiFn)
+	return multiFn, isMultiFn && multiFn.IsMacro
+}
+
+// positionExpansion returns the expansion of a macro with the lists that were
+// generated by the macro (i.e., lists without a position) positioned at the
+// macro call. Lists are copied instead of modified since the macro may return
+// the same form for every call.
+func positionExpansion(form Value, pos Position) Value {
+	lf, isList := form.(*List)
+	if !isList || lf.Position != (Position{}) {
+		return form
+	}
+
+	vals := make([]Value, len(lf.Values))
+	for i, v := range lf.Values {
+		vals[i] = positionExpansion(v, pos)
+	}
+
+	return &List{Values: vals, Position: pos, meta: lf.meta}
+}
+
+// collectDefs collects names of all the symbols defined using def within
+// the form.
+func collectDefs(form Value, names map[string]struct{}) {
+	var vals []Value
+
+	switch v := form.(type) {
+	case Module:
+		vals = v
+
+	case Vector:
+		vals = v.Values
+
+	case Set:
+		vals = v.Values
+
+	case *HashMap:
+		vals = v.kvs()
+
+	case *List:
+		vals = v.Values
+
+		if len(vals) > 1 {
+			head, isSymbol := vals[0].(Symbol)
+			name, isName := vals[1].(Symbol)
+			if isSymbol && isName && head.Value == "def" {
+				names[name.Value] = struct{}{}
+			}
+		}
+	}
+
+	for _, v := range vals {
+		collectDefs(v, names)
+	}
+}
+
+// localEnv represents a set of local bindings introduced by fn* or let*.
+type localEnv struct {
+	parent *localEnv
+	names  []string
+}
+
+func (env *localEnv) child(names ...string) *localEnv {
+	return &localEnv{parent: env, names: names}
+}
+
+func (env *localEnv) has(name string) bool {
+	return env.lookup(name) != nil
+}
+
+// lookup returns the innermost env which binds the name or nil if the name
+// is not bound locally.
+func (env *localEnv) lookup(name string) *localEnv {
+	for e := env; e != nil; e = e.parent {
+		for _, n := range e.names {
+			if n == name {
+				return e
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,485 @@
+package sabre_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/spy16/sabre"
+)
+
+func TestCompile(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		name           string
+		src            string
+		getScope       func() sabre.Scope
+		want           sabre.Value
+		wantCompileErr bool
+		wantRunErr     bool
+	}{
+		{
+			name: "Empty",
+			src:  "",
+			want: sabre.Nil{},
+		},
+		{
+			name: "Program",
+			src:  sampleProgram,
+			want: sabre.Float64(3.1412),
+		},
+		{
+			name:           "UnresolvedSymbol",
+			src:            `(def f (fn* [a] (g a)))`,
+			wantCompileErr: true,
+		},
+		{
+			name:           "UnresolvedSymbolInLet",
+			src:            `(let* [a 1 b c] a)`,
+			wantCompileErr: true,
+		},
+		{
+			name: "ForwardReference",
+			src:  `(def f (fn* [a] (g a))) (def g (fn* [b] b)) (f 10)`,
+			want: sabre.Int64(10),
+		},
+		{
+			name: "Locals",
+			src:  `(let* [a 1 b a] ((fn* self ([] b) ([x & rest] rest)) a b))`,
+			want: &sabre.List{Values: []sabre.Value{sabre.Int64(1)}},
+		},
+		{
+			name: "Quoted",
+			src:  `(def a '(undefined symbols)) :ok`,
+			want: sabre.Keyword("ok"),
+		},
+		{
+			name:           "SyntaxQuoted",
+			src:            "`(a ~b)",
+			wantCompileErr: true,
+		},
+		{
+			name: "ScopeBindings",
+			getScope: func() sabre.Scope {
+				scope := sabre.NewScope(nil)
+				_ = scope.BindGo("inc", func(i sabre.Int64) sabre.Int64 { return i + 1 })
+				return scope
+			},
+			src:  `(inc 1)`,
+			want: sabre.Int64(2),
+		},
+		{
+			name:       "RunError",
+			src:        `(throw "failed")`,
+			wantRunErr: true,
+		},
+		{
+			name:       "ArityError",
+			src:        `((fn* [a] a))`,
+			wantRunErr: true,
+		},
+		{
+			name:       "InvokeNonInvokable",
+			src:        `(1 2)`,
+			wantRunErr: true,
+		},
+		{
+			name: "Closures",
+			src:  `(def pair (fn* [a] (fn* [b] (fn* [c] [a b c])))) (((pair 1) 2) 3)`,
+			want: sabre.Vector{Values: []sabre.Value{sabre.Int64(1), sabre.Int64(2), sabre.Int64(3)}},
+		},
+		{
+			name: "LetShadowing",
+			src:  `(let* [a 1 b [a] a 2] [a b (let* [a 3] a) a])`,
+			want: sabre.Vector{Values: []sabre.Value{
+				sabre.Int64(2),
+				sabre.Vector{Values: []sabre.Value{sabre.Int64(1)}},
+				sabre.Int64(3),
+				sabre.Int64(2),
+			}},
+		},
+		{
+			name: "MultiArity",
+			src:  `(def f (fn* ([] :zero) ([a] a) ([a & r] r))) [(f) (f 1) (f 1 2 3)]`,
+			want: sabre.Vector{Values: []sabre.Value{
+				sabre.Keyword("zero"),
+				sabre.Int64(1),
+				&sabre.List{Values: []sabre.Value{sabre.Int64(2), sabre.Int64(3)}},
+			}},
+		},
+		{
+			name: "VariadicNoArgs",
+			src:  `((fn* [& r] r))`,
+			want: &sabre.List{},
+		},
+		{
+			name: "DefInFn",
+			src:  `(def f (fn* [v] (def g v))) (f :x) g`,
+			want: sabre.Keyword("x"),
+		},
+		{
+			name: "SyntaxQuoteLocals",
+			src:  "(let* [a 1] `[:a ~a #{~(do a)}])",
+			want: sabre.Vector{Values: []sabre.Value{
+				sabre.Keyword("a"),
+				sabre.Int64(1),
+				sabre.Set{Values: []sabre.Value{sabre.Int64(1)}},
+			}},
+		},
+		{
+			name: "GoFuncSeesLocals",
+			getScope: func() sabre.Scope {
+				scope := sabre.NewScope(nil)
+				_ = scope.Bind("eval", sabre.GoFunc(func(scope sabre.Scope, args []sabre.Value) (sabre.Value, error) {
+					form, err := args[0].Eval(scope)
+					if err != nil {
+						return nil, err
+					}
+
+					return sabre.Eval(scope, form)
+				}))
+				return scope
+			},
+			src:  `(let* [a 10] ((fn* [b] (eval '[a b])) 20))`,
+			want: sabre.Vector{Values: []sabre.Value{sabre.Int64(10), sabre.Int64(20)}},
+		},
+		{
+			name:     "GoBlock",
+			getScope: scopeWithTake,
+			src:      `(def f (fn* [a] (let* [b [a]] (go b)))) (take (f 1))`,
+			want:     sabre.Vector{Values: []sabre.Value{sabre.Int64(1)}},
+		},
+		{
+			name: "Delay",
+			getScope: func() sabre.Scope {
+				scope := sabre.NewScope(nil)
+				_ = scope.Bind("force", sabre.GoFunc(func(scope sabre.Scope, args []sabre.Value) (sabre.Value, error) {
+					v, err := args[0].Eval(scope)
+					if err != nil {
+						return nil, err
+					}
+
+					return v.(*sabre.Delay).Deref()
+				}))
+				return scope
+			},
+			src:  `(let* [a 1] (force (delay [a])))`,
+			want: sabre.Vector{Values: []sabre.Value{sabre.Int64(1)}},
+		},
+	}
+
+	for _, tt := range table {
+		for _, be := range backends {
+			t.Run(tt.name+"/"+be.name, func(t *testing.T) {
+				scope := sabre.Scope(sabre.NewScope(nil))
+				if tt.getScope != nil {
+					scope = tt.getScope()
+				}
+
+				prog, err := sabre.ReadCompile(scope, strings.NewReader(tt.src), sabre.WithBackend(be.backend))
+				if (err != nil) != tt.wantCompileErr {
+					t.Errorf("Compile() error = %v, wantErr %v", err, tt.wantCompileErr)
+					return
+				}
+				if tt.wantCompileErr {
+					return
+				}
+
+				got, err := prog.Run(context.Background(), scope)
+				if (err != nil) != tt.wantRunErr {
+					t.Errorf("Run() error = %v, wantErr %v", err, tt.wantRunErr)
+					return
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("Run() got = %#v, want %#v", got, tt.want)
+				}
+			})
+		}
+	}
+}
+
+func TestCompile_Macro(t *testing.T) {
+	t.Parallel()
+
+	expansions := 0
+	scope := sabre.NewScope(nil)
+	_ = scope.Bind("unless", sabre.MultiFn{
+		Name:    "unless",
+		IsMacro: true,
+		Methods: []sabre.Fn{
+			{
+				Args: []string{"test", "then", "else"},
+				Body: sabre.Module{
+					&sabre.List{Values: []sabre.Value{sabre.Symbol{Value: "count-expansion"}}},
+					&sabre.List{Values: []sabre.Value{
+						sabre.Symbol{Value: "syntax-quote"},
+						&sabre.List{Values: []sabre.Value{
+							sabre.Symbol{Value: "if"},
+							&sabre.List{Values: []sabre.Value{sabre.Symbol{Value: "unquote"}, sabre.Symbol{Value: "test"}}},
+							&sabre.List{Values: []sabre.Value{sabre.Symbol{Value: "unquote"}, sabre.Symbol{Value: "else"}}},
+							&sabre.List{Values: []sabre.Value{sabre.Symbol{Value: "unquote"}, sabre.Symbol{Value: "then"}}},
+						}},
+					}},
+				},
+			},
+		},
+	})
+	_ = scope.BindGo("count-expansion", func() { expansions++ })
+
+	for _, be := range backends {
+		expansions = 0
+
+		prog, err := sabre.ReadCompile(scope, strings.NewReader(`
+(def f (fn* [v] (unless v :no :yes)))
+[(f true) (f false) (let* [unless (fn* [a b c] c)] (unless 1 2 3))]
+`), sabre.WithBackend(be.backend))
+		if err != nil {
+			t.Fatalf("Compile() unexpected error: %v", err)
+		}
+
+		want := sabre.Vector{Values: []sabre.Value{sabre.Keyword("yes"), sabre.Keyword("no"), sabre.Int64(3)}}
+		for i := 0; i < 3; i++ {
+			got, err := prog.Run(context.Background(), sabre.NewScope(scope))
+			if err != nil {
+				t.Fatalf("Run() unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Run() got = %v, want %v", got, want)
+			}
+		}
+
+		if expansions != 1 {
+			t.Errorf("macro expanded %d times with %s backend, want 1", expansions, be.name)
+		}
+	}
+}
+
+func TestProgram_Run(t *testing.T) {
+	t.Parallel()
+
+	scope := sabre.NewScope(nil)
+	_ = scope.BindGo("inc", func(i sabre.Int64) sabre.Int64 { return i + 1 })
+
+	for _, be := range backends {
+		prog, err := sabre.ReadCompile(scope, strings.NewReader(concurrentProgram), sabre.WithBackend(be.backend))
+		if err != nil {
+			t.Fatalf("Compile() unexpected error: %v", err)
+		}
+
+		runConcurrently(t, prog, scope)
+	}
+}
+
+func TestProgram_Run_Context(t *testing.T) {
+	t.Parallel()
+
+	type ctxKey struct{}
+
+	scope := sabre.NewScope(nil)
+	_ = scope.Bind("ctx-value", sabre.GoFunc(func(scope sabre.Scope, _ []sabre.Value) (sabre.Value, error) {
+		return sabre.Context(scope).Value(ctxKey{}).(sabre.Value), nil
+	}))
+
+	for _, be := range backends {
+		prog, err := sabre.ReadCompile(scope, strings.NewReader(`
+(def f (fn* [a] (let* [g (fn* [] (ctx-value))] [a (g)])))
+(f 1)
+`), sabre.WithBackend(be.backend))
+		if err != nil {
+			t.Fatalf("Compile() unexpected error: %v", err)
+		}
+
+		ctx := context.WithValue(context.Background(), ctxKey{}, sabre.Keyword(be.name))
+		got, err := prog.Run(ctx, sabre.NewScope(scope))
+		if err != nil {
+			t.Fatalf("Run() unexpected error: %v", err)
+		}
+
+		want := sabre.Vector{Values: []sabre.Value{sabre.Int64(1), sabre.Keyword(be.name)}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Run() got = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestProgram_Run_Cancelled(t *testing.T) {
+	t.Parallel()
+
+	scope := sabre.NewScope(nil)
+	_ = scope.BindGo("inc", func(i sabre.Int64) sabre.Int64 { return i + 1 })
+	_ = scope.BindGo("deeper?", func(i sabre.Int64) bool { return i < 40 })
+
+	programs := map[string]string{
+		"Recursion": `(def burn (fn* [n] (if (deeper? n) (do (burn (inc n)) (burn (inc n))))))
+(burn 0)`,
+		"TailCall": `(def spin (fn* [n] (spin (inc n))))
+(spin 0)`,
+	}
+
+	for name, src := range programs {
+		for _, be := range backends {
+			prog, err := sabre.ReadCompile(scope, strings.NewReader(src), sabre.WithBackend(be.backend))
+			if err != nil {
+				t.Fatalf("Compile() unexpected error: %v", err)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+			_, err = prog.Run(ctx, sabre.NewScope(scope))
+			cancel()
+
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("%s: Run() with %s backend error = %v, want context.DeadlineExceeded", name, be.name, err)
+			}
+		}
+	}
+}
+
+func TestProgram_Run_ErrorPosition(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		name string
+		src  string
+		want string
+	}{
+		{
+			name: "Throw",
+			src:  "(do\n  (throw \"boom\" 1))",
+			want: "(Line 2, Column 3): boom1",
+		},
+		{
+			name: "SyntaxQuote",
+			src:  "(def x 5) (syntax-quote (a ~x ~@[1 2]))",
+			want: "(Line 1, Column 11): boom",
+		},
+		{
+			name: "NestedCalls",
+			src:  "(def f (fn* [] (deref 1)))\n(def g (fn* [] (f)))\n(do\n  (g) nil)",
+			want: "(Line 4, Column 3): eval error in '<string>' (Line 2, Column 16): " +
+				"eval error in '<string>' (Line 1, Column 16): boom",
+		},
+	}
+
+	for _, tt := range table {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			for _, be := range backends {
+				scope := sabre.NewScope(nil)
+				_ = scope.Bind("deref", sabre.GoFunc(func(_ sabre.Scope, _ []sabre.Value) (sabre.Value, error) {
+					return nil, errors.New("boom")
+				}))
+
+				prog, err := sabre.ReadCompile(scope, strings.NewReader(tt.src), sabre.WithBackend(be.backend))
+				if err != nil {
+					t.Fatalf("Compile() unexpected error: %v", err)
+				}
+
+				_, err = prog.Run(context.Background(), scope)
+				if err == nil || !strings.HasSuffix(err.Error(), tt.want) {
+					t.Errorf("Run() with %s backend error = %v, want suffix '%s'", be.name, err, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestProgram_Disassemble(t *testing.T) {
+	t.Parallel()
+
+	scope := sabre.NewScope(nil)
+	_ = scope.BindGo("inc", func(i sabre.Int64) sabre.Int64 { return i + 1 })
+
+	prog, err := sabre.ReadCompile(scope, strings.NewReader(`
+(def f (fn* f [a & rest]
+  (let* [b (inc a)]
+    (if b (fn* [] [a b]) (f b)))))
+`), sabre.WithBackend(sabre.Bytecode))
+	if err != nil {
+		t.Fatalf("Compile() unexpected error: %v", err)
+	}
+
+	var sb strings.Builder
+	if err := prog.Disassemble(&sb); err != nil {
+		t.Fatalf("Disassemble() unexpected error: %v", err)
+	}
+
+	want := `fn <main> [] locals=0 upvals=0 consts=1
+  0000  CLOSURE          0  ; f
+  0001  DEF              0  ; f
+  0002  RETURN
+
+fn f [a & rest] locals=3 upvals=0 consts=2
+  0000  LOAD_GLOBAL      0  ; inc
+  0001  MACRO_CHECK      0  ; (inc a)
+  0002  LOAD_LOCAL       0  ; a
+  0003  CALL             0  ; (inc a)
+  0004  STORE_LOCAL      2  ; b
+  0005  LOAD_LOCAL       2  ; b
+  0006  JUMP_IF_FALSE    9
+  0007  CLOSURE          0  ; <anonymous>
+  0008  JUMP            13
+  0009  LOAD_GLOBAL      1  ; f
+  0010  MACRO_CHECK      1  ; (f b)
+  0011  LOAD_LOCAL       2  ; b
+  0012  TAIL_CALL        1  ; (f b)
+  0013  RETURN
+
+fn <anonymous> [] locals=0 upvals=3 consts=0
+  0000  LOAD_UPVAL       2  ; a
+  0001  LOAD_UPVAL       0  ; b
+  0002  VECTOR           2
+  0003  RETURN
+`
+	if got := sb.String(); got != want {
+		t.Errorf("Disassemble() got = \n%s\nwant = \n%s", got, want)
+	}
+
+	if err := (&sabre.Program{}).Disassemble(&sb); err == nil {
+		t.Errorf("Disassemble() expecting error for program not compiled to bytecode")
+	}
+}
+
+func runConcurrently(t *testing.T, prog *sabre.Program, scope sabre.Scope) {
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 50; j++ {
+				got, err := prog.Run(context.Background(), sabre.NewScope(scope))
+				if err != nil {
+					t.Errorf("Run() unexpected error: %v", err)
+					return
+				}
+
+				want := sabre.Vector{Values: []sabre.Value{sabre.Int64(2), sabre.Keyword("big")}}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("Run() got = %v, want %v", got, want)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := prog.Run(ctx, sabre.NewScope(scope)); err != context.Canceled {
+		t.Errorf("Run() error = %v, want %v", err, context.Canceled)
+	}
+}
+
+var backends = []struct {
+	name    string
+	backend sabre.Backend
+}{
+	{name: "TreeWalk", backend: sabre.TreeWalk},
+	{name: "Closure", backend: sabre.Closure},
+	{name: "Bytecode", backend: sabre.Bytecode},
+}
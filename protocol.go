@@ -0,0 +1,146 @@
+package sabre
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// NewProtocol returns a new protocol with the given method names and no
+// implementations.
+func NewProtocol(name string, methods ...string) *Protocol {
+	return &Protocol{
+		Name:    name,
+		Methods: methods,
+		impls:   map[interface{}]map[string]Invokable{},
+	}
+}
+
+// Protocol represents a named set of functions which dispatch on the type
+// of their first argument. Protocols can be extended to sabre types, record
+// types and Go types wrapped by ValueOf. All operations on a Protocol are
+// safe for use from multiple goroutines.
+type Protocol struct {
+	Name    string
+	Methods []string
+
+	mu    sync.RWMutex
+	impls map[interface{}]map[string]Invokable
+}
+
+// Eval returns the protocol itself.
+func (p *Protocol) Eval(_ Scope) (Value, error) { return p, nil }
+
+func (p *Protocol) String() string { return p.Name }
+
+// Extend registers implementations of the methods for values of the given
+// type. The type can be a sabre type (e.g., reflect.TypeOf(sabre.Int64(0)))
+// or the type of a Go value wrapped by ValueOf. Implementations must be
+// invokable (e.g., ValueOf(goFunc)) and receive the value as their first
+// argument. Existing implementations of the methods are replaced.
+func (p *Protocol) Extend(rt reflect.Type, impls map[string]Value) error {
+	return p.extend(rt, impls)
+}
+
+// ExtendRecord is same as Extend but registers implementations for the
+// records of the given record type.
+func (p *Protocol) ExtendRecord(rt *RecordType, impls map[string]Value) error {
+	return p.extend(rt, impls)
+}
+
+// Satisfies returns true if the protocol has been extended to the type of
+// the value.
+func (p *Protocol) Satisfies(v Value) bool {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	_, found := p.impls[typeKey(v)]
+	return found
+}
+
+func (p *Protocol) extend(key interface{}, impls map[string]Value) error {
+	methods := map[string]Invokable{}
+	for name, v := range impls {
+		if !p.hasMethod(name) {
+			return fmt.Errorf("'%s' is not a method of protocol '%s'", name, p.Name)
+		}
+
+		fn, isInvokable := v.(Invokable)
+		if !isInvokable {
+			return fmt.Errorf("implementation of '%s' must be invokable, not '%s'",
+				name, reflect.TypeOf(v))
+		}
+
+		methods[name] = fn
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.impls == nil {
+		p.impls = map[interface{}]map[string]Invokable{}
+	}
+
+	if p.impls[key] == nil {
+		p.impls[key] = map[string]Invokable{}
+	}
+
+	for name, fn := range methods {
+		p.impls[key][name] = fn
+	}
+
+	return nil
+}
+
+func (p *Protocol) hasMethod(name string) bool {
+	for _, m := range p.Methods {
+		if m == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (p *Protocol) impl(v Value, name string) (Invokable, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	fn, found := p.impls[typeKey(v)][name]
+	return fn, found
+}
+
+// ProtocolFn represents a method of a protocol. Invoking it calls the
+// implementation registered for the type of the first argument.
+type ProtocolFn struct {
+	Protocol *Protocol
+	Name     string
+}
+
+// Eval returns the protocol method itself.
+func (pf ProtocolFn) Eval(_ Scope) (Value, error) { return pf, nil }
+
+func (pf ProtocolFn) String() string {
+	return fmt.Sprintf("ProtocolFn{name=%s}", pf.Name)
+}
+
+// Invoke evaluates the args and dispatches the call to the implementation
+// for the type of the first argument.
+func (pf ProtocolFn) Invoke(scope Scope, args ...Value) (Value, error) {
+	vals, err := evalValueList(scope, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(vals) == 0 {
+		return nil, fmt.Errorf("wrong number of args (0) to '%s'", pf.Name)
+	}
+
+	fn, found := pf.Protocol.impl(vals[0], pf.Name)
+	if !found {
+		return nil, fmt.Errorf("no implementation of '%s' of protocol '%s' for type '%v'",
+			pf.Name, pf.Protocol.Name, typeKey(vals[0]))
+	}
+
+	return applyInPlace(scope, fn, vals)
+}
@@ -0,0 +1,104 @@
+package sabre_test
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/spy16/sabre"
+)
+
+type server struct {
+	host string
+	port int
+}
+
+func TestRecord(t *testing.T) {
+	t.Parallel()
+
+	point := &sabre.RecordType{Name: "Point", Fields: []string{"x", "y"}}
+
+	p1, err := point.New(sabre.Int64(1), sabre.Int64(2))
+	if err != nil {
+		t.Fatalf("New() unexpected error: %v", err)
+	}
+
+	hm, _ := sabre.NewHashMap(sabre.Keyword("y"), sabre.Int64(2), sabre.Keyword("x"), sabre.Int64(1))
+	p2, err := point.FromMap(hm)
+	if err != nil {
+		t.Fatalf("FromMap() unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(p1, p2) {
+		t.Errorf("records not equal: %s and %s", p1, p2)
+	}
+
+	p3, _ := point.New(sabre.Int64(1), sabre.Int64(3))
+	if reflect.DeepEqual(p1, p3) {
+		t.Errorf("records must not be equal: %s and %s", p1, p3)
+	}
+
+	if want := "#Point {:x 1, :y 2}"; p1.String() != want {
+		t.Errorf("String() got = %s, want %s", p1, want)
+	}
+
+	got, err := sabre.Keyword("y").Invoke(sabre.NewScope(nil), p1)
+	if err != nil || got != sabre.Int64(2) {
+		t.Errorf("Invoke() got = %v, %v, want 2", got, err)
+	}
+
+	if _, err := point.New(sabre.Int64(1)); err == nil {
+		t.Errorf("New() expected error for wrong number of values")
+	}
+}
+
+func TestProtocol(t *testing.T) {
+	t.Parallel()
+
+	proto := sabre.NewProtocol("Addressable", "address")
+	scope := sabre.NewScope(nil)
+	_ = scope.Bind("address", sabre.ProtocolFn{Protocol: proto, Name: "address"})
+	_ = scope.BindGo("srv", &server{host: "localhost", port: 8080})
+	_ = scope.BindGo("other", server{})
+
+	err := proto.Extend(reflect.TypeOf(&server{}), map[string]sabre.Value{
+		"address": sabre.ValueOf(func(s *server) string {
+			return fmt.Sprintf("%s:%d", s.host, s.port)
+		}),
+	})
+	if err != nil {
+		t.Fatalf("Extend() unexpected error: %v", err)
+	}
+
+	got, err := sabre.ReadEvalStr(scope, "(address srv)")
+	if err != nil {
+		t.Fatalf("ReadEvalStr() unexpected error: %v", err)
+	}
+
+	if got != sabre.String("localhost:8080") {
+		t.Errorf("got = %s, want \"localhost:8080\"", got)
+	}
+
+	if _, err := sabre.ReadEvalStr(scope, "(address other)"); err == nil {
+		t.Errorf("ReadEvalStr() expected error for type without implementation")
+	}
+
+	srv, _ := scope.Resolve("srv")
+	if !proto.Satisfies(srv) || proto.Satisfies(sabre.Int64(1)) {
+		t.Errorf("Satisfies() reports wrong types")
+	}
+
+	err = proto.Extend(reflect.TypeOf(sabre.Int64(0)), map[string]sabre.Value{
+		"unknown": sabre.ValueOf(func(n int64) int64 { return n }),
+	})
+	if err == nil {
+		t.Errorf("Extend() expected error for unknown method")
+	}
+
+	err = proto.Extend(reflect.TypeOf(sabre.Int64(0)), map[string]sabre.Value{
+		"address": sabre.Int64(10),
+	})
+	if err == nil {
+		t.Errorf("Extend() expected error for non-invokable implementation")
+	}
+}
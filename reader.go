@@ -33,7 +33,7 @@ var (
 		'\\': '\\',
 		't':  '\t',
 		'a':  '\a',
-		'f':  '\a',
+		'f':  '\f',
 		'r':  '\r',
 		'b':  '\b',
 		'v':  '\v',
@@ -65,6 +65,8 @@ func NewReader(rs io.Reader) *Reader {
 		rs:       bufio.NewReader(rs),
 		macros:   defaultReadTable(),
 		dispatch: defaultDispatchTable(),
+		features: map[Keyword]bool{"sabre": true},
+		tags:     defaultTagTable(),
 	}
 }
 
@@ -77,13 +79,16 @@ type ReaderMacro func(rd *Reader, init rune) (Value, error)
 type Reader struct {
 	File string
 
-	rs          io.RuneReader
-	buf         []rune
-	line, col   int
-	lastCol     int
-	macros      map[rune]ReaderMacro
-	dispatch    map[rune]ReaderMacro
-	dispatching bool
+	rs        io.RuneReader
+	buf       []rune
+	line, col int
+	lastCol   int
+	macros    map[rune]ReaderMacro
+	dispatch  map[rune]ReaderMacro
+	features  map[Keyword]bool
+	tags      map[string]TagHandler
+	inAnonFn  bool
+	keepDups  bool
 }
 
 // All consumes characters from stream until EOF and returns a list of all the
@@ -135,13 +140,6 @@ func (rd *Reader) IsTerminal(r rune) bool {
 		return true
 	}
 
-	if rd.dispatching {
-		_, found := rd.dispatch[r]
-		if found {
-			return true
-		}
-	}
-
 	_, found := rd.macros[r]
 	return found
 }
@@ -167,6 +165,24 @@ func (rd *Reader) SetMacro(init rune, macro ReaderMacro, isDispatch bool) {
 	}
 }
 
+// SetFeatures sets the features used for selecting the branches of reader
+// conditionals (e.g., #?(:sabre form :default other)). The :default branch
+// is selected if none of the features match. By default, only :sabre is
+// set.
+func (rd *Reader) SetFeatures(features ...Keyword) {
+	rd.features = map[Keyword]bool{}
+	for _, f := range features {
+		rd.features[f] = true
+	}
+}
+
+// KeepDuplicates controls whether set literals with duplicate elements are
+// read as is instead of failing with an error. This allows tools such as
+// linters to report the duplicates (See Check) along with other problems.
+func (rd *Reader) KeepDuplicates(keep bool) {
+	rd.keepDups = keep
+}
+
 // NextRune returns next rune from the stream and advances the stream.
 func (rd *Reader) NextRune() (rune, error) {
 	var r rune
@@ -311,14 +327,14 @@ func (rd *Reader) execDispatch() (Value, error) {
 
 	dispatchMacro, found := rd.dispatch[r2]
 	if !found {
-		rd.Unread(r2)
-		return nil, nil
-	}
+		if !unicode.IsLetter(r2) {
+			rd.Unread(r2)
+			return nil, nil
+		}
 
-	rd.dispatching = true
-	defer func() {
-		rd.dispatching = false
-	}()
+		// #tag form
+		dispatchMacro = readTagged
+	}
 
 	form, err := dispatchMacro(rd, r2)
 	if err != nil {
@@ -354,30 +370,272 @@ func readString(rd *Reader, _ rune) (Value, error) {
 		}
 
 		if r == '\\' {
-			r2, err := rd.NextRune()
+			escaped, err := readEscape(rd)
 			if err != nil {
-				if err == io.EOF {
-					return nil, errStringEOF
+				return nil, err
+			}
+			r = escaped
+		} else if r == '"' {
+			break
+		}
+
+		b.WriteRune(r)
+	}
+
+	return String(b.String()), nil
+}
+
+// readRawString reads the #r"..." form. Backslashes have no special meaning
+// in raw strings and the string ends at the first '"'. If the 'r' is not
+// followed by '"', the form is read as a tagged literal.
+func readRawString(rd *Reader, init rune) (Value, error) {
+	if !nextIsQuote(rd) {
+		return readTagged(rd, init)
+	}
+
+	var b strings.Builder
+	for {
+		r, err := rd.NextRune()
+		if err != nil {
+			if err == io.EOF {
+				return nil, errStringEOF
+			}
+
+			return nil, err
+		}
+
+		if r == '"' {
+			return String(b.String()), nil
+		}
+		b.WriteRune(r)
+	}
+}
+
+// readInterpolated reads the #f"..." form. Each {form} in the string is
+// read as a form and the whole string is expanded into (str part...) which
+// evaluates the forms at evaluation time. "{{" and "}}" can be used for
+// literal braces. Escape sequences are same as strings. If the 'f' is not
+// followed by '"', the form is read as a tagged literal.
+func readInterpolated(rd *Reader, init rune) (Value, error) {
+	if !nextIsQuote(rd) {
+		return readTagged(rd, init)
+	}
+
+	var parts []Value
+	var b strings.Builder
+
+	flush := func() {
+		if b.Len() > 0 {
+			parts = append(parts, String(b.String()))
+			b.Reset()
+		}
+	}
+
+	for {
+		r, err := rd.NextRune()
+		if err != nil {
+			if err == io.EOF {
+				return nil, errStringEOF
+			}
+
+			return nil, err
+		}
+
+		switch r {
+		case '"':
+			flush()
+			if len(parts) == 0 {
+				return String(""), nil
+			} else if len(parts) == 1 {
+				if s, isString := parts[0].(String); isString {
+					return s, nil
 				}
+			}
+
+			return &List{
+				Values: append([]Value{Symbol{Value: "str"}}, parts...),
+			}, nil
 
+		case '\\':
+			escaped, err := readEscape(rd)
+			if err != nil {
 				return nil, err
 			}
+			b.WriteRune(escaped)
 
-			// TODO: Support for Unicode escape \uNN format.
+		case '}':
+			if r2, err := rd.NextRune(); err != nil || r2 != '}' {
+				return nil, errors.New("single '}' in interpolated string (use '}}')")
+			}
+			b.WriteRune('}')
 
-			escaped, err := getEscape(r2)
+		case '{':
+			r2, err := rd.NextRune()
+			if err != nil {
+				return nil, errStringEOF
+			}
+
+			if r2 == '{' {
+				b.WriteRune('{')
+				continue
+			}
+			rd.Unread(r2)
+
+			form, err := readInterpolation(rd)
 			if err != nil {
 				return nil, err
 			}
-			r = escaped
-		} else if r == '"' {
-			break
+
+			flush()
+			parts = append(parts, form)
+
+		default:
+			b.WriteRune(r)
+		}
+	}
+}
+
+func readInterpolation(rd *Reader) (Value, error) {
+	form, err := readNextForm(rd, "interpolated string")
+	if err != nil {
+		return nil, err
+	}
+
+	if err := rd.SkipSpaces(); err != nil {
+		return nil, errStringEOF
+	}
+
+	if r, err := rd.NextRune(); err != nil || r != '}' {
+		return nil, errors.New("expecting '}' after form in interpolated string")
+	}
+
+	return form, nil
+}
+
+// nextIsQuote consumes the next rune if it is '"' and returns true.
+func nextIsQuote(rd *Reader) bool {
+	r, err := rd.NextRune()
+	if err != nil {
+		return false
+	}
+
+	if r != '"' {
+		rd.Unread(r)
+		return false
+	}
+
+	return true
+}
+
+// readEscape reads the escape sequence following a '\\' in strings. Apart
+// from the single character escapes (\\n, \\t etc.), unicode (\\u00A5 or
+// \\u{1F600}), hexadecimal (\\xA5) and octal (\\245) escapes are supported.
+func readEscape(rd *Reader) (rune, error) {
+	r, err := rd.NextRune()
+	if err != nil {
+		if err == io.EOF {
+			return -1, errStringEOF
+		}
+
+		return -1, err
+	}
+
+	switch {
+	case r == 'u':
+		r2, err := rd.NextRune()
+		if err != nil {
+			return -1, errStringEOF
+		}
+
+		if r2 == '{' {
+			digits, err := readEscapeDigits(rd, 16, 6, '}')
+			if err != nil {
+				return -1, err
+			}
+			return parseEscape("u{"+digits+"}", digits, 16)
+		}
+
+		rd.Unread(r2)
+		digits, err := readEscapeDigits(rd, 16, 4, -1)
+		if err != nil {
+			return -1, err
+		}
+
+		if len(digits) != 4 {
+			return -1, fmt.Errorf("illegal escape sequence '\\u%s'", digits)
+		}
+		return parseEscape("u"+digits, digits, 16)
+
+	case r == 'x':
+		digits, err := readEscapeDigits(rd, 16, 2, -1)
+		if err != nil {
+			return -1, err
+		}
+
+		if len(digits) != 2 {
+			return -1, fmt.Errorf("illegal escape sequence '\\x%s'", digits)
+		}
+		return parseEscape("x"+digits, digits, 16)
+
+	case r >= '0' && r <= '7':
+		rd.Unread(r)
+		digits, err := readEscapeDigits(rd, 8, 3, -1)
+		if err != nil {
+			return -1, err
+		}
+		return parseEscape(digits, digits, 8)
+	}
+
+	return getEscape(r)
+}
+
+// readEscapeDigits reads up to max digits of the base. If end is not -1,
+// the digits must be terminated by end which is consumed.
+func readEscapeDigits(rd *Reader, base, max int, end rune) (string, error) {
+	var b strings.Builder
+
+	for {
+		r, err := rd.NextRune()
+		if err != nil {
+			return "", errStringEOF
+		}
+
+		if end != -1 && r == end {
+			return b.String(), nil
+		}
+
+		if b.Len() == max || !isDigit(r, base) {
+			if end != -1 {
+				return "", fmt.Errorf("illegal escape sequence: expecting '%c'", end)
+			}
+
+			rd.Unread(r)
+			return b.String(), nil
 		}
 
 		b.WriteRune(r)
 	}
+}
 
-	return String(b.String()), nil
+func parseEscape(seq, digits string, base int) (rune, error) {
+	num, err := strconv.ParseInt(digits, base, 32)
+	if err != nil || num > unicode.MaxRune || (base == 8 && num > 0377) {
+		return -1, fmt.Errorf("illegal escape sequence '\\%s'", seq)
+	}
+
+	return rune(num), nil
+}
+
+func isDigit(r rune, base int) bool {
+	switch {
+	case r >= '0' && r <= '9':
+		return int(r-'0') < base
+
+	case base == 16:
+		return (r >= 'a' && r <= 'f') || (r >= 'A' && r <= 'F')
+	}
+
+	return false
 }
 
 func readNumber(rd *Reader, init rune) (Value, error) {
@@ -407,6 +665,9 @@ func readNumber(rd *Reader, init rune) (Value, error) {
 	case isRadix:
 		return parseRadix(numStr)
 
+	case strings.HasSuffix(numStr, "N"):
+		return parseBigInt(numStr)
+
 	default:
 		v, err := strconv.ParseInt(numStr, 0, 64)
 		if err != nil {
@@ -507,13 +768,208 @@ func readSet(rd *Reader, _ rune) (Value, error) {
 		Values:   forms,
 		Position: pi,
 	}
-	if !set.valid() {
+	if !rd.keepDups && !set.valid() {
 		return nil, errors.New("duplicate value in set")
 	}
 
 	return set, nil
 }
 
+func readHashMap(rd *Reader, _ rune) (Value, error) {
+	pi := rd.Position()
+
+	forms, err := readContainer(rd, '{', '}', "map")
+	if err != nil {
+		return nil, err
+	}
+
+	hm, err := NewHashMap(forms...)
+	if err != nil {
+		return nil, err
+	}
+
+	if hm.Size()*2 != len(forms) {
+		return nil, errors.New("duplicate key in map")
+	}
+
+	hm.Position = pi
+	return hm, nil
+}
+
+// readDiscard reads the form following #_ and discards it.
+func readDiscard(rd *Reader, _ rune) (Value, error) {
+	if _, err := readNextForm(rd, "discarded form"); err != nil {
+		return nil, err
+	}
+
+	return nil, ErrSkip
+}
+
+// readSymbolicValue reads the ##Inf, ##-Inf and ##NaN forms which represent
+// the floating point values that have no literal representation.
+func readSymbolicValue(rd *Reader, _ rune) (Value, error) {
+	token, err := readToken(rd, -1)
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+	case "Inf":
+		return Float64(math.Inf(1)), nil
+
+	case "-Inf":
+		return Float64(math.Inf(-1)), nil
+
+	case "NaN":
+		return Float64(math.NaN()), nil
+
+	default:
+		return nil, fmt.Errorf("unknown symbolic value '##%s'", token)
+	}
+}
+
+// readConditional reads the #?(:feature form ...) form and returns the form
+// of the first feature that is set on the reader (or :default). If none of
+// the features match, the whole form is skipped.
+func readConditional(rd *Reader, _ rune) (Value, error) {
+	if err := rd.SkipSpaces(); err != nil {
+		return nil, errors.New("EOF while reading reader conditional")
+	}
+
+	r, err := rd.NextRune()
+	if err != nil {
+		return nil, err
+	}
+
+	if r == '@' {
+		return nil, errors.New("splicing reader conditionals are not supported")
+	} else if r != '(' {
+		return nil, errors.New("reader conditional body must be a list")
+	}
+
+	forms, err := readContainer(rd, '(', ')', "reader conditional")
+	if err != nil {
+		return nil, err
+	}
+
+	if len(forms)%2 != 0 {
+		return nil, errors.New("reader conditional requires even number of forms")
+	}
+
+	for i := 0; i < len(forms); i += 2 {
+		feature, isKeyword := forms[i].(Keyword)
+		if !isKeyword {
+			return nil, fmt.Errorf("feature must be a keyword, not '%v'", forms[i])
+		}
+
+		if rd.features[feature] || feature == "default" {
+			return forms[i+1], nil
+		}
+	}
+
+	return nil, ErrSkip
+}
+
+// readAnonFn reads the #(...) form and rewrites it as (fn* [%1 ... %n & %&]
+// (...)) where n is the highest numbered arg literal used in the body. %
+// is same as %1 and %& (if used) receives the rest of the args.
+func readAnonFn(rd *Reader, _ rune) (Value, error) {
+	pi := rd.Position()
+
+	if rd.inAnonFn {
+		return nil, errors.New("nested #()s are not allowed")
+	}
+
+	rd.inAnonFn = true
+	defer func() {
+		rd.inAnonFn = false
+	}()
+
+	forms, err := readContainer(rd, '(', ')', "anonymous fn")
+	if err != nil {
+		return nil, err
+	}
+
+	body := &List{Values: forms, Position: pi}
+
+	arity, variadic := 0, false
+	body = rewriteArgLiterals(body, &arity, &variadic).(*List)
+
+	var args []Value
+	for i := 1; i <= arity; i++ {
+		args = append(args, Symbol{Value: "%" + strconv.Itoa(i)})
+	}
+
+	if variadic {
+		args = append(args, Symbol{Value: "&"}, Symbol{Value: "%&"})
+	}
+
+	return &List{
+		Values: []Value{
+			Symbol{Value: "fn*"},
+			Vector{Values: args, Position: pi},
+			body,
+		},
+		Position: pi,
+	}, nil
+}
+
+// rewriteArgLiterals replaces % with %1 in the form and records the highest
+// numbered arg literal in 'arity' and the presence of %& in 'variadic'.
+func rewriteArgLiterals(form Value, arity *int, variadic *bool) Value {
+	switch v := form.(type) {
+	case Symbol:
+		switch {
+		case v.Value == "%":
+			v.Value = "%1"
+			if *arity < 1 {
+				*arity = 1
+			}
+
+		case v.Value == "%&":
+			*variadic = true
+
+		case strings.HasPrefix(v.Value, "%"):
+			n, err := strconv.Atoi(v.Value[1:])
+			if err == nil && n > 0 && v.Value[1] != '0' && n > *arity {
+				*arity = n
+			}
+		}
+		return v
+
+	case *List:
+		return &List{
+			Values:   rewriteArgLiteralsIn(v.Values, arity, variadic),
+			Position: v.Position,
+		}
+
+	case Vector:
+		v.Values = rewriteArgLiteralsIn(v.Values, arity, variadic)
+		return v
+
+	case Set:
+		v.Values = rewriteArgLiteralsIn(v.Values, arity, variadic)
+		return v
+
+	case *HashMap:
+		return v.withEntries(rewriteArgLiteralsIn(v.kvs(), arity, variadic))
+	}
+
+	return form
+}
+
+func rewriteArgLiteralsIn(forms []Value, arity *int, variadic *bool) []Value {
+	if forms == nil {
+		return nil
+	}
+
+	res := make([]Value, len(forms))
+	for i, f := range forms {
+		res[i] = rewriteArgLiterals(f, arity, variadic)
+	}
+	return res
+}
+
 func readUnicodeChar(token string, base int) (Character, error) {
 	num, err := strconv.ParseInt(token, base, 64)
 	if err != nil {
@@ -544,13 +1000,8 @@ func readComment(rd *Reader, _ rune) (Value, error) {
 
 func quoteFormReader(expandFunc string) ReaderMacro {
 	return func(rd *Reader, _ rune) (Value, error) {
-		expr, err := rd.One()
+		expr, err := readNextForm(rd, "quote form")
 		if err != nil {
-			if err == io.EOF {
-				return nil, errors.New("EOF while reading quote form")
-			} else if err == ErrSkip {
-				return nil, errors.New("no-op form while reading quote form")
-			}
 			return nil, err
 		}
 
@@ -563,6 +1014,22 @@ func quoteFormReader(expandFunc string) ReaderMacro {
 	}
 }
 
+// readNextForm reads the form following a reader macro (e.g., quote).
+// formType is used in the error messages.
+func readNextForm(rd *Reader, formType string) (Value, error) {
+	form, err := rd.One()
+	if err != nil {
+		if err == io.EOF {
+			return nil, fmt.Errorf("EOF while reading %s", formType)
+		} else if err == ErrSkip {
+			return nil, fmt.Errorf("no-op form while reading %s", formType)
+		}
+		return nil, err
+	}
+
+	return form, nil
+}
+
 func parseRadix(numStr string) (Int64, error) {
 	parts := strings.Split(numStr, "r")
 	if len(parts) != 2 {
@@ -604,6 +1071,12 @@ func parseScientific(numStr string) (Float64, error) {
 		return 0, fmt.Errorf("illegal scientific notation '%s'", numStr)
 	}
 
+	// parse the whole number when possible since scaling the base is not
+	// exact (e.g., 1e-9 would not read back as the same value).
+	if v, err := strconv.ParseFloat(numStr, 64); err == nil {
+		return Float64(v), nil
+	}
+
 	return Float64(base * math.Pow(10, float64(pow))), nil
 }
 
@@ -692,10 +1165,14 @@ func defaultReadTable() map[rune]ReaderMacro {
 		'\'': quoteFormReader("quote"),
 		'~':  quoteFormReader("unquote"),
 		'`':  quoteFormReader("syntax-quote"),
+		'@':  quoteFormReader("deref"),
 		'(':  readList,
 		')':  unmatchedDelimiter,
 		'[':  readVector,
 		']':  unmatchedDelimiter,
+		'{':  readHashMap,
+		'}':  unmatchedDelimiter,
+		'^':  readMeta,
 	}
 }
 
@@ -703,6 +1180,13 @@ func defaultDispatchTable() map[rune]ReaderMacro {
 	return map[rune]ReaderMacro{
 		'{': readSet,
 		'}': unmatchedDelimiter,
+		'(': readAnonFn,
+		'_': readDiscard,
+		'?': readConditional,
+		'"': readRegex,
+		'r': readRawString,
+		'f': readInterpolated,
+		'#': readSymbolicValue,
 	}
 }
 
@@ -2,7 +2,10 @@ package sabre_test
 
 import (
 	"bytes"
+	"errors"
 	"io"
+	"math"
+	"math/big"
 	"os"
 	"reflect"
 	"strings"
@@ -250,6 +253,23 @@ func TestReader_One(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "Deref",
+			src:  "@counter",
+			want: &sabre.List{
+				Values: []sabre.Value{
+					sabre.Symbol{Value: "deref"},
+					sabre.Symbol{
+						Value: "counter",
+						Position: sabre.Position{
+							File:   "<string>",
+							Line:   1,
+							Column: 2,
+						},
+					},
+				},
+			},
+		},
 	})
 }
 
@@ -406,9 +426,52 @@ func TestReader_One_Number(t *testing.T) {
 			src:     "9.3.2",
 			wantErr: true,
 		},
+		{
+			name: "BigInt",
+			src:  "-123456789012345678901234567890N",
+			want: mustBigInt("-123456789012345678901234567890"),
+		},
+		{
+			name: "BigIntHex",
+			src:  "0xFFFFFFFFFFFFFFFFFFN",
+			want: mustBigInt("4722366482869645213695"),
+		},
+		{
+			name:    "BigIntFloat",
+			src:     "1.5N",
+			wantErr: true,
+		},
+		{
+			name: "Inf",
+			src:  "##Inf",
+			want: sabre.Float64(math.Inf(1)),
+		},
+		{
+			name: "NegativeInf",
+			src:  "##-Inf",
+			want: sabre.Float64(math.Inf(-1)),
+		},
+		{
+			name:    "UnknownSymbolicValue",
+			src:     "##Foo",
+			wantErr: true,
+		},
 	})
 }
 
+func TestReader_One_NaN(t *testing.T) {
+	t.Parallel()
+
+	got, err := sabre.NewReader(strings.NewReader(sabre.Float64(math.NaN()).String())).One()
+	if err != nil {
+		t.Fatalf("One() unexpected error: %v", err)
+	}
+
+	if f, ok := got.(sabre.Float64); !ok || !math.IsNaN(float64(f)) {
+		t.Errorf("One() got = %#v, want NaN", got)
+	}
+}
+
 func TestReader_One_String(t *testing.T) {
 	executeReaderTests(t, []readerTestCase{
 		{
@@ -441,9 +504,125 @@ func TestReader_One_String(t *testing.T) {
 			src:     `"hello\`,
 			wantErr: true,
 		},
+		{
+			name: "EscapeFormFeed",
+			src:  `"a\fb"`,
+			want: sabre.String("a\fb"),
+		},
+		{
+			name: "EscapeUnicode",
+			src:  `"\u00A5\u{1F600}\u{41}"`,
+			want: sabre.String("¥😀A"),
+		},
+		{
+			name: "EscapeHex",
+			src:  `"\x41\x7e1"`,
+			want: sabre.String("A~1"),
+		},
+		{
+			name: "EscapeOctal",
+			src:  `"\101\0\1018"`,
+			want: sabre.String("A\x00A8"),
+		},
+		{
+			name:    "EscapeShortUnicode",
+			src:     `"\u41"`,
+			wantErr: true,
+		},
+		{
+			name:    "EscapeUnclosedUnicode",
+			src:     `"\u{41"`,
+			wantErr: true,
+		},
+		{
+			name:    "EscapeUnicodeOutOfRange",
+			src:     `"\u{110000}"`,
+			wantErr: true,
+		},
+		{
+			name:    "EscapeOctalOutOfRange",
+			src:     `"\400"`,
+			wantErr: true,
+		},
 	})
 }
 
+func TestReader_One_StringForms(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		name    string
+		src     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Raw",
+			src:  `#r"C:\new\dir\"`,
+			want: `"C:\\new\\dir\\"`,
+		},
+		{
+			name:    "RawUnterminated",
+			src:     `#r"abc`,
+			wantErr: true,
+		},
+		{
+			name: "Interpolated",
+			src:  `#f"Hello {name}!"`,
+			want: `(str "Hello " name "!")`,
+		},
+		{
+			name: "InterpolatedForms",
+			src:  `#f"{ (:a m) }{{x}}\t{#f"{y}"}"`,
+			want: `(str (:a m) "{x}\t" (str y))`,
+		},
+		{
+			name: "InterpolatedNoForms",
+			src:  `#f"plain {{}}"`,
+			want: `"plain {}"`,
+		},
+		{
+			name:    "InterpolatedMissingClose",
+			src:     `#f"{a b}"`,
+			wantErr: true,
+		},
+		{
+			name:    "InterpolatedSingleBrace",
+			src:     `#f"a } b"`,
+			wantErr: true,
+		},
+		{
+			name:    "InterpolatedEmptyForm",
+			src:     `#f"{}"`,
+			wantErr: true,
+		},
+		{
+			name: "TagStartingWithF",
+			src:  `#foo [1]`,
+			want: `(foo [1])`,
+		},
+	}
+
+	for _, tt := range table {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			rd := sabre.NewReader(strings.NewReader(tt.src))
+			rd.SetTag("foo", func(form sabre.Value) (sabre.Value, error) {
+				return &sabre.List{Values: []sabre.Value{sabre.Symbol{Value: "foo"}, form}}, nil
+			})
+
+			got, err := rd.One()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("One() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && got.String() != tt.want {
+				t.Errorf("One() got = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestReader_One_Keyword(t *testing.T) {
 	executeReaderTests(t, []readerTestCase{
 		{
@@ -892,9 +1071,454 @@ func TestReader_One_Set(t *testing.T) {
 			src:     "#{1 2 2}",
 			wantErr: true,
 		},
+		{
+			name:    "HasDuplicateMaps",
+			src:     "#{{:a 1 :b 2} {:b 2 :a 1}}",
+			wantErr: true,
+		},
 	})
 }
 
+func TestReader_KeepDuplicates(t *testing.T) {
+	t.Parallel()
+
+	rd := sabre.NewReader(strings.NewReader("#{1 2 2}"))
+	rd.KeepDuplicates(true)
+
+	got, err := rd.One()
+	if err != nil {
+		t.Fatalf("One() unexpected error: %v", err)
+	}
+
+	want := sabre.Set{
+		Values:   []sabre.Value{sabre.Int64(1), sabre.Int64(2), sabre.Int64(2)},
+		Position: sabre.Position{File: "<string>", Line: 1, Column: 2},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("One() got = %v, want %v", got, want)
+	}
+}
+
+func TestReader_One_AnonFn(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		name    string
+		src     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "NoArgs",
+			src:  `#(rand)`,
+			want: `(fn* [] (rand))`,
+		},
+		{
+			name: "ImplicitFirstArg",
+			src:  `#(+ % 1)`,
+			want: `(fn* [%1] (+ %1 1))`,
+		},
+		{
+			name: "HighestArg",
+			src:  `#(vector %3 [%1 #{%}])`,
+			want: `(fn* [%1 %2 %3] (vector %3 [%1 #{%1}]))`,
+		},
+		{
+			name: "Variadic",
+			src:  `#(apply f %2 %&)`,
+			want: `(fn* [%1 %2 & %&] (apply f %2 %&))`,
+		},
+		{
+			name: "NotArgLiterals",
+			src:  `#(f %x %0)`,
+			want: `(fn* [] (f %x %0))`,
+		},
+		{
+			name:    "Nested",
+			src:     `#(map #(inc %) %)`,
+			wantErr: true,
+		},
+		{
+			name:    "EOF",
+			src:     `#(inc %`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range table {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sabre.NewReader(strings.NewReader(tt.src)).One()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("One() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && got.String() != tt.want {
+				t.Errorf("One() got = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReader_One_HashMap(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		name    string
+		src     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Empty",
+			src:  `{}`,
+			want: `{}`,
+		},
+		{
+			name: "InsertionOrder",
+			src:  `{:b 1, "a" [x], 1.5 {}}`,
+			want: `{:b 1, "a" [x], 1.5 {}}`,
+		},
+		{
+			name:    "OddForms",
+			src:     `{:a 1 :b}`,
+			wantErr: true,
+		},
+		{
+			name:    "DuplicateKey",
+			src:     `{:a 1 :a 2}`,
+			wantErr: true,
+		},
+		{
+			name:    "Unmatched",
+			src:     `}`,
+			wantErr: true,
+		},
+		{
+			name:    "EOF",
+			src:     `{:a 1`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range table {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sabre.NewReader(strings.NewReader(tt.src)).One()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("One() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && got.String() != tt.want {
+				t.Errorf("One() got = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReader_One_Meta(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		name     string
+		src      string
+		want     string
+		wantMeta string
+		wantErr  bool
+	}{
+		{
+			name:     "Keyword",
+			src:      `^:private foo`,
+			want:     `foo`,
+			wantMeta: `{:private true}`,
+		},
+		{
+			name:     "Map",
+			src:      `^{:doc "adds" :arglists ([a b])} (fn* [a b])`,
+			want:     `(fn* [a b])`,
+			wantMeta: `{:doc "adds", :arglists ([a b])}`,
+		},
+		{
+			name:     "TypeHint",
+			src:      `[^String name]`,
+			want:     `[name]`,
+			wantMeta: `{:tag String}`,
+		},
+		{
+			name:     "Merged",
+			src:      `^:a ^{:a 0 :b 1} #{}`,
+			want:     `#{}`,
+			wantMeta: `{:a true, :b 1}`,
+		},
+		{
+			name:    "InvalidMeta",
+			src:     `^1 foo`,
+			wantErr: true,
+		},
+		{
+			name:    "InvalidTarget",
+			src:     `^:private 1`,
+			wantErr: true,
+		},
+		{
+			name:    "EOF",
+			src:     `^:private`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range table {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := sabre.NewReader(strings.NewReader(tt.src)).One()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("One() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if tt.wantErr {
+				return
+			}
+
+			if got.String() != tt.want {
+				t.Errorf("One() got = %s, want %s", got, tt.want)
+			}
+
+			if vec, ok := got.(sabre.Vector); ok {
+				got = vec.Values[0]
+			}
+
+			meta := got.(sabre.Meta).Meta()
+			if meta.String() != tt.wantMeta {
+				t.Errorf("Meta() got = %s, want %s", meta, tt.wantMeta)
+			}
+		})
+	}
+}
+
+func TestReader_One_Dispatch(t *testing.T) {
+	t.Parallel()
+
+	table := []struct {
+		name     string
+		src      string
+		features []sabre.Keyword
+		want     string
+		wantErr  bool
+	}{
+		{
+			name: "Discard",
+			src:  `#_ (ignored form) [1 #_ 2 3 #_#_ 4 5]`,
+			want: `[1 3]`,
+		},
+		{
+			name: "DiscardInSet",
+			src:  `#{a_b #_ c? d}`,
+			want: `#{a_b d}`,
+		},
+		{
+			name:    "DiscardEOF",
+			src:     `#_`,
+			wantErr: true,
+		},
+		{
+			name: "Conditional",
+			src:  `#?(:clj 1 :sabre 2 :default 3)`,
+			want: `2`,
+		},
+		{
+			name:     "ConditionalFeatures",
+			src:      `[#?(:sabre 1 :wasm 2) #? (:host 3)]`,
+			features: []sabre.Keyword{"wasm", "host"},
+			want:     `[2 3]`,
+		},
+		{
+			name: "ConditionalDefault",
+			src:  `#?(:clj 1 :default [])`,
+			want: `[]`,
+		},
+		{
+			name: "ConditionalNoMatch",
+			src:  `[#?(:clj 1) 2]`,
+			want: `[2]`,
+		},
+		{
+			name:    "ConditionalOddForms",
+			src:     `#?(:sabre)`,
+			wantErr: true,
+		},
+		{
+			name:    "ConditionalNotKeyword",
+			src:     `#?(sabre 1)`,
+			wantErr: true,
+		},
+		{
+			name:    "ConditionalNotList",
+			src:     `#?[:sabre 1]`,
+			wantErr: true,
+		},
+		{
+			name: "Regex",
+			src:  `#"\d+(\.\d*)?"`,
+			want: `#"\d+(\.\d*)?"`,
+		},
+		{
+			name: "RegexQuote",
+			src:  `#"say \"\w+\""`,
+			want: `#"say \"\w+\""`,
+		},
+		{
+			name:    "InvalidRegex",
+			src:     `#"a(b"`,
+			wantErr: true,
+		},
+		{
+			name:    "RegexEOF",
+			src:     `#"abc`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range table {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			rd := sabre.NewReader(strings.NewReader(tt.src))
+			if tt.features != nil {
+				rd.SetFeatures(tt.features...)
+			}
+
+			got, err := rd.One()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("One() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && got.String() != tt.want {
+				t.Errorf("One() got = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReader_One_Tagged(t *testing.T) {
+	t.Parallel()
+
+	money := func(form sabre.Value) (sabre.Value, error) {
+		vec, ok := form.(sabre.Vector)
+		if !ok || len(vec.Values) != 2 {
+			return nil, errors.New("money must be [amount currency]")
+		}
+		return testMoney{amount: vec.Values[0], currency: vec.Values[1]}, nil
+	}
+
+	table := []struct {
+		name    string
+		src     string
+		tags    map[string]sabre.TagHandler
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "Inst",
+			src:  `#inst "2020-01-18T10:20:30.5+05:30"`,
+			want: `#inst "2020-01-18T10:20:30.5+05:30"`,
+		},
+		{
+			name: "InstDate",
+			src:  `#inst "2020-01-18"`,
+			want: `#inst "2020-01-18T00:00:00Z"`,
+		},
+		{
+			name:    "InstInvalid",
+			src:     `#inst "18/01/2020"`,
+			wantErr: true,
+		},
+		{
+			name:    "InstNotString",
+			src:     `#inst 2020`,
+			wantErr: true,
+		},
+		{
+			name: "UUID",
+			src:  `#uuid "F81D4FAE-7DEC-11D0-A765-00A0C91E6BF6"`,
+			want: `#uuid "f81d4fae-7dec-11d0-a765-00a0c91e6bf6"`,
+		},
+		{
+			name: "Duration",
+			src:  `#duration "90m"`,
+			want: `#duration "1h30m0s"`,
+		},
+		{
+			name:    "DurationInvalid",
+			src:     `#duration "1x"`,
+			wantErr: true,
+		},
+		{
+			name:    "UUIDInvalid",
+			src:     `#uuid "f81d4fae7dec11d0a76500a0c91e6bf6"`,
+			wantErr: true,
+		},
+		{
+			name: "Custom",
+			src: `[#money [10 "USD"] #money ; amount
+  [5 "EUR"]]`,
+			tags: map[string]sabre.TagHandler{"money": money},
+			want: `[#money [10 "USD"] #money [5 "EUR"]]`,
+		},
+		{
+			name:    "CustomError",
+			src:     `#money 10`,
+			tags:    map[string]sabre.TagHandler{"money": money},
+			wantErr: true,
+		},
+		{
+			name:    "Removed",
+			src:     `#inst "2020-01-18"`,
+			tags:    map[string]sabre.TagHandler{"inst": nil},
+			wantErr: true,
+		},
+		{
+			name:    "Unknown",
+			src:     `#money [10 "USD"]`,
+			wantErr: true,
+		},
+		{
+			name:    "MissingForm",
+			src:     `#inst`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range table {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			rd := sabre.NewReader(strings.NewReader(tt.src))
+			for tag, handler := range tt.tags {
+				rd.SetTag(tag, handler)
+			}
+
+			got, err := rd.One()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("One() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			if !tt.wantErr && got.String() != tt.want {
+				t.Errorf("One() got = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+type testMoney struct {
+	amount, currency sabre.Value
+}
+
+func (m testMoney) Eval(_ sabre.Scope) (sabre.Value, error) { return m, nil }
+
+func (m testMoney) String() string { return sabre.TaggedString(m) }
+
+func (m testMoney) Tag() (string, sabre.Value) {
+	return "money", sabre.Vector{Values: []sabre.Value{m.amount, m.currency}}
+}
+
 type readerTestCase struct {
 	name    string
 	src     string
@@ -918,3 +1542,11 @@ func executeReaderTests(t *testing.T, tests []readerTestCase) {
 		})
 	}
 }
+
+func mustBigInt(s string) sabre.BigInt {
+	bi, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		panic("invalid big int: " + s)
+	}
+	return sabre.BigInt{Int: bi}
+}
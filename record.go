@@ -0,0 +1,118 @@
+package sabre
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// RecordType represents a named record type with a fixed set of fields.
+// Record types are defined using (defrecord Name [field*]).
+type RecordType struct {
+	Name   string
+	Fields []string
+}
+
+// Eval returns the record type itself.
+func (rt *RecordType) Eval(_ Scope) (Value, error) { return rt, nil }
+
+func (rt *RecordType) String() string { return rt.Name }
+
+// New returns a record of this type with the given values for the fields in
+// the order of their definition.
+func (rt *RecordType) New(vals ...Value) (Record, error) {
+	if len(vals) != len(rt.Fields) {
+		return Record{}, fmt.Errorf("wrong number of args (%d) to '->%s'", len(vals), rt.Name)
+	}
+
+	return Record{Type: rt, vals: append([]Value(nil), vals...)}, nil
+}
+
+// FromMap returns a record of this type with the fields set to the values
+// of the corresponding keyword keys in the map. Fields not present in the
+// map are set to nil and keys not naming a field are not allowed.
+func (rt *RecordType) FromMap(hm *HashMap) (Record, error) {
+	rec := Record{Type: rt, vals: make([]Value, len(rt.Fields))}
+	for i := range rec.vals {
+		rec.vals[i] = Nil{}
+	}
+
+	for _, e := range hm.items() {
+		idx := rt.fieldIndex(e.key)
+		if idx < 0 {
+			return Record{}, fmt.Errorf("record '%s' has no field '%s'", rt.Name, e.key)
+		}
+
+		rec.vals[idx] = e.val
+	}
+
+	return rec, nil
+}
+
+func (rt *RecordType) fieldIndex(key Value) int {
+	kw, isKeyword := key.(Keyword)
+	if !isKeyword {
+		return -1
+	}
+
+	for i, field := range rt.Fields {
+		if field == string(kw) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Record represents an instance of a record type. Fields of a record can
+// be accessed using keywords (e.g., (:x point)). Records are equal (i.e.,
+// reflect.DeepEqual) if they are of the same type and their fields are
+// equal.
+type Record struct {
+	Type *RecordType
+	vals []Value
+}
+
+// Eval returns the record itself.
+func (rec Record) Eval(_ Scope) (Value, error) { return rec, nil }
+
+func (rec Record) String() string { return TaggedString(rec) }
+
+// Tag returns the name of the record type and the fields of the record as
+// a map.
+func (rec Record) Tag() (string, Value) {
+	hm := &HashMap{}
+	for i, field := range rec.Type.Fields {
+		hm.put(Keyword(field), rec.vals[i])
+	}
+
+	return rec.Type.Name, hm
+}
+
+// Get returns the value of the field named by the keyword.
+func (rec Record) Get(key Value) (Value, bool) {
+	idx := rec.Type.fieldIndex(key)
+	if idx < 0 {
+		return nil, false
+	}
+
+	return rec.vals[idx], true
+}
+
+// Size returns the number of fields in the record.
+func (rec Record) Size() int { return len(rec.vals) }
+
+// typeKey returns the key identifying the type of the value for dispatch
+// by protocols. Records are identified by their record type and Go values
+// wrapped by ValueOf are identified by the type of the Go value.
+func typeKey(v Value) interface{} {
+	switch val := v.(type) {
+	case Record:
+		return val.Type
+
+	case anyValue:
+		return val.rv.Type()
+
+	default:
+		return reflect.TypeOf(v)
+	}
+}
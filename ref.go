@@ -0,0 +1,239 @@
+package sabre
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ErrInvalidState is returned when a validator rejects the new value of a
+// reference.
+var ErrInvalidState = errors.New("invalid reference state")
+
+// Derefable represents reference types whose current value can be obtained
+// using deref (or the '@' reader macro).
+type Derefable interface {
+	Value
+	Deref() (Value, error)
+}
+
+// NewAtom returns a new Atom holding the given value.
+func NewAtom(v Value) *Atom {
+	if v == nil {
+		v = Nil{}
+	}
+
+	return &Atom{val: v}
+}
+
+// Atom is a reference type which provides synchronised access to shared
+// state. All operations on an Atom are safe for use from multiple goroutines.
+type Atom struct {
+	mu        sync.RWMutex
+	val       Value
+	version   uint64
+	validator Invokable
+	watches   []watch
+}
+
+// Eval returns the atom itself.
+func (atom *Atom) Eval(_ Scope) (Value, error) { return atom, nil }
+
+func (atom *Atom) String() string {
+	return fmt.Sprintf("Atom{%v}", atom.current())
+}
+
+// Deref returns the current value of the atom.
+func (atom *Atom) Deref() (Value, error) {
+	return atom.current(), nil
+}
+
+// Reset sets the value of the atom to 'v' without regard for the current
+// value and returns 'v'.
+func (atom *Atom) Reset(scope Scope, v Value) (Value, error) {
+	if v == nil {
+		v = Nil{}
+	}
+
+	for {
+		old, version := atom.snapshot()
+
+		if err := atom.validate(scope, v); err != nil {
+			return nil, err
+		}
+
+		if atom.commit(version, v) {
+			return v, atom.notify(scope, old, v)
+		}
+	}
+}
+
+// Swap atomically sets the value of the atom to (fn current-value args...)
+// and returns the new value. Since 'fn' may be invoked multiple times when
+// there are concurrent updates, it should be free of side effects.
+func (atom *Atom) Swap(scope Scope, fn Invokable, args []Value) (Value, error) {
+	for {
+		old, version := atom.snapshot()
+
+		v, err := Apply(scope, fn, append([]Value{old}, args...))
+		if err != nil {
+			return nil, err
+		}
+
+		if err := atom.validate(scope, v); err != nil {
+			return nil, err
+		}
+
+		if atom.commit(version, v) {
+			return v, atom.notify(scope, old, v)
+		}
+	}
+}
+
+// CompareAndSet atomically sets the value of the atom to 'newVal' if and
+// only if the current value is equal to 'oldVal'. Values are compared the
+// same way as the keys of a HashMap. Returns true if the value was set.
+func (atom *Atom) CompareAndSet(scope Scope, oldVal, newVal Value) (bool, error) {
+	for {
+		old, version := atom.snapshot()
+		if !equalKeys(old, oldVal) {
+			return false, nil
+		}
+
+		if err := atom.validate(scope, newVal); err != nil {
+			return false, err
+		}
+
+		if atom.commit(version, newVal) {
+			return true, atom.notify(scope, old, newVal)
+		}
+	}
+}
+
+// SetValidator sets the function used to validate every new value of the
+// atom. Validator is invoked with the proposed value and must return a
+// truthy value for the change to succeed. Passing nil removes the validator.
+// Returns ErrInvalidState if the current value is not valid.
+func (atom *Atom) SetValidator(scope Scope, fn Invokable) error {
+	if fn != nil {
+		ok, err := Apply(scope, fn, []Value{atom.current()})
+		if err != nil {
+			return err
+		}
+
+		if !isTruthy(ok) {
+			return ErrInvalidState
+		}
+	}
+
+	atom.mu.Lock()
+	defer atom.mu.Unlock()
+
+	// updates validated with the previous validator must be retried.
+	atom.validator = fn
+	atom.version++
+	return nil
+}
+
+// AddWatch adds a watch function which is invoked as (fn key atom old new)
+// after every change to the value of the atom. Adding a watch with a key
+// that already exists replaces the existing watch.
+func (atom *Atom) AddWatch(key Value, fn Invokable) {
+	atom.mu.Lock()
+	defer atom.mu.Unlock()
+
+	for i, w := range atom.watches {
+		if reflect.DeepEqual(w.key, key) {
+			atom.watches[i].fn = fn
+			return
+		}
+	}
+
+	atom.watches = append(atom.watches, watch{key: key, fn: fn})
+}
+
+// RemoveWatch removes the watch added with the given key.
+func (atom *Atom) RemoveWatch(key Value) {
+	atom.mu.Lock()
+	defer atom.mu.Unlock()
+
+	var watches []watch
+	for _, w := range atom.watches {
+		if !reflect.DeepEqual(w.key, key) {
+			watches = append(watches, w)
+		}
+	}
+	atom.watches = watches
+}
+
+// snapshot returns the current value of the atom and its version. Changes
+// validated against a snapshot must be stored using commit.
+func (atom *Atom) snapshot() (Value, uint64) {
+	atom.mu.RLock()
+	defer atom.mu.RUnlock()
+
+	return atom.val, atom.version
+}
+
+// commit sets the value of the atom to 'v' if the atom has not been changed
+// since the snapshot with the given version was taken.
+func (atom *Atom) commit(version uint64, v Value) bool {
+	atom.mu.Lock()
+	defer atom.mu.Unlock()
+
+	if atom.version != version {
+		return false
+	}
+
+	atom.val = v
+	atom.version++
+	return true
+}
+
+func (atom *Atom) current() Value {
+	atom.mu.RLock()
+	defer atom.mu.RUnlock()
+
+	return atom.val
+}
+
+func (atom *Atom) validate(scope Scope, v Value) error {
+	atom.mu.RLock()
+	validator := atom.validator
+	atom.mu.RUnlock()
+
+	if validator == nil {
+		return nil
+	}
+
+	ok, err := Apply(scope, validator, []Value{v})
+	if err != nil {
+		return err
+	}
+
+	if !isTruthy(ok) {
+		return ErrInvalidState
+	}
+
+	return nil
+}
+
+func (atom *Atom) notify(scope Scope, oldVal, newVal Value) error {
+	atom.mu.RLock()
+	watches := append([]watch(nil), atom.watches...)
+	atom.mu.RUnlock()
+
+	for _, w := range watches {
+		if _, err := Apply(scope, w.fn, []Value{w.key, atom, oldVal, newVal}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+type watch struct {
+	key Value
+	fn  Invokable
+}
@@ -0,0 +1,171 @@
+package sabre_test
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/spy16/sabre"
+)
+
+var _ sabre.Derefable = sabre.NewAtom(nil)
+
+func TestAtom(t *testing.T) {
+	t.Parallel()
+
+	inc := sabre.ValueOf(func(i sabre.Int64) sabre.Int64 { return i + 1 }).(sabre.Invokable)
+	isPositive := sabre.ValueOf(func(i sabre.Int64) bool { return i > 0 }).(sabre.Invokable)
+
+	t.Run("SwapReset", func(t *testing.T) {
+		atom := sabre.NewAtom(sabre.Int64(1))
+
+		got, err := atom.Swap(nil, inc, nil)
+		if err != nil || got != sabre.Int64(2) {
+			t.Errorf("Swap() got = (%v, %v), want (2, nil)", got, err)
+		}
+
+		got, err = atom.Reset(nil, sabre.Int64(10))
+		if err != nil || got != sabre.Int64(10) {
+			t.Errorf("Reset() got = (%v, %v), want (10, nil)", got, err)
+		}
+
+		got, _ = atom.Deref()
+		if got != sabre.Int64(10) {
+			t.Errorf("Deref() got = %v, want 10", got)
+		}
+	})
+
+	t.Run("SwapQuotesValues", func(t *testing.T) {
+		atom := sabre.NewAtom(&sabre.List{Values: []sabre.Value{sabre.Symbol{Value: "unbound"}}})
+		identity := sabre.GoFunc(func(scope sabre.Scope, args []sabre.Value) (sabre.Value, error) {
+			return args[0].Eval(scope)
+		})
+
+		got, err := atom.Swap(sabre.NewScope(nil), identity, nil)
+		if err != nil {
+			t.Fatalf("Swap() unexpected error: %v", err)
+		}
+
+		want := &sabre.List{Values: []sabre.Value{sabre.Symbol{Value: "unbound"}}}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Swap() got = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("CompareAndSet", func(t *testing.T) {
+		atom := sabre.NewAtom(sabre.Int64(1))
+
+		ok, err := atom.CompareAndSet(nil, sabre.Int64(2), sabre.Int64(3))
+		if err != nil || ok {
+			t.Errorf("CompareAndSet() got = (%t, %v), want (false, nil)", ok, err)
+		}
+
+		ok, err = atom.CompareAndSet(nil, sabre.Int64(1), sabre.Int64(3))
+		if err != nil || !ok {
+			t.Errorf("CompareAndSet() got = (%t, %v), want (true, nil)", ok, err)
+		}
+
+		read := sabre.Vector{
+			Values:   []sabre.Value{sabre.Keyword("a")},
+			Position: sabre.Position{File: "<string>", Line: 1, Column: 1},
+		}
+		atom = sabre.NewAtom(read)
+
+		ok, err = atom.CompareAndSet(nil, sabre.Vector{Values: []sabre.Value{sabre.Keyword("a")}}, sabre.Int64(1))
+		if err != nil || !ok {
+			t.Errorf("CompareAndSet() ignoring position got = (%t, %v), want (true, nil)", ok, err)
+		}
+	})
+
+	t.Run("ValidatorReplacedDuringReset", func(t *testing.T) {
+		atom := sabre.NewAtom(sabre.Int64(1))
+
+		replaced := true
+		replacing := sabre.GoFunc(func(_ sabre.Scope, _ []sabre.Value) (sabre.Value, error) {
+			if !replaced {
+				replaced = true
+				if err := atom.SetValidator(nil, isPositive); err != nil {
+					return nil, err
+				}
+			}
+			return sabre.Bool(true), nil
+		})
+
+		if err := atom.SetValidator(nil, replacing); err != nil {
+			t.Fatalf("SetValidator() unexpected error: %v", err)
+		}
+		replaced = false
+
+		if _, err := atom.Reset(nil, sabre.Int64(-1)); err != sabre.ErrInvalidState {
+			t.Errorf("Reset() error = %v, want ErrInvalidState", err)
+		}
+
+		if got, _ := atom.Deref(); got != sabre.Int64(1) {
+			t.Errorf("Deref() got = %v, want 1", got)
+		}
+	})
+
+	t.Run("Validator", func(t *testing.T) {
+		atom := sabre.NewAtom(sabre.Int64(1))
+		if err := atom.SetValidator(nil, isPositive); err != nil {
+			t.Fatalf("SetValidator() unexpected error: %v", err)
+		}
+
+		if _, err := atom.Reset(nil, sabre.Int64(-1)); err != sabre.ErrInvalidState {
+			t.Errorf("Reset() error = %v, want ErrInvalidState", err)
+		}
+
+		got, _ := atom.Deref()
+		if got != sabre.Int64(1) {
+			t.Errorf("Deref() got = %v, want 1", got)
+		}
+
+		invalid := sabre.NewAtom(sabre.Int64(-1))
+		if err := invalid.SetValidator(nil, isPositive); err != sabre.ErrInvalidState {
+			t.Errorf("SetValidator() error = %v, want ErrInvalidState", err)
+		}
+	})
+
+	t.Run("Watch", func(t *testing.T) {
+		atom := sabre.NewAtom(sabre.Int64(1))
+
+		var calls [][]sabre.Value
+		watcher := sabre.GoFunc(func(_ sabre.Scope, args []sabre.Value) (sabre.Value, error) {
+			calls = append(calls, args)
+			return sabre.Nil{}, nil
+		})
+
+		atom.AddWatch(sabre.Keyword("w"), watcher)
+		_, _ = atom.Reset(nil, sabre.Int64(2))
+		atom.RemoveWatch(sabre.Keyword("w"))
+		_, _ = atom.Reset(nil, sabre.Int64(3))
+
+		want := [][]sabre.Value{{sabre.Keyword("w"), atom, sabre.Int64(1), sabre.Int64(2)}}
+		if !reflect.DeepEqual(calls, want) {
+			t.Errorf("watch calls got = %v, want %v", calls, want)
+		}
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		atom := sabre.NewAtom(sabre.Int64(0))
+
+		var wg sync.WaitGroup
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for j := 0; j < 20; j++ {
+					if _, err := atom.Swap(nil, inc, nil); err != nil {
+						t.Errorf("Swap() unexpected error: %v", err)
+					}
+				}
+			}()
+		}
+		wg.Wait()
+
+		got, _ := atom.Deref()
+		if got != sabre.Int64(1000) {
+			t.Errorf("Deref() got = %v, want 1000", got)
+		}
+	})
+}
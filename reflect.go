@@ -1,8 +1,17 @@
 package sabre
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"math/big"
 	"reflect"
+	"time"
+)
+
+var (
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
 )
 
 // ValueOf converts a Go value to sabre Value type. Functions will be
@@ -19,12 +28,26 @@ func ValueOf(v interface{}) Value {
 		return Nil{}
 	}
 
+	switch val := v.(type) {
+	case *big.Int:
+		return BigInt{Int: val}
+
+	case time.Time:
+		return Time{Time: val}
+
+	case time.Duration:
+		return Duration(val)
+	}
+
 	rv := reflect.ValueOf(v)
 
 	switch rv.Kind() {
 	case reflect.Func:
 		return reflectFn(rv)
 
+	case reflect.Chan:
+		return &Chan{rv: rv}
+
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		return Int64(rv.Int())
 
@@ -59,12 +82,18 @@ func reflectFn(rv reflect.Value) GoFunc {
 		}()
 
 		rt := rv.Type()
-		argVals := reflectValues(args)
+
+		vals, err := evalValueList(scope, args)
+		if err != nil {
+			return nil, err
+		}
+		argVals := reflectValues(vals)
 
 		if err := checkArgCount(rt, len(argVals)); err != nil {
 			return nil, err
 		}
 
+		unwrapTimeArgs(rt, argVals)
 		if err := checkArgTypes(rt, argVals); err != nil {
 			return nil, err
 		}
@@ -86,6 +115,231 @@ func reflectFn(rv reflect.Value) GoFunc {
 	}
 }
 
+// MakeFunc sets 'fptr' (a pointer to a Go function variable) to a new Go
+// function of the same type which invokes 'fn' when called. Arguments are
+// converted to Values using ValueOf and the result of invocation is converted
+// back to the first return type of the function. Function type can have at
+// most 2 return values and if there are 2, the second one must be of error
+// type. Conversion and evaluation errors are returned through the trailing
+// error return value if present. Otherwise, the function panics.
+//
+// 'fn' is invoked with 'scope' as the call site so that the context associated
+// with the scope (See WithContext) applies to the invocations. If the first
+// parameter of the function type is a context.Context, the context passed by
+// the caller is used instead, which allows Go callers to cancel invocations.
+func MakeFunc(scope Scope, fn Value, fptr interface{}) error {
+	invokable, ok := fn.(Invokable)
+	if !ok {
+		return fmt.Errorf("value of type '%s' is not invokable", reflect.TypeOf(fn))
+	}
+
+	rv := reflect.ValueOf(fptr)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() || rv.Elem().Kind() != reflect.Func {
+		return errors.New("fptr must be a non-nil pointer to a function")
+	}
+
+	rt := rv.Elem().Type()
+	if err := checkFuncReturns(rt); err != nil {
+		return err
+	}
+
+	withCtx := rt.NumIn() > 0 && rt.In(0) == contextType
+
+	impl := func(in []reflect.Value) []reflect.Value {
+		callScope := scope
+		if withCtx {
+			ctx, _ := in[0].Interface().(context.Context)
+			if ctx == nil {
+				ctx = context.Background()
+			}
+
+			if callScope == nil {
+				callScope = NewScope(nil)
+			}
+			callScope = WithContext(ctx, callScope)
+			in = in[1:]
+		}
+
+		var args []Value
+		for i, arg := range in {
+			if rt.IsVariadic() && i == len(in)-1 {
+				for j := 0; j < arg.Len(); j++ {
+					args = append(args, ValueOf(arg.Index(j).Interface()))
+				}
+				break
+			}
+
+			args = append(args, ValueOf(arg.Interface()))
+		}
+
+		res, err := Apply(callScope, invokable, args)
+		if err == nil {
+			return makeReturns(rt, res)
+		}
+
+		return makeErrReturns(rt, err)
+	}
+
+	rv.Elem().Set(reflect.MakeFunc(rt, impl))
+	return nil
+}
+
+func checkFuncReturns(rt reflect.Type) error {
+	switch rt.NumOut() {
+	case 0:
+		return nil
+
+	case 1:
+		return nil
+
+	case 2:
+		if rt.Out(1) != errorType {
+			return fmt.Errorf("second return value must be error, not '%s'", rt.Out(1))
+		}
+		return nil
+
+	default:
+		return fmt.Errorf("function can have at most 2 return values, not %d", rt.NumOut())
+	}
+}
+
+func makeReturns(rt reflect.Type, res Value) []reflect.Value {
+	if rt.NumOut() == 0 {
+		return nil
+	}
+
+	if rt.NumOut() == 1 && rt.Out(0) == errorType {
+		return []reflect.Value{reflect.Zero(errorType)}
+	}
+
+	retVal, err := convertValue(res, rt.Out(0))
+	if err != nil {
+		return makeErrReturns(rt, err)
+	}
+
+	if rt.NumOut() == 1 {
+		return []reflect.Value{retVal}
+	}
+
+	return []reflect.Value{retVal, reflect.Zero(errorType)}
+}
+
+func makeErrReturns(rt reflect.Type, err error) []reflect.Value {
+	if rt.NumOut() == 0 || rt.Out(rt.NumOut()-1) != errorType {
+		panic(err)
+	}
+
+	errVal := reflect.ValueOf(&err).Elem()
+	if rt.NumOut() == 1 {
+		return []reflect.Value{errVal}
+	}
+
+	return []reflect.Value{reflect.Zero(rt.Out(0)), errVal}
+}
+
+// convertValue converts the Value 'v' to a Go value of type 'rt'.
+func convertValue(v Value, rt reflect.Type) (reflect.Value, error) {
+	if v == nil {
+		return reflect.Zero(rt), nil
+	}
+
+	if any, isAny := v.(anyValue); isAny && any.rv.Type().AssignableTo(rt) {
+		return any.rv, nil
+	}
+
+	if reflect.TypeOf(v).AssignableTo(rt) {
+		return reflect.ValueOf(v), nil
+	}
+
+	if _, isNil := v.(Nil); isNil {
+		return reflect.Zero(rt), nil
+	}
+
+	rv := reflect.New(rt).Elem()
+
+	switch val := v.(type) {
+	case Bool:
+		if rt.Kind() == reflect.Bool {
+			rv.SetBool(bool(val))
+			return rv, nil
+		}
+
+	case Int64:
+		switch rt.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if !rv.OverflowInt(int64(val)) {
+				rv.SetInt(int64(val))
+				return rv, nil
+			}
+
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if val >= 0 && !rv.OverflowUint(uint64(val)) {
+				rv.SetUint(uint64(val))
+				return rv, nil
+			}
+
+		case reflect.Float32, reflect.Float64:
+			rv.SetFloat(float64(val))
+			return rv, nil
+		}
+
+	case BigInt:
+		if reflect.TypeOf(val.Int).AssignableTo(rt) {
+			return reflect.ValueOf(val.Int), nil
+		}
+
+	case Time:
+		if reflect.TypeOf(val.Time).AssignableTo(rt) {
+			return reflect.ValueOf(val.Time), nil
+		}
+
+	case Duration:
+		if rt == reflect.TypeOf(time.Duration(0)) {
+			return reflect.ValueOf(time.Duration(val)), nil
+		}
+
+	case Float64:
+		if rt.Kind() == reflect.Float32 || rt.Kind() == reflect.Float64 {
+			rv.SetFloat(float64(val))
+			return rv, nil
+		}
+
+	case Character:
+		switch rt.Kind() {
+		case reflect.Int32:
+			rv.SetInt(int64(val))
+			return rv, nil
+
+		case reflect.Uint8:
+			if !rv.OverflowUint(uint64(val)) {
+				rv.SetUint(uint64(val))
+				return rv, nil
+			}
+		}
+
+	case String:
+		if rt.Kind() == reflect.String {
+			rv.SetString(string(val))
+			return rv, nil
+		}
+
+	case Seq:
+		if rt.Kind() == reflect.Slice {
+			for seq := Seq(val); seq != nil && seq.First() != nil; seq = seq.Next() {
+				item, err := convertValue(seq.First(), rt.Elem())
+				if err != nil {
+					return reflect.Value{}, err
+				}
+				rv = reflect.Append(rv, item)
+			}
+			return rv, nil
+		}
+	}
+
+	return reflect.Value{}, fmt.Errorf("cannot convert value of type '%s' to '%s'",
+		reflect.TypeOf(v), rt)
+}
+
 type anyValue struct{ rv reflect.Value }
 
 func (any anyValue) Eval(_ Scope) (Value, error) { return any, nil }
@@ -113,10 +367,46 @@ func checkArgTypes(rt reflect.Type, args []reflect.Value) error {
 	return nil
 }
 
+// unwrapTimeArgs replaces Time and Duration args with the wrapped time.Time
+// and time.Duration values where the function expects the Go types.
+func unwrapTimeArgs(rt reflect.Type, args []reflect.Value) {
+	for i, arg := range args {
+		var expected reflect.Type
+		if rt.IsVariadic() && i >= rt.NumIn()-1 {
+			expected = rt.In(rt.NumIn() - 1).Elem()
+		} else if i < rt.NumIn() {
+			expected = rt.In(i)
+		} else {
+			return
+		}
+
+		if !arg.IsValid() || !arg.CanInterface() {
+			continue
+		}
+
+		switch v := arg.Interface().(type) {
+		case Time:
+			if expected == reflect.TypeOf(v.Time) {
+				args[i] = reflect.ValueOf(v.Time)
+			}
+
+		case Duration:
+			if expected == reflect.TypeOf(time.Duration(0)) {
+				args[i] = reflect.ValueOf(time.Duration(v))
+			}
+		}
+	}
+}
+
 func reflectValues(args []Value) []reflect.Value {
 	var rvs []reflect.Value
 
 	for _, arg := range args {
+		if any, isAny := arg.(anyValue); isAny {
+			rvs = append(rvs, any.rv)
+			continue
+		}
+
 		rvs = append(rvs, reflect.ValueOf(arg))
 	}
 
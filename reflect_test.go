@@ -1,8 +1,11 @@
 package sabre
 
 import (
+	"context"
+	"errors"
 	"reflect"
 	"testing"
+	"time"
 )
 
 var simpleFn = func() {}
@@ -54,6 +57,16 @@ func TestValueOf(t *testing.T) {
 			v:    anyVal,
 			want: anyValue{rv: anyValRV},
 		},
+		{
+			name: "Time",
+			v:    time.Date(2020, 1, 18, 0, 0, 0, 0, time.UTC),
+			want: Time{Time: time.Date(2020, 1, 18, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			name: "Duration",
+			v:    90 * time.Second,
+			want: Duration(90 * time.Second),
+		},
 	}
 
 	for _, tt := range table {
@@ -127,6 +140,12 @@ func Test_strictFn_Invoke(t *testing.T) {
 			args: []Value{Int64(1), Int64(10)},
 			want: Int64(11),
 		},
+		{
+			name: "TimeArgs",
+			v:    func(t time.Time, d time.Duration) time.Time { return t.Add(d) },
+			args: []Value{Time{Time: time.Date(2020, 1, 18, 0, 0, 0, 0, time.UTC)}, Duration(time.Hour)},
+			want: Time{Time: time.Date(2020, 1, 18, 1, 0, 0, 0, time.UTC)},
+		},
 		{
 			name:    "ArityErrorNonVariadic",
 			v:       func() {},
@@ -172,3 +191,179 @@ func Test_strictFn_Invoke(t *testing.T) {
 		})
 	}
 }
+
+func TestMakeFunc(t *testing.T) {
+	t.Parallel()
+
+	type order struct{ Amount int }
+
+	scope := NewScope(nil)
+	_ = scope.BindGo("big-order?", func(o order) bool {
+		return o.Amount > 100
+	})
+	_ = scope.BindGo("amount", func(o order) int {
+		return o.Amount
+	})
+
+	src := `
+(def rule (fn* [o] (big-order? o)))
+(def get-amount (fn* [o] (amount o)))
+(def fail (fn* [o] (throw "rule failed")))
+`
+	if _, err := ReadEvalStr(scope, src); err != nil {
+		t.Fatalf("failed to define rules: %v", err)
+	}
+
+	resolve := func(name string) Value {
+		v, err := scope.Resolve(name)
+		if err != nil {
+			t.Fatalf("failed to resolve '%s': %v", name, err)
+		}
+		return v
+	}
+
+	t.Run("Rule", func(t *testing.T) {
+		var rule func(order) (bool, error)
+		if err := MakeFunc(scope, resolve("rule"), &rule); err != nil {
+			t.Fatalf("MakeFunc() unexpected error: %v", err)
+		}
+
+		got, err := rule(order{Amount: 1000})
+		if err != nil || !got {
+			t.Errorf("rule() got = (%t, %v), want (true, nil)", got, err)
+		}
+
+		got, err = rule(order{Amount: 10})
+		if err != nil || got {
+			t.Errorf("rule() got = (%t, %v), want (false, nil)", got, err)
+		}
+	})
+
+	t.Run("ResultConversion", func(t *testing.T) {
+		var getAmount func(order) (int32, error)
+		if err := MakeFunc(scope, resolve("get-amount"), &getAmount); err != nil {
+			t.Fatalf("MakeFunc() unexpected error: %v", err)
+		}
+
+		got, err := getAmount(order{Amount: 42})
+		if err != nil || got != 42 {
+			t.Errorf("getAmount() got = (%d, %v), want (42, nil)", got, err)
+		}
+	})
+
+	t.Run("ConversionError", func(t *testing.T) {
+		var rule func(order) (string, error)
+		if err := MakeFunc(scope, resolve("rule"), &rule); err != nil {
+			t.Fatalf("MakeFunc() unexpected error: %v", err)
+		}
+
+		if _, err := rule(order{}); err == nil {
+			t.Errorf("rule() expecting conversion error, got nil")
+		}
+	})
+
+	t.Run("EvalError", func(t *testing.T) {
+		var fail func(order) error
+		if err := MakeFunc(scope, resolve("fail"), &fail); err != nil {
+			t.Fatalf("MakeFunc() unexpected error: %v", err)
+		}
+
+		if err := fail(order{}); err == nil {
+			t.Errorf("fail() expecting error, got nil")
+		}
+	})
+
+	t.Run("Variadic", func(t *testing.T) {
+		sum := GoFunc(func(scope Scope, args []Value) (Value, error) {
+			vals, err := evalValueList(scope, args)
+			if err != nil {
+				return nil, err
+			}
+
+			total := Int64(0)
+			for _, v := range vals {
+				total += v.(Int64)
+			}
+			return total, nil
+		})
+
+		var sumFn func(...int) int
+		if err := MakeFunc(scope, sum, &sumFn); err != nil {
+			t.Fatalf("MakeFunc() unexpected error: %v", err)
+		}
+
+		if got := sumFn(1, 2, 3); got != 6 {
+			t.Errorf("sumFn() got = %d, want 6", got)
+		}
+	})
+
+	t.Run("ScopeContext", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		var rule func(order) (bool, error)
+		if err := MakeFunc(WithContext(ctx, scope), resolve("rule"), &rule); err != nil {
+			t.Fatalf("MakeFunc() unexpected error: %v", err)
+		}
+
+		if _, err := rule(order{}); !errors.Is(err, context.Canceled) {
+			t.Errorf("rule() error = %v, want context.Canceled", err)
+		}
+	})
+
+	t.Run("ContextParam", func(t *testing.T) {
+		var rule func(context.Context, order) (bool, error)
+		if err := MakeFunc(scope, resolve("rule"), &rule); err != nil {
+			t.Fatalf("MakeFunc() unexpected error: %v", err)
+		}
+
+		got, err := rule(context.Background(), order{Amount: 1000})
+		if err != nil || !got {
+			t.Errorf("rule() got = (%t, %v), want (true, nil)", got, err)
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		if _, err := rule(ctx, order{}); !errors.Is(err, context.Canceled) {
+			t.Errorf("rule() error = %v, want context.Canceled", err)
+		}
+	})
+
+	t.Run("InvalidArgs", func(t *testing.T) {
+		var f func() (int, int)
+		var g func()
+
+		if err := MakeFunc(scope, resolve("rule"), &f); err == nil {
+			t.Errorf("MakeFunc() expecting error for invalid return types")
+		}
+
+		if err := MakeFunc(scope, resolve("rule"), g); err == nil {
+			t.Errorf("MakeFunc() expecting error for non-pointer fptr")
+		}
+
+		if err := MakeFunc(scope, Int64(10), &g); err == nil {
+			t.Errorf("MakeFunc() expecting error for non-invokable value")
+		}
+	})
+}
+
+func TestValueOf_FuncArgsEvaluated(t *testing.T) {
+	t.Parallel()
+
+	scope := NewScope(nil)
+	_ = scope.Bind("x", Int64(1))
+	_ = scope.BindGo("inc", func(i Int64) Int64 { return i + 1 })
+
+	// previously, args were passed to the Go function without evaluation
+	// and (inc x) failed since 'x' was passed as a symbol.
+	got, err := ReadEvalStr(scope, "[(inc x) (inc (inc 1))]")
+	if err != nil {
+		t.Fatalf("ReadEvalStr() unexpected error: %v", err)
+	}
+
+	want := Vector{Values: []Value{Int64(2), Int64(3)}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ReadEvalStr() got = %v, want %v", got, want)
+	}
+}
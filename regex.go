@@ -0,0 +1,69 @@
+package sabre
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// Regex represents a compiled regular expression. Regex literals are
+// written as #"pattern" and use the Go regexp syntax.
+type Regex struct {
+	*regexp.Regexp
+}
+
+// Eval returns the regex itself.
+func (re Regex) Eval(_ Scope) (Value, error) { return re, nil }
+
+// String returns the regex literal. Quotes in the pattern which are not
+// escaped are escaped so that the literal reads back as the same pattern.
+func (re Regex) String() string {
+	var sb strings.Builder
+	sb.WriteString(`#"`)
+
+	escaped := false
+	for _, r := range re.Regexp.String() {
+		if r == '"' && !escaped {
+			sb.WriteRune('\\')
+		}
+		sb.WriteRune(r)
+		escaped = r == '\\' && !escaped
+	}
+
+	sb.WriteString(`"`)
+	return sb.String()
+}
+
+// readRegex reads the #"pattern" form. Unlike strings, escape sequences are
+// not processed and are passed to the regexp compiler as is except for \"
+// which allows quotes in the pattern.
+func readRegex(rd *Reader, _ rune) (Value, error) {
+	var sb strings.Builder
+
+	for {
+		r, err := rd.NextRune()
+		if err != nil {
+			return nil, errors.New("EOF while reading regex")
+		}
+
+		if r == '"' {
+			break
+		}
+
+		sb.WriteRune(r)
+		if r == '\\' {
+			r2, err := rd.NextRune()
+			if err != nil {
+				return nil, errors.New("EOF while reading regex")
+			}
+			sb.WriteRune(r2)
+		}
+	}
+
+	re, err := regexp.Compile(sb.String())
+	if err != nil {
+		return nil, err
+	}
+
+	return Regex{Regexp: re}, nil
+}
@@ -9,11 +9,17 @@ import (
 // Eval evaluates the given form against the scope and returns the result
 // of evaluation.
 func Eval(scope Scope, form Value) (Value, error) {
-	err := analyze(scope, form)
+	form, err := analyze(scope, form)
 	if err != nil {
 		return nil, err
 	}
 
+	return evalAnalyzed(scope, form)
+}
+
+// evalAnalyzed evaluates the analyzed form and wraps the error (if any) with
+// the position of the form.
+func evalAnalyzed(scope Scope, form Value) (Value, error) {
 	v, err := form.Eval(scope)
 	if err != nil {
 		if _, ok := err.(EvalError); ok {
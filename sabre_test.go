@@ -1,7 +1,12 @@
 package sabre_test
 
 import (
+	"context"
+	"fmt"
 	"reflect"
+	"regexp"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/spy16/sabre"
@@ -44,6 +49,57 @@ func TestEval(t *testing.T) {
 			src:  `(ten? 10)`,
 			want: sabre.Bool(true),
 		},
+		{
+			name: "AnonFn",
+			src:  `(#(do [%2 % %&]) 1 2 3)`,
+			want: sabre.Vector{Values: []sabre.Value{
+				sabre.Int64(2), sabre.Int64(1), &sabre.List{Values: []sabre.Value{sabre.Int64(3)}},
+			}},
+		},
+		{
+			name: "HashMap",
+			src:  `[({:a 1 "b" 2} "b") (:c {:a 1} :none) (:a {:a [1]})]`,
+			want: sabre.Vector{Values: []sabre.Value{
+				sabre.Int64(2), sabre.Keyword("none"), sabre.Vector{Values: []sabre.Value{sabre.Int64(1)}},
+			}},
+		},
+		{
+			name: "Regex",
+			src:  `[#_ ignored #?(:sabre #"a+" :default nil)]`,
+			want: sabre.Vector{Values: []sabre.Value{sabre.Regex{Regexp: regexp.MustCompile("a+")}}},
+		},
+		{
+			name: "Metadata",
+			getScope: func() sabre.Scope {
+				scope := sabre.NewScope(nil)
+				_ = scope.Bind("meta", sabre.GoFunc(func(scope sabre.Scope, args []sabre.Value) (sabre.Value, error) {
+					v, err := args[0].Eval(scope)
+					if err != nil {
+						return nil, err
+					}
+					return v.(sabre.Meta).Meta(), nil
+				}))
+				return scope
+			},
+			src: `(def ^:private v ^:final [1]) [(meta v) (meta ^String [])]`,
+			want: sabre.Vector{Values: []sabre.Value{
+				mustHashMap(sabre.Keyword("final"), sabre.Bool(true), sabre.Keyword("private"), sabre.Bool(true)),
+				mustHashMap(sabre.Keyword("tag"), sabre.Symbol{Value: "String", Position: sabre.Position{File: "<string>", Line: 1, Column: 48}}),
+			}},
+		},
+		{
+			name:     "GoForm",
+			getScope: scopeWithTake,
+			src:      `(take (go (def v 10) v))`,
+			want:     sabre.Int64(10),
+		},
+		{
+			name:     "GoFormError",
+			getScope: scopeWithTake,
+			src:      `(take (go (throw "failed")))`,
+			want:     nil,
+			wantErr:  true,
+		},
 		{
 			name:    "ReadError",
 			src:     `123 [] (`,
@@ -77,9 +133,84 @@ func TestEval(t *testing.T) {
 				t.Errorf("Eval() got = %v, want %v", got, tt.want)
 			}
 		})
+
+		for _, be := range backends {
+			t.Run(tt.name+"/"+be.name, func(t *testing.T) {
+				var scope sabre.Scope
+				if tt.getScope != nil {
+					scope = tt.getScope()
+				}
+
+				var got sabre.Value
+				prog, err := sabre.ReadCompile(scope, strings.NewReader(tt.src), sabre.WithBackend(be.backend))
+				if err == nil {
+					got, err = prog.Run(context.Background(), scope)
+				}
+
+				if (err != nil) != tt.wantErr {
+					t.Errorf("Run() error = %v, wantErr %v", err, tt.wantErr)
+					return
+				}
+				if !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("Run() got = %v, want %v", got, tt.want)
+				}
+			})
+		}
 	}
 }
 
+func TestEval_Concurrent(t *testing.T) {
+	t.Parallel()
+
+	const goroutines = 20
+
+	mod, err := sabre.NewReader(strings.NewReader(concurrentProgram)).All()
+	if err != nil {
+		t.Fatalf("failed to read program: %v", err)
+	}
+	before := fmt.Sprintf("%#v", mod)
+
+	scope := sabre.NewScope(nil)
+	_ = scope.BindGo("inc", func(i sabre.Int64) sabre.Int64 { return i + 1 })
+
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 50; j++ {
+				got, err := sabre.Eval(sabre.NewScope(scope), mod)
+				if err != nil {
+					t.Errorf("Eval() unexpected error: %v", err)
+					return
+				}
+
+				want := sabre.Vector{Values: []sabre.Value{sabre.Int64(2), sabre.Keyword("big")}}
+				if !reflect.DeepEqual(got, want) {
+					t.Errorf("Eval() got = %v, want %v", got, want)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if after := fmt.Sprintf("%#v", mod); after != before {
+		t.Errorf("Eval() modified the form:\nbefore=%s\nafter=%s", before, after)
+	}
+}
+
+const concurrentProgram = `
+(def classify (fn* [v]
+  (let* [n (inc v)]
+    (if n (do :big) :small))))
+
+(def apply-twice (fn* [f v] (f (f v))))
+
+[(apply-twice inc 0) (classify 1)]
+`
+
 const sampleProgram = `
 (def v [1 2 3])
 
@@ -89,3 +220,24 @@ const sampleProgram = `
 
 (echo pi)
 `
+
+func scopeWithTake() sabre.Scope {
+	scope := sabre.NewScope(nil)
+	_ = scope.Bind("take", sabre.GoFunc(func(scope sabre.Scope, args []sabre.Value) (sabre.Value, error) {
+		v, err := args[0].Eval(scope)
+		if err != nil {
+			return nil, err
+		}
+
+		return v.(*sabre.Chan).Take(sabre.Context(scope))
+	}))
+	return scope
+}
+
+func mustHashMap(kvs ...sabre.Value) *sabre.HashMap {
+	hm, err := sabre.NewHashMap(kvs...)
+	if err != nil {
+		panic(err)
+	}
+	return hm
+}
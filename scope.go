@@ -1,6 +1,7 @@
 package sabre
 
 import (
+	"context"
 	"fmt"
 	"sync"
 )
@@ -23,6 +24,16 @@ type MapScope struct {
 	parent   Scope
 	mu       *sync.RWMutex
 	bindings map[string]Value
+
+	// caller is set for scopes of function invocations and refers to the
+	// scope of the call site. It is used to find evaluation-level state (e.g.
+	// context) which must follow the call chain instead of the lexical chain.
+	caller Scope
+
+	// ctx is the context of the evaluation that created the scope of a
+	// function invocation. It saves walking the whole call chain each time
+	// the context is needed.
+	ctx context.Context
 }
 
 // Parent returns the parent scope of this scope.
@@ -16,7 +16,10 @@ func init() {
 		"fn*":          lambdaForm,
 		"if":           ifForm,
 		"do":           doForm,
+		"go":           goForm,
 		"def":          defForm,
+		"future":       futureForm,
+		"delay":        delayForm,
 		"let*":         letForm,
 		"throw":        throwErr,
 		"quote":        simpleQuote,
@@ -68,8 +71,10 @@ func lambdaForm(scope Scope, args []Value) (specialExpr, error) {
 		return nil, err
 	}
 
-	return func(_ Scope) (Value, error) {
-		return def, nil
+	return func(scope Scope) (Value, error) {
+		fn := def
+		fn.scope = scope
+		return fn, nil
 	}, nil
 }
 
@@ -79,7 +84,8 @@ func ifForm(scope Scope, args []Value) (specialExpr, error) {
 		return nil, err
 	}
 
-	if err := analyzeSeq(scope, Values(args)); err != nil {
+	args, err := analyzeList(scope, args)
+	if err != nil {
 		return nil, err
 	}
 
@@ -105,8 +111,8 @@ func ifForm(scope Scope, args []Value) (specialExpr, error) {
 
 // doForm implements the (do <expr>*) special form.
 func doForm(scope Scope, args []Value) (specialExpr, error) {
-	mod := Module(args)
-	if err := analyze(scope, mod); err != nil {
+	mod, err := analyze(scope, Module(args))
+	if err != nil {
 		return nil, err
 	}
 
@@ -115,6 +121,64 @@ func doForm(scope Scope, args []Value) (specialExpr, error) {
 	}, nil
 }
 
+// goForm implements the (go <expr>*) special form. Body is evaluated in a
+// new goroutine and a channel which receives the result is returned. The
+// channel is closed once the result is delivered or the evaluation fails.
+func goForm(scope Scope, args []Value) (specialExpr, error) {
+	mod, err := analyze(scope, Module(args))
+	if err != nil {
+		return nil, err
+	}
+
+	return func(scope Scope) (Value, error) {
+		ch := NewChan(1)
+
+		go func() {
+			var v Value
+			err := Context(scope).Err()
+			if err == nil {
+				v, err = mod.Eval(scope)
+			}
+
+			if err != nil {
+				ch.closeWithErr(err)
+				return
+			}
+
+			ch.rv.Send(reflect.ValueOf(&v).Elem())
+			ch.rv.Close()
+		}()
+
+		return ch, nil
+	}, nil
+}
+
+// futureForm implements the (future <expr>*) special form. Body is evaluated
+// in a new goroutine and a Future for the result is returned.
+func futureForm(scope Scope, args []Value) (specialExpr, error) {
+	mod, err := analyze(scope, Module(args))
+	if err != nil {
+		return nil, err
+	}
+
+	return func(scope Scope) (Value, error) {
+		return NewFuture(scope, mod), nil
+	}, nil
+}
+
+// delayForm implements the (delay <expr>*) special form. Body is evaluated
+// only when the returned Delay is dereferenced for the first time.
+func delayForm(scope Scope, args []Value) (specialExpr, error) {
+	mod, err := analyze(scope, Module(args))
+	if err != nil {
+		return nil, err
+	}
+
+	return func(scope Scope) (Value, error) {
+		return NewDelay(scope, mod), nil
+	}, nil
+}
+
 // defForm implements (def symbol value).
 func defForm(scope Scope, args []Value) (specialExpr, error) {
 	if err := verifyArgCount([]int{2}, args); err != nil {
@@ -127,17 +191,18 @@ func defForm(scope Scope, args []Value) (specialExpr, error) {
 			reflect.TypeOf(args[0]))
 	}
 
-	if err := analyze(scope, args[1]); err != nil {
+	valForm, err := analyze(scope, args[1])
+	if err != nil {
 		return nil, err
 	}
 
 	return func(scope Scope) (Value, error) {
-		v, err := args[1].Eval(scope)
+		v, err := valForm.Eval(scope)
 		if err != nil {
 			return nil, err
 		}
 
-		if err := rootScope(scope).Bind(sym.String(), v); err != nil {
+		if err := bindDef(scope, sym, v); err != nil {
 			return nil, err
 		}
 
@@ -145,6 +210,17 @@ func defForm(scope Scope, args []Value) (specialExpr, error) {
 	}, nil
 }
 
+// bindDef binds the value to the symbol in the root scope. Metadata of the
+// symbol (e.g., docstring) is attached to the value if the value supports
+// metadata.
+func bindDef(scope Scope, sym Symbol, v Value) error {
+	if wm, ok := v.(WithMeta); ok && sym.meta != nil {
+		v = wm.WithMeta(mergeMeta(wm.Meta(), sym.meta))
+	}
+
+	return rootScope(scope).Bind(sym.String(), v)
+}
+
 // letForm implements the (let [binding*] expr*) form. expr are evaluated
 // with given local bindings.
 func letForm(scope Scope, args []Value) (specialExpr, error) {
@@ -174,12 +250,22 @@ func letForm(scope Scope, args []Value) (specialExpr, error) {
 			)
 		}
 
+		expr, err := analyze(scope, vec.Values[i+1])
+		if err != nil {
+			return nil, err
+		}
+
 		bindings = append(bindings, binding{
 			Name: sym.Value,
-			Expr: vec.Values[i+1],
+			Expr: expr,
 		})
 	}
 
+	body, err := analyze(scope, Module(args[1:]))
+	if err != nil {
+		return nil, err
+	}
+
 	return func(scope Scope) (Value, error) {
 		letScope := NewScope(scope)
 		for _, b := range bindings {
@@ -191,14 +277,15 @@ func letForm(scope Scope, args []Value) (specialExpr, error) {
 			_ = letScope.Bind(b.Name, v)
 		}
 
-		return Module(args[1:]).Eval(letScope)
+		return body.Eval(letScope)
 	}, nil
 }
 
 // throwErr signals an error. Stringified versions of args will be
 // concatenated and used as error message.
 func throwErr(scope Scope, args []Value) (specialExpr, error) {
-	if err := analyzeSeq(scope, Values(args)); err != nil {
+	args, err := analyzeList(scope, args)
+	if err != nil {
 		return nil, err
 	}
 
@@ -250,8 +337,8 @@ func makeFn(scope Scope, spec []Value) (*Fn, error) {
 		return nil, fmt.Errorf("insufficient args (%d) for 'fn'", len(spec))
 	}
 
-	body := Module(spec[1:])
-	if err := analyze(scope, body); err != nil {
+	body, err := analyze(scope, Module(spec[1:]))
+	if err != nil {
 		return nil, err
 	}
 
@@ -306,6 +393,10 @@ func recursiveQuote(scope Scope, f Value) (Value, error) {
 		quoted, err := quoteList(scope, v.Values)
 		return Vector{Values: quoted}, err
 
+	case *HashMap:
+		quoted, err := quoteList(scope, v.kvs())
+		return v.withEntries(quoted), err
+
 	default:
 		return f, nil
 	}
@@ -339,21 +430,20 @@ func quoteList(scope Scope, forms []Value) ([]Value, error) {
 }
 
 func stringFromVals(vals []Value) String {
-	argc := len(vals)
-	switch argc {
-	case 0:
-		return String("")
+	var sb strings.Builder
+	for _, v := range vals {
+		switch val := v.(type) {
+		case String:
+			sb.WriteString(string(val))
 
-	case 1:
-		return String(strings.Trim(vals[0].String(), "\""))
+		case Character:
+			sb.WriteRune(rune(val))
 
-	default:
-		var sb strings.Builder
-		for _, v := range vals {
-			sb.WriteString(strings.Trim(v.String(), "\""))
+		default:
+			sb.WriteString(v.String())
 		}
-		return String(sb.String())
 	}
+	return String(sb.String())
 }
 
 func verifyArgCount(arities []int, args []Value) error {
@@ -382,45 +472,72 @@ func verifyArgCount(arities []int, args []Value) error {
 	return nil
 }
 
-func analyze(scope Scope, v Value) (err error) {
-	switch val := v.(type) {
+// analyze returns an analyzed copy of the form in which all special forms
+// are parsed and bound to their implementations. The form itself is never
+// modified and hence a form can be analyzed and evaluated concurrently.
+func analyze(scope Scope, form Value) (Value, error) {
+	var res Value
+	var err error
+
+	switch v := form.(type) {
 	case Module:
-		err = analyzeSeq(scope, Values(val))
+		var vals []Value
+		vals, err = analyzeList(scope, v)
+		res = Module(vals)
 
 	case *List:
-		err = val.parseSpecial(scope)
+		res, err = v.analyze(scope)
+
+	case Vector:
+		var vals []Value
+		vals, err = analyzeList(scope, v.Values)
+		res = Vector{Values: vals, Position: v.Position, meta: v.meta}
 
-	case Seq:
-		err = analyzeSeq(scope, val)
+	case Set:
+		var vals []Value
+		vals, err = analyzeList(scope, v.Values)
+		res = Set{Values: vals, Position: v.Position, meta: v.meta}
+
+	case *HashMap:
+		var kvs []Value
+		kvs, err = analyzeList(scope, v.kvs())
+		res = v.withEntries(kvs)
 
+	default:
+		return form, nil
 	}
 
 	if err != nil {
-		return EvalError{
+		if _, ok := err.(EvalError); ok {
+			return nil, err
+		}
+
+		return nil, EvalError{
 			Cause:    err,
-			Position: getPosition(v),
-			Form:     v,
+			Position: getPosition(form),
+			Form:     form,
 		}
 	}
 
-	return nil
+	return res, nil
 }
 
-func analyzeSeq(scope Scope, seq Seq) error {
-	for seq != nil {
-		item := seq.First()
-		if item == nil {
-			break
-		}
+func analyzeList(scope Scope, forms []Value) ([]Value, error) {
+	if forms == nil {
+		return nil, nil
+	}
 
-		if err := analyze(scope, item); err != nil {
-			return err
+	res := make([]Value, len(forms))
+	for i, form := range forms {
+		v, err := analyze(scope, form)
+		if err != nil {
+			return nil, err
 		}
 
-		seq = seq.Next()
+		res[i] = v
 	}
 
-	return nil
+	return res, nil
 }
 
 type specialForm func(scope Scope, args []Value) (specialExpr, error)
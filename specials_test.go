@@ -54,5 +54,21 @@ func Test_lambdaForm(t *testing.T) {
 				return
 			}
 		})
+
+		t.Run(tt.name+"/Closure", func(t *testing.T) {
+			cc := &closureCompiler{}
+			_, err := cc.compileLambda(tt.args, &lexScope{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("compileLambda() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+
+		t.Run(tt.name+"/Bytecode", func(t *testing.T) {
+			bc := &bcCompiler{proto: &funcProto{name: "<main>"}}
+			err := bc.compileLambda(tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("compileLambda() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
 	}
 }
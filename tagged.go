@@ -0,0 +1,69 @@
+package sabre
+
+import (
+	"fmt"
+)
+
+// TagHandler converts the form following a tag (e.g., the string in
+// #inst "2020-01-18T00:00:00Z") into the value the tagged literal
+// represents.
+type TagHandler func(form Value) (Value, error)
+
+// Tagged represents a value which is printed as a tagged literal. Values
+// returned by tag handlers can implement Tagged and use TaggedString in
+// their String() so that they read back as the same value.
+type Tagged interface {
+	Value
+
+	// Tag returns the tag (without '#') and the form representing the
+	// value.
+	Tag() (tag string, form Value)
+}
+
+// TaggedString returns the tagged literal representation of the value
+// (i.e., #tag form).
+func TaggedString(v Tagged) string {
+	tag, form := v.Tag()
+	return fmt.Sprintf("#%s %s", tag, form)
+}
+
+// SetTag sets the handler for the tagged literals with the given tag (e.g.,
+// "money" for #money [10 "USD"]). Overwrites if a handler is already set.
+// If the handler is nil, handler for the tag is removed.
+func (rd *Reader) SetTag(tag string, handler TagHandler) {
+	if handler == nil {
+		delete(rd.tags, tag)
+		return
+	}
+
+	rd.tags[tag] = handler
+}
+
+// readTagged reads the #tag form and returns the result of the handler
+// set for the tag.
+func readTagged(rd *Reader, init rune) (Value, error) {
+	tag, err := readToken(rd, init)
+	if err != nil {
+		return nil, err
+	}
+
+	handler, found := rd.tags[tag]
+	if !found {
+		return nil, fmt.Errorf("no reader function for tag '%s'", tag)
+	}
+
+	form, err := readNextForm(rd, "tagged literal")
+	if err != nil {
+		return nil, err
+	}
+
+	return handler(form)
+}
+
+func defaultTagTable() map[string]TagHandler {
+	return map[string]TagHandler{
+		"inst":     readInst,
+		"uuid":     readUUID,
+		"duration": readDuration,
+	}
+}
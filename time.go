@@ -0,0 +1,85 @@
+package sabre
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// instLayouts are the layouts accepted by #inst. Parts that are not
+// present default to the start of the period in UTC.
+var instLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04",
+	"2006-01-02",
+	"2006-01",
+	"2006",
+}
+
+// Time represents an instant in time. Time literals are written as
+// #inst "2020-01-18T00:00:00Z" (RFC 3339).
+type Time struct {
+	time.Time
+}
+
+// Eval returns the time itself.
+func (t Time) Eval(_ Scope) (Value, error) { return t, nil }
+
+func (t Time) String() string { return TaggedString(t) }
+
+// Tag returns the inst tag and the time formatted using RFC 3339.
+func (t Time) Tag() (string, Value) {
+	return "inst", String(t.Format(time.RFC3339Nano))
+}
+
+// ParseInst parses the timestamp in the formats accepted by #inst (i.e.,
+// RFC 3339 or a prefix of it).
+func ParseInst(s string) (Time, error) {
+	for _, layout := range instLayouts {
+		t, err := time.Parse(layout, s)
+		if err == nil {
+			return Time{Time: t}, nil
+		}
+	}
+
+	return Time{}, fmt.Errorf("invalid timestamp '%s'", s)
+}
+
+func readInst(form Value) (Value, error) {
+	s, isString := form.(String)
+	if !isString {
+		return nil, fmt.Errorf("#inst requires a string, not '%s'", reflect.TypeOf(form))
+	}
+
+	return ParseInst(string(s))
+}
+
+// Duration represents the elapsed time between two instants. Duration
+// literals are written as #duration "1h30m" (See time.ParseDuration).
+type Duration time.Duration
+
+// Eval returns the duration itself.
+func (d Duration) Eval(_ Scope) (Value, error) { return d, nil }
+
+func (d Duration) String() string { return TaggedString(d) }
+
+// Tag returns the duration tag and the duration formatted as Go duration
+// string (e.g., "1h30m0s").
+func (d Duration) Tag() (string, Value) {
+	return "duration", String(time.Duration(d).String())
+}
+
+func readDuration(form Value) (Value, error) {
+	s, isString := form.(String)
+	if !isString {
+		return nil, fmt.Errorf("#duration requires a string, not '%s'", reflect.TypeOf(form))
+	}
+
+	d, err := time.ParseDuration(string(s))
+	if err != nil {
+		return nil, err
+	}
+
+	return Duration(d), nil
+}
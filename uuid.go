@@ -0,0 +1,50 @@
+package sabre
+
+import (
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// UUID represents a universally unique identifier. UUID literals are written
+// as #uuid "f81d4fae-7dec-11d0-a765-00a0c91e6bf6".
+type UUID [16]byte
+
+// ParseUUID parses the canonical textual representation of a UUID (i.e.,
+// 32 hexadecimal digits in 8-4-4-4-12 groups).
+func ParseUUID(s string) (UUID, error) {
+	var u UUID
+
+	parts := strings.Split(s, "-")
+	if len(s) != 36 || len(parts) != 5 || len(parts[0]) != 8 || len(parts[1]) != 4 ||
+		len(parts[2]) != 4 || len(parts[3]) != 4 {
+		return u, fmt.Errorf("invalid uuid '%s'", s)
+	}
+
+	if _, err := hex.Decode(u[:], []byte(strings.Join(parts, ""))); err != nil {
+		return u, fmt.Errorf("invalid uuid '%s'", s)
+	}
+
+	return u, nil
+}
+
+// Eval returns the UUID itself.
+func (u UUID) Eval(_ Scope) (Value, error) { return u, nil }
+
+func (u UUID) String() string { return TaggedString(u) }
+
+// Tag returns the uuid tag and the canonical textual representation.
+func (u UUID) Tag() (string, Value) {
+	h := hex.EncodeToString(u[:])
+	return "uuid", String(h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:])
+}
+
+func readUUID(form Value) (Value, error) {
+	s, isString := form.(String)
+	if !isString {
+		return nil, fmt.Errorf("#uuid requires a string, not '%s'", reflect.TypeOf(form))
+	}
+
+	return ParseUUID(string(s))
+}
@@ -0,0 +1,479 @@
+package sabre
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// maxFrames is the maximum depth of nested (non-tail) calls in the VM.
+const maxFrames = 1 << 20
+
+// vmMethod is a function prototype along with the captured upvalues. It is
+// used as Fn.Func of the functions created by bytecode programs.
+type vmMethod struct {
+	proto  *funcProto
+	upvals []Value
+	global Scope
+}
+
+// Invoke executes the method with already evaluated args.
+func (vmm *vmMethod) Invoke(scope Scope, args ...Value) (Value, error) {
+	return vmm.call(scope, args)
+}
+
+// call executes the method in a new VM. 'caller' is the scope of the call
+// site.
+func (vmm *vmMethod) call(caller Scope, args []Value) (Value, error) {
+	if !vmm.matchArity(len(args)) {
+		return nil, fmt.Errorf("wrong number of args (%d) to '%s'",
+			len(args), vmm.proto.name)
+	}
+
+	m := &vm{
+		caller: caller,
+		entry:  vmm.global,
+		stack:  make([]Value, 0, 16+len(args)),
+	}
+	m.ctx = Context(m.dynamic())
+	m.stack = append(append(m.stack, nil), args...)
+
+	if err := m.enter(vmm, 1, len(args)); err != nil {
+		return nil, err
+	}
+
+	return m.run()
+}
+
+func (vmm *vmMethod) matchArity(argc int) bool {
+	if vmm.proto.variadic {
+		return argc >= len(vmm.proto.args)-1
+	}
+
+	return argc == len(vmm.proto.args)
+}
+
+// vmThunk adapts a method that takes no arguments to the Value interface so
+// that it can be evaluated later (e.g., by Future or Delay).
+type vmThunk struct {
+	method *vmMethod
+}
+
+func (thunk vmThunk) Eval(scope Scope) (Value, error) {
+	return thunk.method.call(scope, nil)
+}
+
+func (thunk vmThunk) String() string {
+	return "<compiled>"
+}
+
+// vm executes the bytecode. Every invocation of a compiled function from
+// outside the VM (e.g., from Go) runs in a new vm.
+type vm struct {
+	stack  []Value
+	frames []vmFrame
+
+	// caller is the scope from which the VM was entered and entry is the
+	// global scope of the first method. These are used for finding state
+	// in the dynamic chain (See walkDynamic).
+	caller Scope
+	entry  Scope
+
+	// ctx is the context of the evaluation and is checked on every call so
+	// that long-running or recursive functions can be interrupted.
+	ctx context.Context
+}
+
+type vmFrame struct {
+	method *vmMethod
+	ip     int
+	base   int
+
+	// tail is the site of the tail call that replaced the calling frame
+	// with this one, if any.
+	tail *site
+}
+
+// dynamic returns the next scope in the dynamic chain for all the frames
+// in the VM.
+func (m *vm) dynamic() Scope {
+	if m.caller != nil {
+		return m.caller
+	}
+
+	return m.entry
+}
+
+// enter pushes a new frame for the method. Callee is at stack[base-1] and
+// 'argc' args follow.
+func (m *vm) enter(vmm *vmMethod, base, argc int) error {
+	if len(m.frames) >= maxFrames {
+		return errors.New("stack overflow")
+	}
+
+	proto := vmm.proto
+	if proto.variadic {
+		idx := len(proto.args) - 1
+
+		var rest []Value
+		if argc > idx {
+			rest = append(rest, m.stack[base+idx:base+argc]...)
+		}
+
+		m.stack = append(m.stack[:base+idx], &List{Values: rest})
+	}
+
+	for len(m.stack) < base+proto.nlocals {
+		m.stack = append(m.stack, nil)
+	}
+
+	m.frames = append(m.frames, vmFrame{method: vmm, base: base})
+	return nil
+}
+
+// run executes the frames until the outermost one returns. Errors are
+// annotated with the call sites of all the frames they unwind through
+// (only the last one in case of a chain of tail calls).
+func (m *vm) run() (Value, error) {
+	v, err := m.exec()
+	if err != nil {
+		for i := len(m.frames) - 1; i >= 0; i-- {
+			fr := m.frames[i]
+			if fr.tail != nil {
+				err = fr.tail.wrap(err)
+			}
+
+			if i > 0 {
+				caller := m.frames[i-1]
+				in := caller.method.proto.code[caller.ip-1]
+				err = caller.method.proto.sites[in.arg()].wrap(err)
+			}
+		}
+	}
+
+	return v, err
+}
+
+func (m *vm) exec() (Value, error) {
+	for {
+		fr := &m.frames[len(m.frames)-1]
+		proto := fr.method.proto
+		in := proto.code[fr.ip]
+		fr.ip++
+
+		switch in.op() {
+		case opConst:
+			m.push(proto.consts[in.arg()])
+
+		case opLoadLocal:
+			m.push(m.stack[fr.base+in.arg()])
+
+		case opStoreLocal:
+			m.stack[fr.base+in.arg()] = m.pop()
+
+		case opLoadUpval:
+			m.push(fr.method.upvals[in.arg()])
+
+		case opLoadGlobal:
+			sym := proto.consts[in.arg()].(Symbol)
+			v, err := fr.method.global.Resolve(sym.Value)
+			if err != nil {
+				return nil, wrapEvalErr(sym, err)
+			}
+			m.push(v)
+
+		case opDef:
+			sym := proto.consts[in.arg()].(Symbol)
+			if err := bindDef(fr.method.global, sym, m.pop()); err != nil {
+				return nil, wrapEvalErr(sym, err)
+			}
+			m.push(sym)
+
+		case opPop:
+			m.pop()
+
+		case opJump:
+			fr.ip = in.arg()
+
+		case opJumpIfFalse:
+			if !isTruthy(m.pop()) {
+				fr.ip = in.arg()
+			}
+
+		case opMacroCheck:
+			s := proto.sites[in.arg()]
+			macro, isMacro := m.stack[len(m.stack)-1].(MultiFn)
+			if isMacro && macro.IsMacro {
+				v, err := macro.Invoke(m.view(s), s.args...)
+				if err != nil {
+					return nil, s.wrap(err)
+				}
+
+				m.stack[len(m.stack)-1] = v
+				fr.ip = s.end
+			}
+
+		case opCall, opTailCall:
+			s := proto.sites[in.arg()]
+			if err := m.call(s, in.op() == opTailCall); err != nil {
+				return nil, s.wrap(err)
+			}
+
+		case opReturn:
+			v := m.pop()
+			m.stack = m.stack[:fr.base-1]
+			m.frames = m.frames[:len(m.frames)-1]
+
+			if len(m.frames) == 0 {
+				return v, nil
+			}
+			m.push(v)
+
+		case opClosure:
+			m.push(m.closure(fr, proto.fns[in.arg()]))
+
+		case opList:
+			m.push(&List{Values: m.popN(in.arg())})
+
+		case opVector:
+			m.push(Vector{Values: m.popN(in.arg())})
+
+		case opSet:
+			m.push(Set{Values: uniq(m.popN(in.arg()))})
+
+		case opQuotedSet:
+			m.push(Set{Values: m.popN(in.arg())})
+
+		case opMap:
+			s := proto.sites[in.arg()]
+			hm, err := NewHashMap(m.popN(len(s.args))...)
+			if err != nil {
+				return nil, s.wrap(err)
+			}
+			m.push(hm)
+
+		case opMeta:
+			v := m.pop().(WithMeta)
+			m.push(v.WithMeta(proto.consts[in.arg()].(*HashMap)))
+
+		case opThrow:
+			s := proto.sites[in.arg()]
+			err := errors.New(string(stringFromVals(m.popN(len(s.args)))))
+			return nil, s.wrap(err)
+
+		case opGo:
+			m.push(m.spawn(m.pop().(MultiFn)))
+
+		case opFuture:
+			thunk := vmThunk{method: m.pop().(MultiFn).Methods[0].Func.(*vmMethod)}
+			m.push(NewFuture(m.dynamic(), thunk))
+
+		case opDelay:
+			thunk := vmThunk{method: m.pop().(MultiFn).Methods[0].Func.(*vmMethod)}
+			m.push(NewDelay(m.dynamic(), thunk))
+
+		case opEval:
+			s := proto.sites[in.arg()]
+			v, err := s.form.Eval(m.view(s))
+			if err != nil {
+				return nil, s.wrap(err)
+			}
+			m.push(v)
+
+		default:
+			return nil, fmt.Errorf("invalid opcode: %d", in.op())
+		}
+	}
+}
+
+// call invokes the callee on the stack with the args that follow. Calls to
+// compiled methods push a new frame (or replace the current frame in case
+// of tail calls) while other invokables are called directly.
+func (m *vm) call(s *site, tail bool) error {
+	if err := m.ctx.Err(); err != nil {
+		return err
+	}
+
+	argc := len(s.args)
+	calleeIdx := len(m.stack) - argc - 1
+	args := m.stack[calleeIdx+1:]
+
+	var v Value
+	var err error
+
+	switch fn := m.stack[calleeIdx].(type) {
+	case MultiFn:
+		var method Fn
+		method, err = fn.selectMethod(args)
+		if err != nil {
+			return err
+		}
+
+		if vmm, ok := method.Func.(*vmMethod); ok {
+			if tail {
+				base := m.frames[len(m.frames)-1].base
+				copy(m.stack[base-1:], m.stack[calleeIdx:])
+				m.stack = m.stack[:base+argc]
+				m.frames = m.frames[:len(m.frames)-1]
+				calleeIdx = base - 1
+			}
+
+			if err := m.enter(vmm, calleeIdx+1, argc); err != nil {
+				return err
+			}
+
+			if tail {
+				m.frames[len(m.frames)-1].tail = s
+			}
+			return nil
+		}
+
+		view := m.view(s)
+		v, err = method.invoke(fn.bodyScope(view), view, append([]Value(nil), args...))
+
+	case Invokable:
+		v, err = applyInPlace(m.view(s), fn, append([]Value(nil), args...))
+
+	default:
+		err = fmt.Errorf("cannot invoke value of type '%s'", reflect.TypeOf(fn))
+	}
+
+	if err != nil {
+		return err
+	}
+
+	m.stack = append(m.stack[:calleeIdx], v)
+	return nil
+}
+
+// closure creates a function from the definition capturing the upvalues
+// from the current frame.
+func (m *vm) closure(fr *vmFrame, def *fnDef) MultiFn {
+	methods := make([]Fn, len(def.methods))
+	for i, proto := range def.methods {
+		upvals := make([]Value, len(proto.upvals))
+		for j, up := range proto.upvals {
+			if up.fromLocal {
+				upvals[j] = m.stack[fr.base+up.index]
+			} else {
+				upvals[j] = fr.method.upvals[up.index]
+			}
+		}
+
+		methods[i] = Fn{
+			Args:     proto.args,
+			Variadic: proto.variadic,
+			Func: &vmMethod{
+				proto:  proto,
+				upvals: upvals,
+				global: fr.method.global,
+			},
+		}
+	}
+
+	return MultiFn{Name: def.name, Methods: methods}
+}
+
+// spawn executes the function in a new goroutine and returns a channel
+// which receives the result (See goForm).
+func (m *vm) spawn(fn MultiFn) *Chan {
+	method := fn.Methods[0].Func.(*vmMethod)
+	dynamic := m.dynamic()
+	ch := NewChan(1)
+
+	go func() {
+		var v Value
+		err := Context(dynamic).Err()
+		if err == nil {
+			v, err = method.call(dynamic, nil)
+		}
+
+		if err != nil {
+			ch.closeWithErr(err)
+			return
+		}
+
+		ch.rv.Send(reflect.ValueOf(&v).Elem())
+		ch.rv.Close()
+	}()
+
+	return ch
+}
+
+func (m *vm) view(s *site) vmScope {
+	return vmScope{vm: m, frame: len(m.frames) - 1, site: s}
+}
+
+func (m *vm) push(v Value) {
+	m.stack = append(m.stack, v)
+}
+
+func (m *vm) pop() Value {
+	v := m.stack[len(m.stack)-1]
+	m.stack = m.stack[:len(m.stack)-1]
+	return v
+}
+
+// popN pops 'n' values and returns them in the order they were pushed.
+// Returns nil if 'n' is 0.
+func (m *vm) popN(n int) []Value {
+	if n == 0 {
+		return nil
+	}
+
+	vals := append([]Value(nil), m.stack[len(m.stack)-n:]...)
+	m.stack = m.stack[:len(m.stack)-n]
+	return vals
+}
+
+// vmScope exposes the local bindings and upvalues visible at a site as a
+// Scope. It is passed to values that are not compiled (e.g., Go functions)
+// and remains valid only until the function at the site returns.
+type vmScope struct {
+	vm    *vm
+	frame int
+	site  *site
+}
+
+func (vs vmScope) Parent() Scope {
+	return vs.vm.frames[vs.frame].method.global
+}
+
+func (vs vmScope) Bind(symbol string, v Value) error {
+	fr := vs.vm.frames[vs.frame]
+
+	if slot, found := vs.findLocal(symbol); found {
+		vs.vm.stack[fr.base+slot] = v
+		return nil
+	}
+
+	return fr.method.global.Bind(symbol, v)
+}
+
+func (vs vmScope) Resolve(symbol string) (Value, error) {
+	fr := vs.vm.frames[vs.frame]
+
+	if slot, found := vs.findLocal(symbol); found {
+		return vs.vm.stack[fr.base+slot], nil
+	}
+
+	for i, up := range fr.method.proto.upvals {
+		if up.name == symbol {
+			return fr.method.upvals[i], nil
+		}
+	}
+
+	return fr.method.global.Resolve(symbol)
+}
+
+func (vs vmScope) findLocal(symbol string) (int, bool) {
+	locals := vs.site.locals
+	for i := len(locals) - 1; i >= 0; i-- {
+		if locals[i].name == symbol {
+			return locals[i].slot, true
+		}
+	}
+
+	return 0, false
+}
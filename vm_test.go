@@ -0,0 +1,54 @@
+package sabre
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestVM_TailCall(t *testing.T) {
+	t.Parallel()
+
+	src := `
+(def count-down (fn* [n]
+  (if (zero? n)
+    :done
+    (count-down (dec n)))))
+
+(count-down n)
+`
+
+	// calls more than maxFrames deep would fail without tail calls.
+	scope := NewScope(nil)
+	_ = scope.Bind("n", Int64(maxFrames+1))
+	_ = scope.Bind("zero?", GoFunc(func(scope Scope, args []Value) (Value, error) {
+		v, err := args[0].Eval(scope)
+		return Bool(v == Int64(0)), err
+	}))
+	_ = scope.Bind("dec", GoFunc(func(scope Scope, args []Value) (Value, error) {
+		v, err := args[0].Eval(scope)
+		if err != nil {
+			return nil, err
+		}
+		return v.(Int64) - 1, nil
+	}))
+
+	form, err := NewReader(strings.NewReader(src)).All()
+	if err != nil {
+		t.Fatalf("All() unexpected error: %v", err)
+	}
+
+	proto, err := compileBytecode(scope, form)
+	if err != nil {
+		t.Fatalf("compileBytecode() unexpected error: %v", err)
+	}
+
+	m := &vmMethod{proto: proto, global: scope}
+	got, err := m.call(nil, nil)
+	if err != nil {
+		t.Fatalf("call() unexpected error: %v", err)
+	}
+
+	if got != (Keyword("done")) {
+		t.Errorf("call() got = %v, want :done", got)
+	}
+}